@@ -4,7 +4,12 @@ go 1.14
 
 require (
 	github.com/antlr/antlr4 v0.0.0-20210311224141-c2f104cd0810 // indirect
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/go-sql-driver/mysql v1.5.0
+	github.com/golang/protobuf v1.4.2
 	github.com/iancoleman/strcase v0.1.3 // indirect
 	github.com/tal-tech/go-zero v1.1.5
 	go.uber.org/automaxprocs v1.4.0 // indirect
+	google.golang.org/grpc v1.29.1
+	google.golang.org/protobuf v1.25.0
 )
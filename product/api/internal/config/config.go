@@ -0,0 +1,60 @@
+package config
+
+import (
+	"time"
+
+	"github.com/tal-tech/go-zero/rest"
+)
+
+type Config struct {
+	rest.RestConf
+	Mysql struct {
+		DataSource string
+	}
+	// Auth signs the tokens of the admin routes
+	Auth struct {
+		AccessSecret string
+		AccessExpire int64 `json:",default=86400"` // seconds
+	}
+	// Loader batches the product and SKU lookups by ID arriving within Wait
+	Loader struct {
+		Wait     time.Duration `json:",default=2ms"`
+		MaxBatch int           `json:",default=100"`
+	}
+	// DetailCache keeps the portal product details in process for about
+	// Expire, at most Limit of them, and the products not found for about
+	// NotFoundExpire. Changes made by other processes, the rpc service and
+	// other api instances, show once the details expire.
+	DetailCache struct {
+		Expire         time.Duration `json:",default=1m"`
+		NotFoundExpire time.Duration `json:",default=10s"`
+		Limit          int           `json:",default=10000"`
+	}
+	// ProductFilter tells the product IDs not existing without a lookup. It
+	// is built on start, and rebuilt every RefreshInterval to learn of the
+	// products inserted by other processes.
+	ProductFilter struct {
+		Capacity        int           `json:",default=1000000"`
+		FalsePositive   float64       `json:",default=0.01"`
+		RefreshInterval time.Duration `json:",default=1m"`
+	}
+	Pricing struct {
+		ReductionMode string `json:",default=best,options=best|repeat"`
+		// WindowInterval between starting and ending due promotion windows
+		WindowInterval time.Duration `json:",default=10s"`
+	}
+	LowStock struct {
+		// Interval between scans, 0 to disable the scanner
+		Interval       time.Duration `json:",default=1m"`
+		Webhook        string        `json:",optional"`
+		WebhookTimeout time.Duration `json:",default=3s"`
+	}
+	Reservation struct {
+		Ttl            time.Duration `json:",default=30m"`
+		MaxTtl         time.Duration `json:",default=24h"`
+		ExpireInterval time.Duration `json:",default=10s"`
+	}
+	Publish struct {
+		ScheduleInterval time.Duration `json:",default=10s"`
+	}
+}
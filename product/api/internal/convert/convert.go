@@ -0,0 +1,311 @@
+// Package convert maps the Pms* models into the API types of product.api,
+// so handlers don't copy them field by field.
+//
+// Columns map to the API field whose json name is the camel case of the
+// column, unless renamed, and nulls become zero values: prices are strings,
+// "" when NULL, and times are unix seconds, 0 when NULL. Every column has to
+// be mapped or omitted on purpose, and every API field filled: the mappings
+// are built when the package is loaded and Check tells what's wrong with
+// them, so a column added to a model but not to its API type fails the
+// service at startup instead of going missing silently.
+package convert
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"malltmp/product/api/internal/types"
+	"malltmp/product/model"
+)
+
+type (
+	// mapping copies the columns of a model struct into an API struct.
+	// A mapping failing to build has err set, and copies nothing.
+	mapping struct {
+		src    reflect.Type
+		dst    reflect.Type
+		fields []fieldMapping
+		err    error
+	}
+
+	fieldMapping struct {
+		src     int
+		dst     int
+		convert func(reflect.Value) reflect.Value
+	}
+)
+
+var (
+	productMapping = newMapping(model.PmsProduct{}, types.Product{}, map[string]string{
+		"feight_template_id": "freightTemplateId",
+		"recommand_status":   "recommendStatus",
+	}, "previous_promotion_type")
+	productSummaryMapping = newMapping(model.PmsProduct{}, types.ProductSummary{}, map[string]string{
+		"recommand_status": "recommendStatus",
+	}, "keywords", "note", "service_ids", "album_pics", "description", "detail_title", "detail_desc",
+		"detail_html", "detail_mobile_html", "feight_template_id", "product_attribute_category_id",
+		"publish_status", "verify_status", "delete_status", "use_point_limit", "promotion_per_limit", "low_stock",
+		"weight", "promotion_start_time", "promotion_end_time", "window_promotion_type",
+		"previous_promotion_type", "update_time")
+	skuMapping            = newMapping(model.PmsSkuStock{}, types.Sku{}, nil)
+	brandMapping          = newMapping(model.PmsBrand{}, types.Brand{}, nil)
+	attributeMapping      = newMapping(model.PmsProductAttribute{}, types.Attribute{}, nil)
+	attributeValueMapping = newMapping(model.PmsProductAttributeValue{}, types.AttributeValue{}, nil)
+	ladderMapping         = newMapping(model.PmsProductLadder{}, types.Ladder{}, nil)
+	fullReductionMapping  = newMapping(model.PmsProductFullReduction{}, types.FullReduction{}, nil)
+
+	mappings = []*mapping{productMapping, productSummaryMapping, skuMapping, brandMapping, attributeMapping,
+		attributeValueMapping, ladderMapping, fullReductionMapping}
+
+	moneyType     = reflect.TypeOf(model.Money(0))
+	nullMoneyType = reflect.TypeOf(model.NullMoney{})
+	nullInt64Type = reflect.TypeOf(sql.NullInt64{})
+	nullFloatType = reflect.TypeOf(sql.NullFloat64{})
+	nullStrType   = reflect.TypeOf(sql.NullString{})
+	nullTimeType  = reflect.TypeOf(sql.NullTime{})
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// Check returns the error of the first mapping that failed to build.
+func Check() error {
+	for _, m := range mappings {
+		if m.err != nil {
+			return m.err
+		}
+	}
+
+	return nil
+}
+
+func Product(p *model.PmsProduct) types.Product {
+	var out types.Product
+	productMapping.apply(p, &out)
+	return out
+}
+
+func ProductSummaries(products []*model.PmsProduct) []types.ProductSummary {
+	out := make([]types.ProductSummary, len(products))
+	for i, p := range products {
+		productSummaryMapping.apply(p, &out[i])
+	}
+	return out
+}
+
+// StatusFilter turns a status filter of a request, -1 for any, into the
+// status of a model query.
+func StatusFilter(status int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: status, Valid: status >= 0}
+}
+
+func Sku(s *model.PmsSkuStock) types.Sku {
+	var out types.Sku
+	skuMapping.apply(s, &out)
+	return out
+}
+
+func Skus(skus []*model.PmsSkuStock) []types.Sku {
+	out := make([]types.Sku, len(skus))
+	for i, s := range skus {
+		skuMapping.apply(s, &out[i])
+	}
+	return out
+}
+
+func Brand(b *model.PmsBrand) types.Brand {
+	var out types.Brand
+	brandMapping.apply(b, &out)
+	return out
+}
+
+func Attribute(a *model.PmsProductAttribute) types.Attribute {
+	var out types.Attribute
+	attributeMapping.apply(a, &out)
+	return out
+}
+
+func AttributeValues(values []*model.PmsProductAttributeValue) []types.AttributeValue {
+	out := make([]types.AttributeValue, len(values))
+	for i, v := range values {
+		attributeValueMapping.apply(v, &out[i])
+	}
+	return out
+}
+
+func Ladders(ladders []*model.PmsProductLadder) []types.Ladder {
+	out := make([]types.Ladder, len(ladders))
+	for i, l := range ladders {
+		ladderMapping.apply(l, &out[i])
+	}
+	return out
+}
+
+func FullReductions(reductions []*model.PmsProductFullReduction) []types.FullReduction {
+	out := make([]types.FullReduction, len(reductions))
+	for i, r := range reductions {
+		fullReductionMapping.apply(r, &out[i])
+	}
+	return out
+}
+
+// newMapping maps the db columns of src into the fields of dst, by json
+// name. renamed gives the json name of columns not named after them, omitted
+// the columns not in the API. Any column or field left over, or of a type it
+// can't convert, is the error of the mapping.
+func newMapping(src, dst interface{}, renamed map[string]string, omitted ...string) *mapping {
+	m := &mapping{
+		src: reflect.TypeOf(src),
+		dst: reflect.TypeOf(dst),
+	}
+	if err := m.build(renamed, omitted); err != nil {
+		return &mapping{src: m.src, dst: m.dst, err: err}
+	}
+
+	return m
+}
+
+func (m *mapping) build(renamed map[string]string, omitted []string) error {
+	fields := make(map[string]int, m.dst.NumField())
+	for i := 0; i < m.dst.NumField(); i++ {
+		name := strings.Split(m.dst.Field(i).Tag.Get("json"), ",")[0]
+		fields[name] = i
+	}
+	skip := make(map[string]bool, len(omitted))
+	for _, column := range omitted {
+		skip[column] = true
+	}
+
+	for i := 0; i < m.src.NumField(); i++ {
+		sf := m.src.Field(i)
+		column := sf.Tag.Get("db")
+		if len(column) == 0 || column == "-" {
+			continue
+		}
+		if skip[column] {
+			delete(skip, column)
+			continue
+		}
+
+		name, ok := renamed[column]
+		if !ok {
+			name = camelCase(column)
+		}
+		j, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("convert: column %s of %s isn't mapped, add %q to %s or omit it",
+				column, m.src, name, m.dst)
+		}
+		delete(fields, name)
+
+		df := m.dst.Field(j)
+		convert := converter(sf.Type, df.Type)
+		if convert == nil {
+			return fmt.Errorf("convert: can't convert %s.%s of type %s into %s.%s of type %s",
+				m.src, sf.Name, sf.Type, m.dst, df.Name, df.Type)
+		}
+		m.fields = append(m.fields, fieldMapping{src: i, dst: j, convert: convert})
+	}
+
+	for _, column := range omitted {
+		if skip[column] {
+			return fmt.Errorf("convert: omitted column %s isn't in %s", column, m.src)
+		}
+	}
+	for i := 0; i < m.dst.NumField(); i++ {
+		name := strings.Split(m.dst.Field(i).Tag.Get("json"), ",")[0]
+		if _, ok := fields[name]; ok {
+			return fmt.Errorf("convert: field %q of %s isn't filled by any column of %s", name, m.dst, m.src)
+		}
+	}
+
+	return nil
+}
+
+// apply copies src, a pointer to the model, into dst, a pointer to the API
+// type. A nil src, or a mapping that failed to build, leaves dst zero.
+func (m *mapping) apply(src, dst interface{}) {
+	sv := reflect.ValueOf(src)
+	if sv.IsNil() {
+		return
+	}
+	sv = sv.Elem()
+	dv := reflect.ValueOf(dst).Elem()
+
+	for _, f := range m.fields {
+		dv.Field(f.dst).Set(f.convert(sv.Field(f.src)))
+	}
+}
+
+// converter returns how to convert a model field of type from into an API
+// field of type to, nil when not supported.
+func converter(from, to reflect.Type) func(reflect.Value) reflect.Value {
+	switch {
+	case from == moneyType && to.Kind() == reflect.String:
+		return func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(v.Interface().(model.Money).String())
+		}
+	case from == nullMoneyType && to.Kind() == reflect.String:
+		return func(v reflect.Value) reflect.Value {
+			m := v.Interface().(model.NullMoney)
+			if !m.Valid {
+				return reflect.ValueOf("")
+			}
+			return reflect.ValueOf(m.Money.String())
+		}
+	case from == nullInt64Type && to.Kind() == reflect.Int64:
+		return func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(v.Interface().(sql.NullInt64).Int64)
+		}
+	case from == nullFloatType && to.Kind() == reflect.Float64:
+		return func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(v.Interface().(sql.NullFloat64).Float64)
+		}
+	case from == nullStrType && to.Kind() == reflect.String:
+		return func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(v.Interface().(sql.NullString).String)
+		}
+	case from == nullTimeType && to.Kind() == reflect.Int64:
+		return func(v reflect.Value) reflect.Value {
+			t := v.Interface().(sql.NullTime)
+			if !t.Valid {
+				return reflect.ValueOf(int64(0))
+			}
+			return reflect.ValueOf(t.Time.Unix())
+		}
+	case from == timeType && to.Kind() == reflect.Int64:
+		return func(v reflect.Value) reflect.Value {
+			t := v.Interface().(time.Time)
+			if t.IsZero() {
+				return reflect.ValueOf(int64(0))
+			}
+			return reflect.ValueOf(t.Unix())
+		}
+	case from.Kind() == to.Kind() && from.ConvertibleTo(to) && isPlain(from.Kind()):
+		return func(v reflect.Value) reflect.Value {
+			return v.Convert(to)
+		}
+	default:
+		return nil
+	}
+}
+
+func isPlain(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int64, reflect.Float64, reflect.String, reflect.Bool:
+		return true
+	default:
+		return false
+	}
+}
+
+func camelCase(column string) string {
+	parts := strings.Split(column, "_")
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) > 0 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
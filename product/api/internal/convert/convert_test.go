@@ -0,0 +1,199 @@
+package convert
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"malltmp/product/api/internal/types"
+	"malltmp/product/model"
+)
+
+// populate sets every column of the model v points to a value of its own,
+// telling fields apart, and returns what each becomes in the API.
+func populate(v interface{}) map[int]interface{} {
+	rv := reflect.ValueOf(v).Elem()
+	want := make(map[int]interface{})
+	for i := 0; i < rv.NumField(); i++ {
+		if len(rv.Type().Field(i).Tag.Get("db")) == 0 {
+			continue
+		}
+
+		n := int64(i + 1)
+		at := time.Unix(1616000000+n, 0)
+		money := model.Money(n * 101)
+		f := rv.Field(i)
+		switch f.Interface().(type) {
+		case int64:
+			f.SetInt(n)
+			want[i] = n
+		case string:
+			f.SetString("s" + money.String())
+			want[i] = "s" + money.String()
+		case model.Money:
+			f.Set(reflect.ValueOf(money))
+			want[i] = money.String()
+		case model.NullMoney:
+			f.Set(reflect.ValueOf(model.NewNullMoney(money)))
+			want[i] = money.String()
+		case sql.NullInt64:
+			f.Set(reflect.ValueOf(sql.NullInt64{Int64: n, Valid: true}))
+			want[i] = n
+		case sql.NullFloat64:
+			f.Set(reflect.ValueOf(sql.NullFloat64{Float64: float64(n) + 0.5, Valid: true}))
+			want[i] = float64(n) + 0.5
+		case sql.NullString:
+			f.Set(reflect.ValueOf(sql.NullString{String: "n" + money.String(), Valid: true}))
+			want[i] = "n" + money.String()
+		case sql.NullTime:
+			f.Set(reflect.ValueOf(sql.NullTime{Time: at, Valid: true}))
+			want[i] = at.Unix()
+		case time.Time:
+			f.Set(reflect.ValueOf(at))
+			want[i] = at.Unix()
+		}
+	}
+
+	return want
+}
+
+func TestMappings(t *testing.T) {
+	if err := Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range mappings {
+		src := reflect.New(m.src).Interface()
+		want := populate(src)
+		dst := reflect.New(m.dst)
+		m.apply(src, dst.Interface())
+
+		for _, f := range m.fields {
+			got := dst.Elem().Field(f.dst).Interface()
+			if got != want[f.src] {
+				t.Errorf("%s.%s = %v, want %v", m.dst, m.dst.Field(f.dst).Name, got, want[f.src])
+			}
+		}
+		// every field is filled by a column, none is left zero
+		for i := 0; i < m.dst.NumField(); i++ {
+			if dst.Elem().Field(i).IsZero() {
+				t.Errorf("%s.%s is not filled", m.dst, m.dst.Field(i).Name)
+			}
+		}
+
+		// NULLs and nil models convert to zero values
+		zero := reflect.New(m.dst)
+		m.apply(reflect.New(m.src).Interface(), zero.Interface())
+		if !zero.Elem().IsZero() {
+			t.Errorf("%s of a zero %s = %+v, want zero", m.dst, m.src, zero.Elem())
+		}
+		m.apply(reflect.Zero(reflect.PtrTo(m.src)).Interface(), zero.Interface())
+		if !zero.Elem().IsZero() {
+			t.Errorf("%s of a nil %s = %+v, want zero", m.dst, m.src, zero.Elem())
+		}
+	}
+}
+
+func TestProduct(t *testing.T) {
+	p := &model.PmsProduct{
+		Id:               1,
+		Name:             "手机",
+		Price:            model.NewNullMoney(model.MustParseMoney("1999.00")),
+		FeightTemplateId: sql.NullInt64{Int64: 3, Valid: true},
+		RecommandStatus:  sql.NullInt64{Int64: 1, Valid: true},
+		PromotionEndTime: sql.NullTime{Time: time.Unix(1616000000, 0), Valid: true},
+		// not in the API
+		PreviousPromotionType: sql.NullInt64{Int64: 4, Valid: true},
+	}
+
+	got := Product(p)
+	want := types.Product{
+		Id:                1,
+		Name:              "手机",
+		Price:             "1999.00",
+		FreightTemplateId: 3,
+		RecommendStatus:   1,
+		PromotionEndTime:  1616000000,
+	}
+	if got != want {
+		t.Errorf("Product = %+v, want %+v", got, want)
+	}
+
+	summaries := ProductSummaries([]*model.PmsProduct{p, nil})
+	if len(summaries) != 2 || summaries[0].Price != "1999.00" || summaries[0].RecommendStatus != 1 ||
+		summaries[1] != (types.ProductSummary{}) {
+		t.Errorf("ProductSummaries = %+v", summaries)
+	}
+}
+
+func TestMappingErrors(t *testing.T) {
+	type (
+		row struct {
+			Id    int64          `db:"id"`
+			Name  sql.NullString `db:"name"`
+			Price model.Money    `db:"price"`
+		}
+		resp struct {
+			Id    int64  `json:"id"`
+			Name  string `json:"name"`
+			Price string `json:"price"`
+		}
+		short struct {
+			Id   int64  `json:"id"`
+			Name string `json:"name"`
+		}
+		long struct {
+			Id    int64  `json:"id"`
+			Name  string `json:"name"`
+			Price string `json:"price"`
+			Extra string `json:"extra"`
+		}
+		mistyped struct {
+			Id    int64   `json:"id"`
+			Name  string  `json:"name"`
+			Price float64 `json:"price"`
+		}
+	)
+
+	tests := []struct {
+		name    string
+		dst     interface{}
+		renamed map[string]string
+		omitted []string
+		err     string
+	}{
+		{"mapped", resp{}, nil, nil, ""},
+		{"omitted", short{}, nil, []string{"price"}, ""},
+		{"renamed", struct {
+			Id    int64  `json:"id"`
+			Title string `json:"title"`
+			Price string `json:"price"`
+		}{}, map[string]string{"name": "title"}, nil, ""},
+		{"column left over", short{}, nil, nil, "column price"},
+		{"omitted missing", resp{}, nil, []string{"sale"}, "omitted column sale"},
+		{"field left over", long{}, nil, nil, `field "extra"`},
+		{"type mismatch", mistyped{}, nil, nil, "can't convert"},
+	}
+	for _, tt := range tests {
+		m := newMapping(row{}, tt.dst, tt.renamed, tt.omitted...)
+		if len(tt.err) == 0 {
+			if m.err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, m.err)
+			}
+			continue
+		}
+		if m.err == nil || !strings.Contains(m.err.Error(), tt.err) {
+			t.Errorf("%s: error = %v, want one about %s", tt.name, m.err, tt.err)
+			continue
+		}
+
+		// a broken mapping copies nothing, and doesn't panic
+		dst := reflect.New(reflect.TypeOf(tt.dst))
+		m.apply(&row{Id: 1}, dst.Interface())
+		if !dst.Elem().IsZero() {
+			t.Errorf("%s: broken mapping copied %+v", tt.name, dst.Elem())
+		}
+	}
+}
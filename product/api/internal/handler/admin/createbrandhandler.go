@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"malltmp/product/api/internal/logic/admin"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/rest/httpx"
+)
+
+func CreateBrandHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.BrandForm
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.Error(w, err)
+			return
+		}
+
+		l := logic.NewCreateBrandLogic(r.Context(), ctx)
+		resp, err := l.CreateBrand(req)
+		if err != nil {
+			httpx.Error(w, err)
+		} else {
+			httpx.OkJson(w, resp)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"malltmp/product/api/internal/logic/admin"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/rest/httpx"
+)
+
+func DeleteAttributeHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.AdminIdReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.Error(w, err)
+			return
+		}
+
+		l := logic.NewDeleteAttributeLogic(r.Context(), ctx)
+		err := l.DeleteAttribute(req)
+		if err != nil {
+			httpx.Error(w, err)
+		} else {
+			httpx.Ok(w)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net/http"
+
+	"malltmp/product/api/internal/logic"
+	"malltmp/product/model"
+)
+
+// ErrorHandler answers the errors of the handlers: rows not found are 404,
+// failures of the service 500, the rest 400 as before, the error as the body.
+func ErrorHandler(err error) (int, interface{}) {
+	switch err {
+	case model.ErrNotFound:
+		return http.StatusNotFound, err
+	case logic.ErrInternal:
+		return http.StatusInternalServerError, err
+	default:
+		return http.StatusBadRequest, err
+	}
+}
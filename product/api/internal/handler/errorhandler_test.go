@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"malltmp/product/api/internal/logic"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/rest/httpx"
+)
+
+func TestErrorHandler(t *testing.T) {
+	httpx.SetErrorHandler(ErrorHandler)
+	defer httpx.SetErrorHandler(nil)
+
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{model.ErrNotFound, http.StatusNotFound},
+		{logic.ErrInternal, http.StatusInternalServerError},
+		{errors.New("invalid product"), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		httpx.Error(w, tt.err)
+		if w.Code != tt.code || w.Body.String() != tt.err.Error()+"\n" {
+			t.Errorf("%v: answered %d %q, want %d", tt.err, w.Code, w.Body.String(), tt.code)
+		}
+	}
+}
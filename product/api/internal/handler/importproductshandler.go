@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"malltmp/product/api/internal/logic"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/rest/httpx"
+)
+
+// importFileField is the multipart field holding the CSV file.
+const importFileField = "file"
+
+func ImportProductsHandler(ctx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ImportProductsReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.Error(w, err)
+			return
+		}
+
+		file, _, err := r.FormFile(importFileField)
+		if err != nil {
+			httpx.Error(w, err)
+			return
+		}
+		defer file.Close()
+
+		l := logic.NewImportProductsLogic(r.Context(), ctx)
+		resp, err := l.ImportProducts(req, file)
+		if err != nil {
+			httpx.Error(w, err)
+		} else {
+			httpx.OkJson(w, resp)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// Code generated by goctl. DO NOT EDIT.
+package handler
+
+import (
+	"net/http"
+
+	admin "malltmp/product/api/internal/handler/admin"
+	"malltmp/product/api/internal/svc"
+
+	"github.com/tal-tech/go-zero/rest"
+)
+
+func RegisterHandlers(engine *rest.Server, serverCtx *svc.ServiceContext) {
+	engine.AddRoutes(
+		[]rest.Route{
+			{
+				Method:  http.MethodGet,
+				Path:    "/product/detail/:productId",
+				Handler: PortalProductDetailHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/product/list",
+				Handler: PortalProductsHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodGet,
+				Path:    "/product/:id/ladder-quote",
+				Handler: LadderQuoteHandler(serverCtx),
+			},
+			{
+				Method:  http.MethodPost,
+				Path:    "/product/price-preview",
+				Handler: PricePreviewHandler(serverCtx),
+			},
+		},
+	)
+
+	engine.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.StockReserve},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/stock/reservations",
+					Handler: ReserveStockHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/stock/reservations/:id/confirm",
+					Handler: ConfirmReservationHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/stock/reservations/:id/cancel",
+					Handler: CancelReservationHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+	)
+
+	engine.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.CatalogRead},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/stock/skus/:skuId/ledger",
+					Handler: StockLedgerHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/stock/skus/:skuId/ledger/verify",
+					Handler: VerifyStockLedgerHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/product/:id/verify-records",
+					Handler: ProductVerifyRecordsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/product/publish-schedules",
+					Handler: PublishSchedulesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/product/:id/revisions",
+					Handler: ProductRevisionsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/product/:id/revisions/diff",
+					Handler: RevisionDiffHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+	)
+
+	engine.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.CatalogWrite},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/import",
+					Handler: ImportProductsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/:id/submit",
+					Handler: SubmitProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/:id/publish",
+					Handler: PublishProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/:id/unpublish",
+					Handler: UnpublishProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/:id/preview",
+					Handler: PreviewProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/:id/publish-schedules",
+					Handler: SchedulePublishHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/publish-schedules/:id/cancel",
+					Handler: CancelPublishScheduleHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/product/:id/promotion-window",
+					Handler: SetPromotionWindowHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/product/:id/promotion-window",
+					Handler: ClearPromotionWindowHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/:id/revisions",
+					Handler: SaveRevisionHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+	)
+
+	engine.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.CatalogReview},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/:id/approve",
+					Handler: ApproveProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/:id/reject",
+					Handler: RejectProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/audit",
+					Handler: AuditLogsHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+	)
+
+	engine.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.CatalogManage},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/product/:id/revisions/:revision/restore",
+					Handler: RestoreRevisionHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+	)
+
+	engine.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.CatalogRead},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/products/:id",
+					Handler: admin.GetProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/products",
+					Handler: admin.ListProductsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/products/:id/skus",
+					Handler: admin.ListSkusHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/products/:id/attribute-values",
+					Handler: admin.ListAttributeValuesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/products/:id/ladders",
+					Handler: admin.ListLaddersHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/products/:id/full-reductions",
+					Handler: admin.ListFullReductionsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/attributes",
+					Handler: admin.ListAttributesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/brands/:id",
+					Handler: admin.GetBrandHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/brands",
+					Handler: admin.ListBrandsHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+	)
+
+	engine.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.CatalogWrite},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/admin/products",
+					Handler: admin.CreateProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/admin/products/:id",
+					Handler: admin.UpdateProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/admin/products/:id/skus",
+					Handler: admin.CreateSkuHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/admin/skus/:id",
+					Handler: admin.UpdateSkuHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/admin/products/:id/attribute-values",
+					Handler: admin.ReplaceAttributeValuesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/admin/products/:id/ladders",
+					Handler: admin.ReplaceLaddersHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/admin/products/:id/full-reductions",
+					Handler: admin.ReplaceFullReductionsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/admin/attributes",
+					Handler: admin.CreateAttributeHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/admin/attributes/:id",
+					Handler: admin.UpdateAttributeHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/admin/brands",
+					Handler: admin.CreateBrandHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/admin/brands/:id",
+					Handler: admin.UpdateBrandHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+	)
+
+	engine.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.CatalogManage},
+			[]rest.Route{
+				{
+					Method:  http.MethodDelete,
+					Path:    "/admin/products/:id",
+					Handler: admin.DeleteProductHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/admin/skus/:id",
+					Handler: admin.DeleteSkuHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/admin/attributes/:id",
+					Handler: admin.DeleteAttributeHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/admin/brands/:id",
+					Handler: admin.DeleteBrandHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+	)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"malltmp/product/api/internal/config"
+	"malltmp/product/api/internal/middleware"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/service"
+	"github.com/tal-tech/go-zero/rest"
+	"github.com/tal-tech/go-zero/rest/router"
+)
+
+const testSecret = "a-secret-for-the-tests-of-the-routes"
+
+// newTestRouter registers the routes and returns the router serving them,
+// the chain of each route built as the server builds it.
+func newTestRouter(t *testing.T) http.Handler {
+	var c config.Config
+	c.Mode, c.Log.Mode = service.TestMode, "console"
+	c.Host, c.Port = "127.0.0.1", 0
+	c.Auth.AccessSecret = testSecret
+	r := router.NewRouter()
+	server := rest.MustNewServer(c.RestConf, rest.WithRouter(r))
+	logx.Disable()
+	RegisterHandlers(server, &svc.ServiceContext{
+		Config:        c,
+		CatalogRead:   middleware.NewCatalogReadMiddleware().Handle,
+		CatalogWrite:  middleware.NewCatalogWriteMiddleware().Handle,
+		CatalogReview: middleware.NewCatalogReviewMiddleware().Handle,
+		CatalogManage: middleware.NewCatalogManageMiddleware().Handle,
+		StockReserve:  middleware.NewStockReserveMiddleware().Handle,
+	})
+	// the routes are bound by Start, which then serves them on a port of its own
+	go server.Start()
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/products/1", nil))
+		if w.Code != http.StatusNotFound {
+			return r
+		}
+	}
+	t.Fatal("routes not bound")
+	return nil
+}
+
+func TestAdminRoutesAuth(t *testing.T) {
+	r := newTestRouter(t)
+	token := func(secret string, role auth.Role, expire time.Duration) string {
+		s, err := auth.NewToken(secret, "alice", role, expire)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		code   int
+	}{
+		{"no token", http.MethodDelete, "/admin/products/1", "", http.StatusUnauthorized},
+		{"malformed token", http.MethodDelete, "/admin/products/1", "not-a-token", http.StatusUnauthorized},
+		{"other secret", http.MethodDelete, "/admin/products/1",
+			token("another-secret-for-the-tests", auth.RoleAdmin, time.Hour), http.StatusUnauthorized},
+		{"expired", http.MethodDelete, "/admin/products/1",
+			token(testSecret, auth.RoleAdmin, -time.Minute), http.StatusUnauthorized},
+		{"restore without token", http.MethodPost, "/product/1/revisions/1/restore", "", http.StatusUnauthorized},
+		{"role without permission", http.MethodDelete, "/admin/products/1",
+			token(testSecret, auth.RoleEditor, time.Hour), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if len(tt.token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%s: %s %s = %d, want %d", tt.name, tt.method, tt.path, w.Code, tt.code)
+		}
+	}
+}
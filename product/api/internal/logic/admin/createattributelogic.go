@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/catalog"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type CreateAttributeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateAttributeLogic(ctx context.Context, svcCtx *svc.ServiceContext) CreateAttributeLogic {
+	return CreateAttributeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateAttributeLogic) CreateAttribute(req types.AttributeForm) (*types.AdminIdResp, error) {
+	data := attributeFromForm(req)
+	if err := catalog.ValidateAttribute(&data); err != nil {
+		return nil, err
+	}
+
+	ret, err := l.svcCtx.AttributeModelFor(auth.OperatorFrom(l.ctx)).Insert(data)
+	if err != nil {
+		return nil, err
+	}
+	id, err := ret.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AdminIdResp{Id: id}, nil
+}
@@ -0,0 +1,64 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/catalog"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type CreateBrandLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateBrandLogic(ctx context.Context, svcCtx *svc.ServiceContext) CreateBrandLogic {
+	return CreateBrandLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateBrandLogic) CreateBrand(req types.BrandForm) (*types.AdminIdResp, error) {
+	data := brandFromForm(req)
+	if err := catalog.ValidateBrand(&data); err != nil {
+		return nil, err
+	}
+	if err := checkBrandName(l.svcCtx, data); err != nil {
+		return nil, err
+	}
+
+	ret, err := l.svcCtx.BrandModelFor(auth.OperatorFrom(l.ctx)).Insert(data)
+	if err != nil {
+		return nil, err
+	}
+	id, err := ret.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AdminIdResp{Id: id}, nil
+}
+
+// checkBrandName fails when another brand has the name of data.
+func checkBrandName(svcCtx *svc.ServiceContext, data model.PmsBrand) error {
+	brand, err := svcCtx.BrandModel.FindOneByName(data.Name.String)
+	switch err {
+	case nil:
+		if brand.Id != data.Id {
+			return &catalog.FieldError{Field: "name", Message: "is taken by another brand"}
+		}
+		return nil
+	case model.ErrNotFound:
+		return nil
+	default:
+		return err
+	}
+}
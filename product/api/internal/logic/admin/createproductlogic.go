@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type CreateProductLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateProductLogic(ctx context.Context, svcCtx *svc.ServiceContext) CreateProductLogic {
+	return CreateProductLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateProductLogic) CreateProduct(req types.ProductForm) (*types.AdminIdResp, error) {
+	data, err := productFromForm(req)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := l.svcCtx.Editor(auth.OperatorFrom(l.ctx)).CreateProduct(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AdminIdResp{Id: id}, nil
+}
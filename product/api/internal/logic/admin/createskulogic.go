@@ -0,0 +1,40 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type CreateSkuLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateSkuLogic(ctx context.Context, svcCtx *svc.ServiceContext) CreateSkuLogic {
+	return CreateSkuLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateSkuLogic) CreateSku(req types.CreateSkuReq) (*types.AdminIdResp, error) {
+	data, err := skuFromForm(req.SkuCode, req.Price, req.PromotionPrice, req.LowStock, req.SpData, req.Pic)
+	if err != nil {
+		return nil, err
+	}
+	data.Stock = req.Stock
+
+	id, err := l.svcCtx.Editor(auth.OperatorFrom(l.ctx)).CreateSku(req.ProductId, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AdminIdResp{Id: id}, nil
+}
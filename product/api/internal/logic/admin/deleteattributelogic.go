@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type DeleteAttributeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteAttributeLogic(ctx context.Context, svcCtx *svc.ServiceContext) DeleteAttributeLogic {
+	return DeleteAttributeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteAttributeLogic) DeleteAttribute(req types.AdminIdReq) error {
+	return l.svcCtx.AttributeModelFor(auth.OperatorFrom(l.ctx)).Delete(req.Id)
+}
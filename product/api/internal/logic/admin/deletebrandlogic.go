@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type DeleteBrandLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteBrandLogic(ctx context.Context, svcCtx *svc.ServiceContext) DeleteBrandLogic {
+	return DeleteBrandLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteBrandLogic) DeleteBrand(req types.AdminIdReq) error {
+	return l.svcCtx.BrandModelFor(auth.OperatorFrom(l.ctx)).Delete(req.Id)
+}
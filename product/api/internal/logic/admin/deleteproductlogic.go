@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type DeleteProductLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteProductLogic(ctx context.Context, svcCtx *svc.ServiceContext) DeleteProductLogic {
+	return DeleteProductLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteProductLogic) DeleteProduct(req types.AdminIdReq) error {
+	return l.svcCtx.Editor(auth.OperatorFrom(l.ctx)).DeleteProduct(req.Id)
+}
@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type DeleteSkuLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteSkuLogic(ctx context.Context, svcCtx *svc.ServiceContext) DeleteSkuLogic {
+	return DeleteSkuLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteSkuLogic) DeleteSku(req types.AdminIdReq) error {
+	return l.svcCtx.Editor(auth.OperatorFrom(l.ctx)).DeleteSku(req.Id)
+}
@@ -0,0 +1,148 @@
+package logic
+
+import (
+	"database/sql"
+
+	"malltmp/product/api/internal/types"
+	"malltmp/product/catalog"
+	"malltmp/product/model"
+)
+
+const maxAdminPageLimit = 100
+
+func pageLimit(limit int) int {
+	if limit <= 0 || limit > maxAdminPageLimit {
+		return maxAdminPageLimit
+	}
+
+	return limit
+}
+
+func productFromForm(req types.ProductForm) (model.PmsProduct, error) {
+	price, err := parseMoney("price", req.Price)
+	if err != nil {
+		return model.PmsProduct{}, err
+	}
+	originalPrice, err := parseMoney("original_price", req.OriginalPrice)
+	if err != nil {
+		return model.PmsProduct{}, err
+	}
+	promotionPrice, err := parseMoney("promotion_price", req.PromotionPrice)
+	if err != nil {
+		return model.PmsProduct{}, err
+	}
+
+	return model.PmsProduct{
+		Id:                         req.Id,
+		Name:                       req.Name,
+		ProductSn:                  req.ProductSn,
+		Price:                      price,
+		BrandId:                    nullId(req.BrandId),
+		ProductCategoryId:          nullId(req.ProductCategoryId),
+		ProductCategoryName:        nullString(req.ProductCategoryName),
+		FeightTemplateId:           nullId(req.FreightTemplateId),
+		ProductAttributeCategoryId: nullId(req.ProductAttributeCategoryId),
+		Pic:                        nullString(req.Pic),
+		AlbumPics:                  nullString(req.AlbumPics),
+		SubTitle:                   nullString(req.SubTitle),
+		Description:                req.Description,
+		Keywords:                   nullString(req.Keywords),
+		Note:                       nullString(req.Note),
+		Unit:                       nullString(req.Unit),
+		Weight:                     sql.NullFloat64{Float64: req.Weight, Valid: req.Weight > 0},
+		Sort:                       nullInt(req.Sort),
+		NewStatus:                  nullInt(req.NewStatus),
+		RecommandStatus:            nullInt(req.RecommendStatus),
+		OriginalPrice:              originalPrice,
+		PromotionPrice:             promotionPrice,
+		PromotionType:              nullInt(req.PromotionType),
+		PromotionPerLimit:          nullInt(req.PromotionPerLimit),
+		GiftPoint:                  req.GiftPoint,
+		GiftGrowth:                 req.GiftGrowth,
+		UsePointLimit:              nullInt(req.UsePointLimit),
+		LowStock:                   nullInt(req.LowStock),
+		ServiceIds:                 nullString(req.ServiceIds),
+		DetailTitle:                nullString(req.DetailTitle),
+		DetailDesc:                 req.DetailDesc,
+		DetailHtml:                 req.DetailHtml,
+		DetailMobileHtml:           req.DetailMobileHtml,
+	}, nil
+}
+
+func skuFromForm(skuCode, priceValue, promotionPriceValue string, lowStock int64, spData,
+	pic string) (model.PmsSkuStock, error) {
+	price, err := parseMoney("price", priceValue)
+	if err != nil {
+		return model.PmsSkuStock{}, err
+	}
+	promotionPrice, err := parseMoney("promotion_price", promotionPriceValue)
+	if err != nil {
+		return model.PmsSkuStock{}, err
+	}
+
+	return model.PmsSkuStock{
+		SkuCode:        skuCode,
+		Price:          price,
+		PromotionPrice: promotionPrice,
+		LowStock:       nullInt(lowStock),
+		SpData:         nullString(spData),
+		Pic:            nullString(pic),
+	}, nil
+}
+
+func attributeFromForm(req types.AttributeForm) model.PmsProductAttribute {
+	return model.PmsProductAttribute{
+		Id:                         req.Id,
+		ProductAttributeCategoryId: nullId(req.ProductAttributeCategoryId),
+		Name:                       nullString(req.Name),
+		Type:                       nullInt(req.Type),
+		SelectType:                 nullInt(req.SelectType),
+		InputType:                  nullInt(req.InputType),
+		InputList:                  nullString(req.InputList),
+		Sort:                       nullInt(req.Sort),
+		FilterType:                 nullInt(req.FilterType),
+		SearchType:                 nullInt(req.SearchType),
+		RelatedStatus:              nullInt(req.RelatedStatus),
+		HandAddStatus:              nullInt(req.HandAddStatus),
+	}
+}
+
+func brandFromForm(req types.BrandForm) model.PmsBrand {
+	return model.PmsBrand{
+		Id:            req.Id,
+		Name:          nullString(req.Name),
+		FirstLetter:   nullString(req.FirstLetter),
+		Sort:          nullInt(req.Sort),
+		FactoryStatus: nullInt(req.FactoryStatus),
+		ShowStatus:    nullInt(req.ShowStatus),
+		Logo:          nullString(req.Logo),
+		BigPic:        nullString(req.BigPic),
+		BrandStory:    req.BrandStory,
+	}
+}
+
+// parseMoney parses the price of field, NULL when empty.
+func parseMoney(field, s string) (model.NullMoney, error) {
+	if len(s) == 0 {
+		return model.NullMoney{}, nil
+	}
+
+	m, err := model.ParseMoney(s)
+	if err != nil {
+		return model.NullMoney{}, &catalog.FieldError{Field: field, Message: "is not a price"}
+	}
+	return model.NewNullMoney(m), nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: len(s) > 0}
+}
+
+func nullInt(v int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: v, Valid: true}
+}
+
+// nullId is NULL for 0, which is no row.
+func nullId(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id > 0}
+}
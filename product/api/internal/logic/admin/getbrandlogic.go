@@ -0,0 +1,35 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type GetBrandLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetBrandLogic(ctx context.Context, svcCtx *svc.ServiceContext) GetBrandLogic {
+	return GetBrandLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetBrandLogic) GetBrand(req types.AdminIdReq) (*types.Brand, error) {
+	brand, err := l.svcCtx.BrandModel.FindOne(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := convert.Brand(brand)
+	return &resp, nil
+}
@@ -0,0 +1,35 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type GetProductLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetProductLogic(ctx context.Context, svcCtx *svc.ServiceContext) GetProductLogic {
+	return GetProductLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetProductLogic) GetProduct(req types.AdminIdReq) (*types.Product, error) {
+	product, err := l.svcCtx.ProductModel.FindOne(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := convert.Product(product)
+	return &resp, nil
+}
@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ListAttributesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListAttributesLogic(ctx context.Context, svcCtx *svc.ServiceContext) ListAttributesLogic {
+	return ListAttributesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListAttributesLogic) ListAttributes(req types.AttributesReq) (*types.AttributesResp, error) {
+	limit := pageLimit(req.Limit)
+	attributes, err := l.svcCtx.AttributeModel.FindByCategoryId(req.ProductAttributeCategoryId, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.AttributesResp{
+		Attributes: make([]types.Attribute, 0, len(attributes)),
+	}
+	for _, attribute := range attributes {
+		resp.Attributes = append(resp.Attributes, convert.Attribute(attribute))
+	}
+	if len(attributes) == limit {
+		resp.NextCursor = attributes[len(attributes)-1].Id
+	}
+
+	return resp, nil
+}
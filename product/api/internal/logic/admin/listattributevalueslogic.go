@@ -0,0 +1,36 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ListAttributeValuesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListAttributeValuesLogic(ctx context.Context, svcCtx *svc.ServiceContext) ListAttributeValuesLogic {
+	return ListAttributeValuesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListAttributeValuesLogic) ListAttributeValues(req types.AdminIdReq) (*types.AttributeValuesResp, error) {
+	values, err := l.svcCtx.AttributeValueModel.FindByProductIds([]int64{req.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AttributeValuesResp{
+		Values: convert.AttributeValues(values),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ListBrandsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListBrandsLogic(ctx context.Context, svcCtx *svc.ServiceContext) ListBrandsLogic {
+	return ListBrandsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListBrandsLogic) ListBrands(req types.AdminPageReq) (*types.BrandsResp, error) {
+	limit := pageLimit(req.Limit)
+	brands, err := l.svcCtx.BrandModel.FindAll(req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.BrandsResp{
+		Brands: make([]types.Brand, 0, len(brands)),
+	}
+	for _, brand := range brands {
+		resp.Brands = append(resp.Brands, convert.Brand(brand))
+	}
+	if len(brands) == limit {
+		resp.NextCursor = brands[len(brands)-1].Id
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,36 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ListFullReductionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListFullReductionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) ListFullReductionsLogic {
+	return ListFullReductionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListFullReductionsLogic) ListFullReductions(req types.AdminIdReq) (*types.FullReductionsResp, error) {
+	reductions, err := l.svcCtx.FullReductionModel.FindByProductId(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.FullReductionsResp{
+		FullReductions: convert.FullReductions(reductions),
+	}, nil
+}
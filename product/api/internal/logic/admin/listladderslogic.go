@@ -0,0 +1,36 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ListLaddersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListLaddersLogic(ctx context.Context, svcCtx *svc.ServiceContext) ListLaddersLogic {
+	return ListLaddersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListLaddersLogic) ListLadders(req types.AdminIdReq) (*types.LaddersResp, error) {
+	ladders, err := l.svcCtx.LadderModel.FindByProductId(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.LaddersResp{
+		Ladders: convert.Ladders(ladders),
+	}, nil
+}
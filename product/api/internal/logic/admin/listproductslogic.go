@@ -0,0 +1,55 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ListProductsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListProductsLogic(ctx context.Context, svcCtx *svc.ServiceContext) ListProductsLogic {
+	return ListProductsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListProductsLogic) ListProducts(req types.AdminProductsReq) (*types.AdminProductsResp, error) {
+	limit := pageLimit(req.Limit)
+	products, next, err := l.svcCtx.ProductModel.FindPage(model.PmsProductPageQuery{
+		OrderBy:           req.OrderBy,
+		Desc:              req.Desc,
+		DeleteStatus:      convert.StatusFilter(req.DeleteStatus),
+		PublishStatus:     convert.StatusFilter(req.PublishStatus),
+		VerifyStatus:      convert.StatusFilter(req.VerifyStatus),
+		NewStatus:         convert.StatusFilter(req.NewStatus),
+		RecommandStatus:   convert.StatusFilter(req.RecommendStatus),
+		PreviewStatus:     convert.StatusFilter(req.PreviewStatus),
+		BrandId:           req.BrandId,
+		ProductCategoryId: req.ProductCategoryId,
+	}, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.AdminProductsResp{
+		Products:   make([]types.Product, 0, len(products)),
+		NextCursor: next,
+	}
+	for _, product := range products {
+		resp.Products = append(resp.Products, convert.Product(product))
+	}
+
+	return resp, nil
+}
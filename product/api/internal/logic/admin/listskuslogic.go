@@ -0,0 +1,36 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ListSkusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListSkusLogic(ctx context.Context, svcCtx *svc.ServiceContext) ListSkusLogic {
+	return ListSkusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListSkusLogic) ListSkus(req types.AdminIdReq) (*types.SkusResp, error) {
+	skus, err := l.svcCtx.SkuStockModel.FindByProductIds([]int64{req.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SkusResp{
+		Skus: convert.Skus(skus),
+	}, nil
+}
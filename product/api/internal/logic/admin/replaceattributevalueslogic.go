@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ReplaceAttributeValuesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReplaceAttributeValuesLogic(ctx context.Context, svcCtx *svc.ServiceContext) ReplaceAttributeValuesLogic {
+	return ReplaceAttributeValuesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ReplaceAttributeValuesLogic) ReplaceAttributeValues(req types.AttributeValuesForm) error {
+	values := make([]*model.PmsProductAttributeValue, len(req.Values))
+	for i, v := range req.Values {
+		values[i] = &model.PmsProductAttributeValue{
+			ProductAttributeId: nullId(v.ProductAttributeId),
+			Value:              sql.NullString{String: v.Value, Valid: true},
+		}
+	}
+
+	return l.svcCtx.Editor(auth.OperatorFrom(l.ctx)).ReplaceAttributeValues(req.Id, values)
+}
@@ -0,0 +1,47 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ReplaceFullReductionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReplaceFullReductionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) ReplaceFullReductionsLogic {
+	return ReplaceFullReductionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ReplaceFullReductionsLogic) ReplaceFullReductions(req types.FullReductionsForm) error {
+	reductions := make([]*model.PmsProductFullReduction, len(req.FullReductions))
+	for i, item := range req.FullReductions {
+		fullPrice, err := parseMoney("full_price", item.FullPrice)
+		if err != nil {
+			return err
+		}
+		reducePrice, err := parseMoney("reduce_price", item.ReducePrice)
+		if err != nil {
+			return err
+		}
+
+		reductions[i] = &model.PmsProductFullReduction{
+			FullPrice:   fullPrice,
+			ReducePrice: reducePrice,
+		}
+	}
+
+	return l.svcCtx.Editor(auth.OperatorFrom(l.ctx)).ReplaceFullReductions(req.Id, reductions)
+}
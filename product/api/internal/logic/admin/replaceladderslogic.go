@@ -0,0 +1,48 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ReplaceLaddersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReplaceLaddersLogic(ctx context.Context, svcCtx *svc.ServiceContext) ReplaceLaddersLogic {
+	return ReplaceLaddersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ReplaceLaddersLogic) ReplaceLadders(req types.LaddersForm) error {
+	ladders := make([]*model.PmsProductLadder, len(req.Ladders))
+	for i, item := range req.Ladders {
+		discount, err := parseMoney("discount", item.Discount)
+		if err != nil {
+			return err
+		}
+		price, err := parseMoney("price", item.Price)
+		if err != nil {
+			return err
+		}
+
+		ladders[i] = &model.PmsProductLadder{
+			Count:    nullInt(item.Count),
+			Discount: discount,
+			Price:    price,
+		}
+	}
+
+	return l.svcCtx.Editor(auth.OperatorFrom(l.ctx)).ReplaceLadders(req.Id, ladders)
+}
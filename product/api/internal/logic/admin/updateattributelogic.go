@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/catalog"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type UpdateAttributeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateAttributeLogic(ctx context.Context, svcCtx *svc.ServiceContext) UpdateAttributeLogic {
+	return UpdateAttributeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateAttributeLogic) UpdateAttribute(req types.AttributeForm) error {
+	data := attributeFromForm(req)
+	if err := catalog.ValidateAttribute(&data); err != nil {
+		return err
+	}
+	if _, err := l.svcCtx.AttributeModel.FindOne(data.Id); err != nil {
+		return err
+	}
+
+	return l.svcCtx.AttributeModelFor(auth.OperatorFrom(l.ctx)).Update(data)
+}
@@ -0,0 +1,41 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/catalog"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type UpdateBrandLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateBrandLogic(ctx context.Context, svcCtx *svc.ServiceContext) UpdateBrandLogic {
+	return UpdateBrandLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateBrandLogic) UpdateBrand(req types.BrandForm) error {
+	data := brandFromForm(req)
+	if err := catalog.ValidateBrand(&data); err != nil {
+		return err
+	}
+	if _, err := l.svcCtx.BrandModel.FindOne(data.Id); err != nil {
+		return err
+	}
+	if err := checkBrandName(l.svcCtx, data); err != nil {
+		return err
+	}
+
+	return l.svcCtx.BrandModelFor(auth.OperatorFrom(l.ctx)).Update(data)
+}
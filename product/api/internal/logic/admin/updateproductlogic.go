@@ -0,0 +1,34 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type UpdateProductLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateProductLogic(ctx context.Context, svcCtx *svc.ServiceContext) UpdateProductLogic {
+	return UpdateProductLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateProductLogic) UpdateProduct(req types.ProductForm) error {
+	data, err := productFromForm(req)
+	if err != nil {
+		return err
+	}
+
+	return l.svcCtx.Editor(auth.OperatorFrom(l.ctx)).UpdateProduct(data)
+}
@@ -0,0 +1,35 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type UpdateSkuLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateSkuLogic(ctx context.Context, svcCtx *svc.ServiceContext) UpdateSkuLogic {
+	return UpdateSkuLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateSkuLogic) UpdateSku(req types.UpdateSkuReq) error {
+	data, err := skuFromForm(req.SkuCode, req.Price, req.PromotionPrice, req.LowStock, req.SpData, req.Pic)
+	if err != nil {
+		return err
+	}
+	data.Id = req.Id
+
+	return l.svcCtx.Editor(auth.OperatorFrom(l.ctx)).UpdateSku(data)
+}
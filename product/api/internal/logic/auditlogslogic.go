@@ -0,0 +1,77 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/audit"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+const maxAuditLogLimit = 100
+
+type AuditLogsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAuditLogsLogic(ctx context.Context, svcCtx *svc.ServiceContext) AuditLogsLogic {
+	return AuditLogsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *AuditLogsLogic) AuditLogs(req types.AuditLogsReq) (*types.AuditLogsResp, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxAuditLogLimit {
+		limit = maxAuditLogLimit
+	}
+
+	logs, err := l.svcCtx.Recorder.History(req.Table, req.Id, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.AuditLogsResp{
+		Logs: make([]types.AuditLog, 0, len(logs)),
+	}
+	for _, log := range logs {
+		changes, err := audit.ParseChanges(log)
+		if err != nil {
+			return nil, err
+		}
+
+		item := types.AuditLog{
+			Id:         log.Id,
+			Action:     log.Action,
+			Actor:      log.Actor,
+			Changes:    make([]types.AuditChange, 0, len(changes)),
+			CreateTime: log.CreateTime.Unix(),
+		}
+		for _, change := range changes {
+			item.Changes = append(item.Changes, types.AuditChange{
+				Field:  change.Field,
+				Before: stringOrEmpty(change.Before),
+				After:  stringOrEmpty(change.After),
+			})
+		}
+		resp.Logs = append(resp.Logs, item)
+	}
+	if len(logs) == limit {
+		resp.NextCursor = logs[len(logs)-1].Id
+	}
+
+	return resp, nil
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
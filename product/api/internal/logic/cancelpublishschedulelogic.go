@@ -0,0 +1,28 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type CancelPublishScheduleLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCancelPublishScheduleLogic(ctx context.Context, svcCtx *svc.ServiceContext) CancelPublishScheduleLogic {
+	return CancelPublishScheduleLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CancelPublishScheduleLogic) CancelPublishSchedule(req types.CancelPublishScheduleReq) error {
+	return l.svcCtx.PublishScheduler.Cancel(req.Id)
+}
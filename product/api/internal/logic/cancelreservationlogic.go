@@ -0,0 +1,28 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type CancelReservationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCancelReservationLogic(ctx context.Context, svcCtx *svc.ServiceContext) CancelReservationLogic {
+	return CancelReservationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CancelReservationLogic) CancelReservation(req types.ReservationReq) error {
+	return l.svcCtx.Reserver.Cancel(req.Id)
+}
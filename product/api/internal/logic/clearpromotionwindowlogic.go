@@ -0,0 +1,28 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ClearPromotionWindowLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewClearPromotionWindowLogic(ctx context.Context, svcCtx *svc.ServiceContext) ClearPromotionWindowLogic {
+	return ClearPromotionWindowLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ClearPromotionWindowLogic) ClearPromotionWindow(req types.ClearPromotionWindowReq) error {
+	return l.svcCtx.ProductModel.ClearPromotionWindow(nil, req.Id)
+}
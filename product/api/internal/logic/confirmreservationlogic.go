@@ -0,0 +1,28 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ConfirmReservationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewConfirmReservationLogic(ctx context.Context, svcCtx *svc.ServiceContext) ConfirmReservationLogic {
+	return ConfirmReservationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ConfirmReservationLogic) ConfirmReservation(req types.ReservationReq) error {
+	return l.svcCtx.Reserver.Confirm(req.Id)
+}
@@ -0,0 +1,7 @@
+package logic
+
+import "errors"
+
+// ErrInternal is returned for failures of the service rather than of the
+// request, such as rows stored broken; what went wrong is logged instead.
+var ErrInternal = errors.New("internal error")
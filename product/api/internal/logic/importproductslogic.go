@@ -0,0 +1,51 @@
+package logic
+
+import (
+	"context"
+	"io"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type ImportProductsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewImportProductsLogic(ctx context.Context, svcCtx *svc.ServiceContext) ImportProductsLogic {
+	return ImportProductsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ImportProductsLogic) ImportProducts(req types.ImportProductsReq, file io.Reader) (*types.ImportProductsResp, error) {
+	report, err := l.svcCtx.Importer(auth.OperatorFrom(l.ctx)).Import(file, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.ImportProductsResp{
+		DryRun:   report.DryRun,
+		Products: report.Products,
+		Skus:     report.Skus,
+		Errors:   make([]types.ImportRowError, 0, len(report.Errors)),
+	}
+	for _, e := range report.Errors {
+		resp.Errors = append(resp.Errors, types.ImportRowError{
+			Line:    e.Line,
+			Column:  e.Column,
+			Message: e.Message,
+		})
+	}
+	l.Infof("imported %d products, %d skus, %d errors, dry run: %t",
+		report.Products, report.Skus, len(report.Errors), report.DryRun)
+
+	return resp, nil
+}
@@ -0,0 +1,89 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/model"
+	"malltmp/product/pricing"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type LadderQuoteLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewLadderQuoteLogic(ctx context.Context, svcCtx *svc.ServiceContext) LadderQuoteLogic {
+	return LadderQuoteLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// LadderQuote prices qty items of the product like the price preview does,
+// by the Calculator, so both agree. The tier of qty is returned whatever the
+// promotion type, LadderActive telling whether the price goes by it.
+func (l *LadderQuoteLogic) LadderQuote(req types.LadderQuoteReq) (*types.LadderQuoteResp, error) {
+	if req.Qty <= 0 {
+		return nil, pricing.ErrInvalidQuantity
+	}
+
+	product, err := l.svcCtx.ProductModel.FindOne(req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	if product.DeleteStatus.Int64 == 1 || product.PublishStatus.Int64 != 1 {
+		return nil, model.ErrNotFound
+	}
+	ladders, err := l.svcCtx.LadderModel.FindByProductId(req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	base := product.Price.Money
+	if err := pricing.ValidateLadders(base, ladders); err != nil {
+		l.Errorf("product %d has invalid ladders: %v", req.ProductId, err)
+		return nil, ErrInternal
+	}
+
+	breakdowns, err := l.svcCtx.Calculator.Quote(pricing.Line{
+		Product: product,
+		Ladders: ladders,
+		Items:   []pricing.Item{{Qty: req.Qty}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b := breakdowns[0]
+	resp := &types.LadderQuoteResp{
+		ProductId:    req.ProductId,
+		Qty:          req.Qty,
+		BasePrice:    b.UnitPrice.String(),
+		LadderActive: b.Ladder != nil,
+		UnitPrice:    b.Total.Div(b.Qty).String(),
+		Total:        b.Total.String(),
+		Saving:       b.Saving().String(),
+	}
+	ladder, err := pricing.ResolveLadder(ladders, req.Qty)
+	switch err {
+	case nil:
+		resp.LadderId = ladder.Id
+		resp.Count = ladder.Count.Int64
+		resp.Discount = ladder.Discount.Money.String()
+		resp.LadderPrice = pricing.LadderUnitPrice(b.UnitPrice, ladder).String()
+		if resp.LadderActive {
+			resp.UnitPrice = resp.LadderPrice
+		}
+	case pricing.ErrNoLadder:
+	default:
+		return nil, err
+	}
+
+	return resp, nil
+}
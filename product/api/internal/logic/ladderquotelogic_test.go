@@ -0,0 +1,102 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/model"
+)
+
+type (
+	fakeProductModel struct {
+		model.PmsProductModel
+		rows map[int64]model.PmsProduct
+	}
+
+	fakeLadderModel struct {
+		model.PmsProductLadderModel
+		rows map[int64][]*model.PmsProductLadder
+	}
+)
+
+func (m *fakeProductModel) FindOne(id int64) (*model.PmsProduct, error) {
+	row, ok := m.rows[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &row, nil
+}
+
+func (m *fakeLadderModel) FindByProductId(productId int64) ([]*model.PmsProductLadder, error) {
+	return m.rows[productId], nil
+}
+
+func quotedProduct(id, promotionType, publishStatus, deleteStatus int64) model.PmsProduct {
+	return model.PmsProduct{
+		Id:            id,
+		Price:         model.NewNullMoney(model.MustParseMoney("100.00")),
+		PromotionType: sql.NullInt64{Int64: promotionType, Valid: true},
+		PublishStatus: sql.NullInt64{Int64: publishStatus, Valid: true},
+		DeleteStatus:  sql.NullInt64{Int64: deleteStatus, Valid: true},
+	}
+}
+
+func quotedLadder(id, count int64, discount string) *model.PmsProductLadder {
+	return &model.PmsProductLadder{
+		Id:       id,
+		Count:    sql.NullInt64{Int64: count, Valid: true},
+		Discount: model.NewNullMoney(model.MustParseMoney(discount)),
+	}
+}
+
+func TestLadderQuote(t *testing.T) {
+	ladders := []*model.PmsProductLadder{quotedLadder(1, 2, "0.90"), quotedLadder(2, 5, "0.80")}
+	l := NewLadderQuoteLogic(context.Background(), &svc.ServiceContext{
+		ProductModel: &fakeProductModel{rows: map[int64]model.PmsProduct{
+			1: quotedProduct(1, model.PromotionTypeLadder, 1, 0),
+			2: quotedProduct(2, model.PromotionTypeNone, 1, 0),
+			3: quotedProduct(3, model.PromotionTypeLadder, 0, 0),
+			4: quotedProduct(4, model.PromotionTypeLadder, 1, 1),
+			5: quotedProduct(5, model.PromotionTypeLadder, 1, 0),
+		}},
+		LadderModel: &fakeLadderModel{rows: map[int64][]*model.PmsProductLadder{
+			1: ladders,
+			2: ladders,
+			5: {quotedLadder(3, 2, "1.20")},
+		}},
+	})
+
+	tests := []struct {
+		name      string
+		productId int64
+		qty       int64
+		want      types.LadderQuoteResp
+		err       error
+	}{
+		{"ladder active", 1, 5, types.LadderQuoteResp{ProductId: 1, Qty: 5, BasePrice: "100.00", LadderId: 2,
+			Count: 5, Discount: "0.80", LadderPrice: "80.00", LadderActive: true, UnitPrice: "80.00",
+			Total: "400.00", Saving: "100.00"}, nil},
+		{"under the first tier", 1, 1, types.LadderQuoteResp{ProductId: 1, Qty: 1, BasePrice: "100.00",
+			UnitPrice: "100.00", Total: "100.00", Saving: "0.00"}, nil},
+		{"ladder inactive", 2, 3, types.LadderQuoteResp{ProductId: 2, Qty: 3, BasePrice: "100.00", LadderId: 1,
+			Count: 2, Discount: "0.90", LadderPrice: "90.00", UnitPrice: "100.00", Total: "300.00",
+			Saving: "0.00"}, nil},
+		{"unpublished", 3, 2, types.LadderQuoteResp{}, model.ErrNotFound},
+		{"deleted", 4, 2, types.LadderQuoteResp{}, model.ErrNotFound},
+		{"missing", 6, 2, types.LadderQuoteResp{}, model.ErrNotFound},
+		{"broken ladders", 5, 2, types.LadderQuoteResp{}, ErrInternal},
+	}
+	for _, tt := range tests {
+		resp, err := l.LadderQuote(types.LadderQuoteReq{ProductId: tt.productId, Qty: tt.qty})
+		if err != tt.err {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && *resp != tt.want {
+			t.Errorf("%s: quote = %+v, want %+v", tt.name, *resp, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type PortalProductDetailLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewPortalProductDetailLogic(ctx context.Context, svcCtx *svc.ServiceContext) PortalProductDetailLogic {
+	return PortalProductDetailLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// PortalProductDetail returns the detail of a product on sale. Details are
+// cached and shared between requests, never modify them.
+func (l *PortalProductDetailLogic) PortalProductDetail(req types.PortalProductDetailReq) (*types.PortalProductDetailResp, error) {
+	resp, err := l.svcCtx.DetailCache.Take(req.ProductId, func() (interface{}, error) {
+		return l.detail(req.ProductId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*types.PortalProductDetailResp), nil
+}
+
+func (l *PortalProductDetailLogic) detail(productId int64) (*types.PortalProductDetailResp, error) {
+	product, err := l.svcCtx.ProductModel.FindOne(productId)
+	if err != nil {
+		return nil, err
+	}
+	if product.DeleteStatus.Int64 == 1 || product.PublishStatus.Int64 != 1 {
+		return nil, model.ErrNotFound
+	}
+
+	ids := []int64{productId}
+	skus, err := l.svcCtx.SkuStockModel.FindByProductIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	values, err := l.svcCtx.AttributeValueModel.FindByProductIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	ladders, err := l.svcCtx.LadderModel.FindByProductId(productId)
+	if err != nil {
+		return nil, err
+	}
+	reductions, err := l.svcCtx.FullReductionModel.FindByProductId(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.PortalProductDetailResp{
+		Product:         convert.Product(product),
+		Skus:            convert.Skus(skus),
+		AttributeValues: convert.AttributeValues(values),
+		Ladders:         convert.Ladders(ladders),
+		FullReductions:  convert.FullReductions(reductions),
+	}, nil
+}
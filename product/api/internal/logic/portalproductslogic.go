@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"context"
+	"database/sql"
+
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+const maxPortalProductsLimit = 100
+
+type PortalProductsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewPortalProductsLogic(ctx context.Context, svcCtx *svc.ServiceContext) PortalProductsLogic {
+	return PortalProductsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PortalProductsLogic) PortalProducts(req types.PortalProductsReq) (*types.PortalProductsResp, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxPortalProductsLimit {
+		limit = maxPortalProductsLimit
+	}
+
+	products, next, err := l.svcCtx.ProductModel.FindPage(model.PmsProductPageQuery{
+		OrderBy:           req.OrderBy,
+		Desc:              req.Desc,
+		DeleteStatus:      sql.NullInt64{Int64: 0, Valid: true},
+		PublishStatus:     sql.NullInt64{Int64: 1, Valid: true},
+		NewStatus:         convert.StatusFilter(req.NewStatus),
+		RecommandStatus:   convert.StatusFilter(req.RecommendStatus),
+		PreviewStatus:     convert.StatusFilter(req.PreviewStatus),
+		BrandId:           req.BrandId,
+		ProductCategoryId: req.ProductCategoryId,
+	}, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.PortalProductsResp{
+		Products:   convert.ProductSummaries(products),
+		NextCursor: next,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/publish"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type PreviewProductLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewPreviewProductLogic(ctx context.Context, svcCtx *svc.ServiceContext) PreviewProductLogic {
+	return PreviewProductLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PreviewProductLogic) PreviewProduct(req types.ProductTransitionReq) (*types.ProductStateResp, error) {
+	state, err := l.svcCtx.Workflow.Transition(req.Id, publish.ActionPreview, auth.OperatorFrom(l.ctx), req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ProductStateResp{
+		Id:    req.Id,
+		State: state,
+	}, nil
+}
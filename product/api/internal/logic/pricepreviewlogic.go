@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/pricing"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type PricePreviewLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewPricePreviewLogic(ctx context.Context, svcCtx *svc.ServiceContext) PricePreviewLogic {
+	return PricePreviewLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PricePreviewLogic) PricePreview(req types.PricePreviewReq) (*types.PricePreviewResp, error) {
+	items := make([]pricing.PreviewItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = pricing.PreviewItem{SkuId: item.SkuId, Quantity: item.Quantity}
+	}
+
+	preview, err := l.svcCtx.Previewer.Preview(items)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.PricePreviewResp{
+		Lines:      make([]types.PricePreviewLine, len(preview.Lines)),
+		Subtotal:   preview.Subtotal.String(),
+		Saving:     preview.Saving.String(),
+		Total:      preview.Total.String(),
+		GiftPoint:  preview.GiftPoint,
+		GiftGrowth: preview.GiftGrowth,
+	}
+	for i, line := range preview.Lines {
+		out := &resp.Lines[i]
+		out.SkuId = line.SkuId
+		out.ProductId = line.ProductId
+		out.SkuCode = line.SkuCode
+		out.ProductName = line.ProductName
+		out.Quantity = line.Quantity
+		out.Available = line.Available
+		out.InStock = line.InStock
+		out.GiftPoint = line.GiftPoint
+		out.GiftGrowth = line.GiftGrowth
+		out.Error = line.Error
+		if b := line.Breakdown; b != nil {
+			out.UnitPrice = b.UnitPrice.String()
+			out.Subtotal = b.Subtotal.String()
+			out.PromotionSaving = b.PromotionSaving.String()
+			out.LadderSaving = b.LadderSaving.String()
+			out.ReductionSaving = b.ReductionSaving.String()
+			out.Total = b.Total.String()
+		}
+	}
+
+	return resp, nil
+}
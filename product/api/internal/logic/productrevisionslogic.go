@@ -0,0 +1,51 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/audit"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+const maxRevisionLimit = 100
+
+type ProductRevisionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewProductRevisionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) ProductRevisionsLogic {
+	return ProductRevisionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ProductRevisionsLogic) ProductRevisions(req types.ProductRevisionsReq) (*types.ProductRevisionsResp, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxRevisionLimit {
+		limit = maxRevisionLimit
+	}
+
+	revisions, err := l.svcCtx.Revisions(audit.ActorSystem).List(req.Id, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.ProductRevisionsResp{
+		Revisions: make([]types.ProductRevision, 0, len(revisions)),
+	}
+	for _, revision := range revisions {
+		resp.Revisions = append(resp.Revisions, toProductRevision(revision))
+	}
+	if len(revisions) == limit {
+		resp.NextCursor = revisions[len(revisions)-1].Id
+	}
+
+	return resp, nil
+}
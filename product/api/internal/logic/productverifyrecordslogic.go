@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+const maxVerifyRecordLimit = 100
+
+type ProductVerifyRecordsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewProductVerifyRecordsLogic(ctx context.Context, svcCtx *svc.ServiceContext) ProductVerifyRecordsLogic {
+	return ProductVerifyRecordsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ProductVerifyRecordsLogic) ProductVerifyRecords(req types.VerifyRecordsReq) (*types.VerifyRecordsResp, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxVerifyRecordLimit {
+		limit = maxVerifyRecordLimit
+	}
+
+	records, err := l.svcCtx.Workflow.History(req.Id, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.VerifyRecordsResp{
+		Records: make([]types.VerifyRecord, 0, len(records)),
+	}
+	for _, record := range records {
+		resp.Records = append(resp.Records, types.VerifyRecord{
+			Id:         record.Id,
+			Action:     record.Action,
+			FromState:  record.FromState,
+			ToState:    record.ToState,
+			Detail:     record.Detail,
+			Operator:   record.Operator,
+			CreateTime: record.CreateTime.Unix(),
+		})
+	}
+	if len(records) == limit {
+		resp.NextCursor = records[len(records)-1].Id
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,50 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+const maxPublishScheduleLimit = 100
+
+type PublishSchedulesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewPublishSchedulesLogic(ctx context.Context, svcCtx *svc.ServiceContext) PublishSchedulesLogic {
+	return PublishSchedulesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PublishSchedulesLogic) PublishSchedules(req types.PublishSchedulesReq) (*types.PublishSchedulesResp, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxPublishScheduleLimit {
+		limit = maxPublishScheduleLimit
+	}
+
+	schedules, err := l.svcCtx.PublishScheduler.Upcoming(req.ProductId, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.PublishSchedulesResp{
+		Schedules: make([]types.PublishSchedule, 0, len(schedules)),
+	}
+	for _, schedule := range schedules {
+		resp.Schedules = append(resp.Schedules, toPublishSchedule(schedule))
+	}
+	if len(schedules) == limit {
+		resp.NextCursor = schedules[len(schedules)-1].Id
+	}
+
+	return resp, nil
+}
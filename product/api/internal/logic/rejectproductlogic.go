@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/publish"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type RejectProductLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRejectProductLogic(ctx context.Context, svcCtx *svc.ServiceContext) RejectProductLogic {
+	return RejectProductLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RejectProductLogic) RejectProduct(req types.ProductTransitionReq) (*types.ProductStateResp, error) {
+	state, err := l.svcCtx.Workflow.Transition(req.Id, publish.ActionReject, auth.OperatorFrom(l.ctx), req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ProductStateResp{
+		Id:    req.Id,
+		State: state,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+var errEmptyOrderSn = errors.New("orderSn must not be empty")
+
+type ReserveStockLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReserveStockLogic(ctx context.Context, svcCtx *svc.ServiceContext) ReserveStockLogic {
+	return ReserveStockLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ReserveStockLogic) ReserveStock(req types.ReserveStockReq) (*types.ReservationResp, error) {
+	if len(req.OrderSn) == 0 {
+		return nil, errEmptyOrderSn
+	}
+
+	conf := l.svcCtx.Config.Reservation
+	ttl := time.Duration(req.Ttl) * time.Second
+	if ttl <= 0 {
+		ttl = conf.Ttl
+	}
+	if ttl > conf.MaxTtl {
+		ttl = conf.MaxTtl
+	}
+
+	reservation, err := l.svcCtx.Reserver.Reserve(req.OrderSn, req.SkuId, req.Quantity, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ReservationResp{
+		Id:         reservation.Id,
+		OrderSn:    reservation.OrderSn,
+		SkuId:      reservation.SkuId,
+		Quantity:   reservation.Quantity,
+		Status:     reservation.Status,
+		ExpireTime: reservation.ExpireTime.Unix(),
+	}, nil
+}
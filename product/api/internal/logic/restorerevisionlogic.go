@@ -0,0 +1,36 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type RestoreRevisionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRestoreRevisionLogic(ctx context.Context, svcCtx *svc.ServiceContext) RestoreRevisionLogic {
+	return RestoreRevisionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RestoreRevisionLogic) RestoreRevision(req types.RestoreRevisionReq) (*types.ProductRevision, error) {
+	operator := auth.OperatorFrom(l.ctx)
+	revision, err := l.svcCtx.Revisions(operator).Restore(req.Id, req.Revision, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toProductRevision(revision)
+	return &resp, nil
+}
@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/audit"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type RevisionDiffLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRevisionDiffLogic(ctx context.Context, svcCtx *svc.ServiceContext) RevisionDiffLogic {
+	return RevisionDiffLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *RevisionDiffLogic) RevisionDiff(req types.RevisionDiffReq) (*types.RevisionDiffResp, error) {
+	changes, err := l.svcCtx.Revisions(audit.ActorSystem).Diff(req.Id, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.RevisionDiffResp{
+		Changes: make([]types.AuditChange, 0, len(changes)),
+	}
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, types.AuditChange{
+			Field:  change.Field,
+			Before: stringOrEmpty(change.Before),
+			After:  stringOrEmpty(change.After),
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,46 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type SaveRevisionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSaveRevisionLogic(ctx context.Context, svcCtx *svc.ServiceContext) SaveRevisionLogic {
+	return SaveRevisionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SaveRevisionLogic) SaveRevision(req types.SaveRevisionReq) (*types.ProductRevision, error) {
+	operator := auth.OperatorFrom(l.ctx)
+	revision, err := l.svcCtx.Revisions(operator).Save(req.Id, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toProductRevision(revision)
+	return &resp, nil
+}
+
+func toProductRevision(revision *model.PmsProductRevision) types.ProductRevision {
+	return types.ProductRevision{
+		Id:         revision.Id,
+		Revision:   revision.Revision,
+		Actor:      revision.Actor,
+		CreateTime: revision.CreateTime.Unix(),
+	}
+}
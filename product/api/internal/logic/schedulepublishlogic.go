@@ -0,0 +1,48 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/auth"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type SchedulePublishLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSchedulePublishLogic(ctx context.Context, svcCtx *svc.ServiceContext) SchedulePublishLogic {
+	return SchedulePublishLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SchedulePublishLogic) SchedulePublish(req types.SchedulePublishReq) (*types.PublishSchedule, error) {
+	schedule, err := l.svcCtx.PublishScheduler.Schedule(req.Id, req.Action, time.Unix(req.Time, 0), auth.OperatorFrom(l.ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toPublishSchedule(schedule)
+	return &resp, nil
+}
+
+func toPublishSchedule(schedule *model.PmsProductPublishSchedule) types.PublishSchedule {
+	return types.PublishSchedule{
+		Id:        schedule.Id,
+		ProductId: schedule.ProductId,
+		Action:    schedule.Action,
+		Time:      schedule.ScheduleTime.Unix(),
+		Status:    schedule.Status,
+		Operator:  schedule.Operator,
+	}
+}
@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+	"malltmp/product/pricing"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type SetPromotionWindowLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSetPromotionWindowLogic(ctx context.Context, svcCtx *svc.ServiceContext) SetPromotionWindowLogic {
+	return SetPromotionWindowLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *SetPromotionWindowLogic) SetPromotionWindow(req types.PromotionWindowReq) error {
+	start, end := time.Unix(req.StartTime, 0), time.Unix(req.EndTime, 0)
+	if err := pricing.ValidateWindow(req.PromotionType, start, end); err != nil {
+		return err
+	}
+	if _, err := l.svcCtx.ProductModel.FindOne(req.Id); err != nil {
+		return err
+	}
+
+	return l.svcCtx.ProductModel.SetPromotionWindow(nil, req.Id, req.PromotionType, start, end)
+}
@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+const maxLedgerLimit = 100
+
+type StockLedgerLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewStockLedgerLogic(ctx context.Context, svcCtx *svc.ServiceContext) StockLedgerLogic {
+	return StockLedgerLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *StockLedgerLogic) StockLedger(req types.StockLedgerReq) (*types.StockLedgerResp, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxLedgerLimit {
+		limit = maxLedgerLimit
+	}
+
+	entries, err := l.svcCtx.Inventory.History(req.SkuId, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.StockLedgerResp{
+		Entries: make([]types.StockLedgerEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, types.StockLedgerEntry{
+			Id:             entry.Id,
+			Reason:         entry.Reason,
+			StockDelta:     entry.StockDelta,
+			LockStockDelta: entry.LockStockDelta,
+			Operator:       entry.Operator,
+			RefId:          entry.RefId,
+			CreateTime:     entry.CreateTime.Unix(),
+		})
+	}
+	if len(entries) == limit {
+		resp.NextCursor = entries[len(entries)-1].Id
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/api/internal/svc"
+	"malltmp/product/api/internal/types"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type VerifyStockLedgerLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewVerifyStockLedgerLogic(ctx context.Context, svcCtx *svc.ServiceContext) VerifyStockLedgerLogic {
+	return VerifyStockLedgerLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *VerifyStockLedgerLogic) VerifyStockLedger(req types.VerifyStockLedgerReq) (*types.VerifyStockLedgerResp, error) {
+	v, err := l.svcCtx.Inventory.Verify(req.SkuId)
+	if err != nil {
+		return nil, err
+	}
+	if !v.Consistent() {
+		l.Errorf("stock ledger of sku %d replays to %d/%d, stored %d/%d",
+			v.SkuId, v.LedgerStock, v.LedgerLockStock, v.Stock, v.LockStock)
+	}
+
+	return &types.VerifyStockLedgerResp{
+		SkuId:           v.SkuId,
+		Entries:         v.Entries,
+		LedgerStock:     v.LedgerStock,
+		LedgerLockStock: v.LedgerLockStock,
+		Stock:           v.Stock,
+		LockStock:       v.LockStock,
+		Consistent:      v.Consistent(),
+	}, nil
+}
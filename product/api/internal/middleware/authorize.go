@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+// authorize lets the request through to next only if the role of its token
+// grants permission, answering 403 otherwise.
+func authorize(permission auth.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		role := auth.RoleFrom(r.Context())
+		if !role.Can(permission) {
+			logx.WithContext(r.Context()).Infof("operator %s of role %q is denied %s %s",
+				auth.OperatorFrom(r.Context()), role, r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
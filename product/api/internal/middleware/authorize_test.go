@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"malltmp/product/auth"
+)
+
+func TestAuthorize(t *testing.T) {
+	middlewares := map[string]func(http.HandlerFunc) http.HandlerFunc{
+		"read":    NewCatalogReadMiddleware().Handle,
+		"write":   NewCatalogWriteMiddleware().Handle,
+		"review":  NewCatalogReviewMiddleware().Handle,
+		"manage":  NewCatalogManageMiddleware().Handle,
+		"reserve": NewStockReserveMiddleware().Handle,
+	}
+	tests := []struct {
+		role auth.Role
+		// the middlewares letting the role through, the others answer 403
+		allowed []string
+	}{
+		{auth.RoleViewer, []string{"read"}},
+		{auth.RoleEditor, []string{"read", "write"}},
+		{auth.RoleAuditor, []string{"read", "review"}},
+		{auth.RoleAdmin, []string{"read", "write", "review", "manage", "reserve"}},
+		{auth.RoleOrder, []string{"reserve"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		allowed := make(map[string]bool, len(tt.allowed))
+		for _, name := range tt.allowed {
+			allowed[name] = true
+		}
+
+		for name, handle := range middlewares {
+			var called bool
+			h := handle(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if len(tt.role) > 0 {
+				r = r.WithContext(context.WithValue(r.Context(), auth.ClaimRole, string(tt.role)))
+			}
+			w := httptest.NewRecorder()
+			h(w, r)
+
+			want := http.StatusForbidden
+			if allowed[name] {
+				want = http.StatusOK
+			}
+			if w.Code != want || called != allowed[name] {
+				t.Errorf("role %q through %s: status %d, called %t, want %d", tt.role, name, w.Code, called, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"malltmp/product/auth"
+)
+
+// CatalogManageMiddleware lets through the roles granted auth.PermManage.
+type CatalogManageMiddleware struct {
+}
+
+func NewCatalogManageMiddleware() *CatalogManageMiddleware {
+	return &CatalogManageMiddleware{}
+}
+
+func (m *CatalogManageMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return authorize(auth.PermManage, next)
+}
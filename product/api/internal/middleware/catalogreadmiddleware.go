@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"malltmp/product/auth"
+)
+
+// CatalogReadMiddleware lets through the roles granted auth.PermRead.
+type CatalogReadMiddleware struct {
+}
+
+func NewCatalogReadMiddleware() *CatalogReadMiddleware {
+	return &CatalogReadMiddleware{}
+}
+
+func (m *CatalogReadMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return authorize(auth.PermRead, next)
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"malltmp/product/auth"
+)
+
+// CatalogReviewMiddleware lets through the roles granted auth.PermReview.
+type CatalogReviewMiddleware struct {
+}
+
+func NewCatalogReviewMiddleware() *CatalogReviewMiddleware {
+	return &CatalogReviewMiddleware{}
+}
+
+func (m *CatalogReviewMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return authorize(auth.PermReview, next)
+}
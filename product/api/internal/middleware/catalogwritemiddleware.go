@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"malltmp/product/auth"
+)
+
+// CatalogWriteMiddleware lets through the roles granted auth.PermWrite.
+type CatalogWriteMiddleware struct {
+}
+
+func NewCatalogWriteMiddleware() *CatalogWriteMiddleware {
+	return &CatalogWriteMiddleware{}
+}
+
+func (m *CatalogWriteMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return authorize(auth.PermWrite, next)
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"malltmp/product/auth"
+)
+
+// StockReserveMiddleware lets through the roles granted auth.PermReserve.
+type StockReserveMiddleware struct {
+}
+
+func NewStockReserveMiddleware() *StockReserveMiddleware {
+	return &StockReserveMiddleware{}
+}
+
+func (m *StockReserveMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return authorize(auth.PermReserve, next)
+}
@@ -0,0 +1,183 @@
+package svc
+
+import (
+	"malltmp/product/api/internal/config"
+	"malltmp/product/api/internal/convert"
+	"malltmp/product/api/internal/middleware"
+	"malltmp/product/audit"
+	"malltmp/product/cache"
+	"malltmp/product/catalog"
+	"malltmp/product/event"
+	"malltmp/product/loader"
+	"malltmp/product/model"
+	"malltmp/product/pricing"
+	"malltmp/product/publish"
+	"malltmp/product/stock"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+	"github.com/tal-tech/go-zero/rest"
+)
+
+type ServiceContext struct {
+	Config                config.Config
+	Calculator            pricing.Calculator
+	Previewer             *pricing.Previewer
+	PromotionWindowWorker *pricing.PromotionWindowWorker
+	Recorder              *audit.Recorder
+	Bus                   *event.Bus
+	DetailCache           *cache.ProductCache
+	ProductFilter         *cache.ProductFilter
+	ProductModel          model.PmsProductModel
+	SkuStockModel         model.PmsSkuStockModel
+	LadderModel           model.PmsProductLadderModel
+	FullReductionModel    model.PmsProductFullReductionModel
+	BrandModel            model.PmsBrandModel
+	AttributeModel        model.PmsProductAttributeModel
+	AttributeValueModel   model.PmsProductAttributeValueModel
+	Workflow              *publish.Workflow
+	PublishScheduler      *publish.Scheduler
+	Inventory             *stock.Inventory
+	Reserver              *stock.Reserver
+	LowStockScanner       *stock.LowStockScanner
+	ReservationExpirer    *stock.ReservationExpirer
+	CatalogRead           rest.Middleware
+	CatalogWrite          rest.Middleware
+	CatalogReview         rest.Middleware
+	CatalogManage         rest.Middleware
+	StockReserve          rest.Middleware
+
+	conn          sqlx.SqlConn
+	ledgerModel   model.PmsSkuStockLedgerModel
+	revisionModel model.PmsProductRevisionModel
+}
+
+func NewServiceContext(c config.Config) *ServiceContext {
+	mode, err := pricing.ParseReductionMode(c.Pricing.ReductionMode)
+	logx.Must(err)
+	logx.Must(convert.Check())
+
+	bus := event.NewBus()
+	conn := event.NewConn(sqlx.NewMysql(c.Mysql.DataSource), bus)
+	ctx := &ServiceContext{
+		Config:        c,
+		Calculator:    pricing.Calculator{ReductionMode: mode},
+		Recorder:      audit.NewRecorder(conn, model.NewPmsAuditLogModel(conn)),
+		Bus:           bus,
+		conn:          conn,
+		ledgerModel:   model.NewPmsSkuStockLedgerModel(conn),
+		revisionModel: model.NewPmsProductRevisionModel(conn),
+		CatalogRead:   middleware.NewCatalogReadMiddleware().Handle,
+		CatalogWrite:  middleware.NewCatalogWriteMiddleware().Handle,
+		CatalogReview: middleware.NewCatalogReviewMiddleware().Handle,
+		CatalogManage: middleware.NewCatalogManageMiddleware().Handle,
+		StockReserve:  middleware.NewStockReserveMiddleware().Handle,
+	}
+	// the models below write on behalf of the system, the back office
+	// writes through the models of its operator
+	ctx.LadderModel = ctx.LadderModelFor(audit.ActorSystem)
+	ctx.FullReductionModel = ctx.FullReductionModelFor(audit.ActorSystem)
+	ctx.BrandModel = ctx.BrandModelFor(audit.ActorSystem)
+	ctx.AttributeModel = ctx.AttributeModelFor(audit.ActorSystem)
+	ctx.AttributeValueModel = ctx.AttributeValueModelFor(audit.ActorSystem)
+	productModel, skuStockModel := ctx.CatalogModels(audit.ActorSystem)
+	ctx.ProductModel = loader.NewProductModel(productModel, c.Loader.Wait, c.Loader.MaxBatch)
+	ctx.SkuStockModel = loader.NewSkuStockModel(skuStockModel, c.Loader.Wait, c.Loader.MaxBatch)
+
+	// a product told changed exists, or did, so it's added to the filter
+	// before its detail is dropped, the not found included
+	ctx.ProductFilter = cache.NewProductFilter(productModel, c.ProductFilter.Capacity,
+		c.ProductFilter.FalsePositive, c.ProductFilter.RefreshInterval)
+	ctx.DetailCache = cache.NewProductCache("product-detail", c.DetailCache.Expire,
+		cache.WithLimit(c.DetailCache.Limit), cache.WithNotFoundExpire(c.DetailCache.NotFoundExpire),
+		cache.WithFilter(ctx.ProductFilter))
+	bus.Subscribe(ctx.ProductFilter.Add)
+	bus.Subscribe(ctx.DetailCache.Del)
+
+	ctx.Previewer = pricing.NewPreviewer(ctx.Calculator, ctx.ProductModel, ctx.SkuStockModel, ctx.LadderModel,
+		ctx.FullReductionModel)
+	ctx.PromotionWindowWorker = pricing.NewPromotionWindowWorker(productModel, c.Pricing.WindowInterval)
+	ctx.Workflow = publish.NewWorkflow(conn, productModel, model.NewPmsProductVerifyRecordModel(conn))
+	ctx.PublishScheduler = publish.NewScheduler(conn, ctx.Workflow, productModel,
+		model.NewPmsProductPublishScheduleModel(conn), c.Publish.ScheduleInterval)
+	ctx.Inventory = stock.NewInventory(conn, skuStockModel, ctx.ledgerModel)
+	ctx.Reserver = stock.NewReserver(conn, ctx.Inventory, model.NewPmsStockReservationModel(conn))
+	ctx.ReservationExpirer = stock.NewReservationExpirer(ctx.Reserver, c.Reservation.ExpireInterval)
+
+	if c.LowStock.Interval > 0 {
+		notifier := stock.NewLogNotifier()
+		if len(c.LowStock.Webhook) > 0 {
+			notifier = stock.NewMultiNotifier(notifier,
+				stock.NewWebhookNotifier(c.LowStock.Webhook, c.LowStock.WebhookTimeout))
+		}
+		ctx.LowStockScanner = stock.NewLowStockScanner(ctx.ProductModel, ctx.SkuStockModel,
+			notifier, c.LowStock.Interval)
+	}
+
+	return ctx
+}
+
+// CatalogModels returns the product and SKU models writing on behalf of
+// actor, with their writes audited, stock ledgered and rolled up, and the
+// products written told on the bus.
+func (ctx *ServiceContext) CatalogModels(actor string) (model.PmsProductModel, model.PmsSkuStockModel) {
+	productModel := publish.NewGuardedProductModel(ctx.conn, audit.NewProductModel(
+		event.NewProductModel(model.NewPmsProductModel(ctx.conn), ctx.Bus), ctx.Recorder, actor))
+	skuStockModel := stock.NewRollupSkuStockModel(ctx.conn, stock.NewLedgerSkuStockModel(ctx.conn,
+		audit.NewSkuStockModel(event.NewSkuStockModel(model.NewPmsSkuStockModel(ctx.conn), ctx.Bus),
+			ctx.Recorder, actor), ctx.ledgerModel), productModel)
+
+	return productModel, skuStockModel
+}
+
+// BrandModelFor returns the brand model writing on behalf of actor, audited.
+func (ctx *ServiceContext) BrandModelFor(actor string) model.PmsBrandModel {
+	return audit.NewBrandModel(model.NewPmsBrandModel(ctx.conn), ctx.Recorder, actor)
+}
+
+// AttributeModelFor returns the attribute model writing on behalf of actor, audited.
+func (ctx *ServiceContext) AttributeModelFor(actor string) model.PmsProductAttributeModel {
+	return audit.NewAttributeModel(model.NewPmsProductAttributeModel(ctx.conn), ctx.Recorder, actor)
+}
+
+// AttributeValueModelFor returns the attribute value model writing on behalf
+// of actor, audited, and the products written told on the bus.
+func (ctx *ServiceContext) AttributeValueModelFor(actor string) model.PmsProductAttributeValueModel {
+	return audit.NewAttributeValueModel(event.NewAttributeValueModel(
+		model.NewPmsProductAttributeValueModel(ctx.conn), ctx.Bus), ctx.Recorder, actor)
+}
+
+// LadderModelFor returns the ladder model writing on behalf of actor,
+// audited, and the products written told on the bus.
+func (ctx *ServiceContext) LadderModelFor(actor string) model.PmsProductLadderModel {
+	return audit.NewLadderModel(event.NewLadderModel(model.NewPmsProductLadderModel(ctx.conn), ctx.Bus),
+		ctx.Recorder, actor)
+}
+
+// FullReductionModelFor returns the full reduction model writing on behalf
+// of actor, audited, and the products written told on the bus.
+func (ctx *ServiceContext) FullReductionModelFor(actor string) model.PmsProductFullReductionModel {
+	return audit.NewFullReductionModel(event.NewFullReductionModel(
+		model.NewPmsProductFullReductionModel(ctx.conn), ctx.Bus), ctx.Recorder, actor)
+}
+
+// Importer returns an importer creating products on behalf of actor.
+func (ctx *ServiceContext) Importer(actor string) *catalog.Importer {
+	productModel, skuStockModel := ctx.CatalogModels(actor)
+	return catalog.NewImporter(ctx.conn, productModel, skuStockModel, ctx.BrandModel, ctx.Revisions(actor), actor)
+}
+
+// Revisions returns the product revisions, saved and restored on behalf of actor.
+func (ctx *ServiceContext) Revisions(actor string) *catalog.Revisions {
+	productModel, skuStockModel := ctx.CatalogModels(actor)
+	return catalog.NewRevisions(ctx.conn, productModel, skuStockModel, ctx.AttributeValueModelFor(actor),
+		ctx.LadderModelFor(actor), ctx.FullReductionModelFor(actor), ctx.revisionModel)
+}
+
+// Editor returns the editor of products on behalf of actor.
+func (ctx *ServiceContext) Editor(actor string) *catalog.Editor {
+	productModel, skuStockModel := ctx.CatalogModels(actor)
+	return catalog.NewEditor(ctx.conn, productModel, skuStockModel, ctx.BrandModel,
+		ctx.AttributeValueModelFor(actor), ctx.LadderModelFor(actor), ctx.FullReductionModelFor(actor),
+		ctx.Revisions(actor), actor)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"malltmp/product/api/internal/config"
+	"malltmp/product/api/internal/handler"
+	"malltmp/product/api/internal/svc"
+
+	"github.com/tal-tech/go-zero/core/conf"
+	"github.com/tal-tech/go-zero/core/service"
+	"github.com/tal-tech/go-zero/rest"
+	"github.com/tal-tech/go-zero/rest/httpx"
+)
+
+var configFile = flag.String("f", "etc/product-api.yaml", "the config file")
+
+func main() {
+	flag.Parse()
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+
+	ctx := svc.NewServiceContext(c)
+	server := rest.MustNewServer(c.RestConf)
+	handler.RegisterHandlers(server, ctx)
+	httpx.SetErrorHandler(handler.ErrorHandler)
+
+	group := service.NewServiceGroup()
+	defer group.Stop()
+	group.Add(server)
+	group.Add(ctx.ReservationExpirer)
+	group.Add(ctx.PublishScheduler)
+	group.Add(ctx.PromotionWindowWorker)
+	group.Add(ctx.ProductFilter)
+	group.Add(ctx.DetailCache)
+	if ctx.LowStockScanner != nil {
+		group.Add(ctx.LowStockScanner)
+	}
+
+	fmt.Printf("Starting server at %s:%d...\n", c.Host, c.Port)
+	group.Start()
+}
@@ -0,0 +1,696 @@
+package audit
+
+import (
+	"database/sql"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+// The models below record each write in the transaction of the write, the
+// row before it read and locked in that transaction. The writes without a
+// session run in a new transaction, so do the ones given a nil session.
+
+type (
+	productModel struct {
+		model.PmsProductModel
+		recorder *Recorder
+		actor    string
+	}
+
+	skuStockModel struct {
+		model.PmsSkuStockModel
+		recorder *Recorder
+		actor    string
+	}
+
+	brandModel struct {
+		model.PmsBrandModel
+		recorder *Recorder
+		actor    string
+	}
+
+	attributeModel struct {
+		model.PmsProductAttributeModel
+		recorder *Recorder
+		actor    string
+	}
+
+	ladderModel struct {
+		model.PmsProductLadderModel
+		recorder *Recorder
+		actor    string
+	}
+
+	fullReductionModel struct {
+		model.PmsProductFullReductionModel
+		recorder *Recorder
+		actor    string
+	}
+
+	attributeValueModel struct {
+		model.PmsProductAttributeValueModel
+		recorder *Recorder
+		actor    string
+	}
+)
+
+// NewProductModel wraps m to record its writes as done by actor: inserts,
+// updates, deletes, and the changes of status, promotion window and rolled
+// up stock.
+func NewProductModel(m model.PmsProductModel, recorder *Recorder, actor string) model.PmsProductModel {
+	return &productModel{
+		PmsProductModel: m,
+		recorder:        recorder,
+		actor:           actor,
+	}
+}
+
+func (m *productModel) Insert(data model.PmsProduct) (ret sql.Result, err error) {
+	err = m.recorder.transact(nil, func(session sqlx.Session) error {
+		ret, err = m.TxInsert(session, data)
+		return err
+	})
+	return
+}
+
+func (m *productModel) TxInsert(session sqlx.Session, data model.PmsProduct) (sql.Result, error) {
+	ret, err := m.PmsProductModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+	id, err := ret.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	// the row as written, with the column defaults in place of NULLs
+	inserted, err := m.PmsProductModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, m.recorder.Record(session, TableProduct, id, ActionInsert, m.actor, nil, inserted)
+}
+
+func (m *productModel) Update(data model.PmsProduct) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *productModel) TxUpdate(session sqlx.Session, data model.PmsProduct) error {
+	old, err := m.PmsProductModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+	updated, err := m.PmsProductModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableProduct, data.Id, ActionUpdate, m.actor, old, updated)
+}
+
+func (m *productModel) Delete(id int64) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxDelete(session, id)
+	})
+}
+
+func (m *productModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsProductModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableProduct, id, ActionDelete, m.actor, old, nil)
+}
+
+func (m *productModel) TxUpdateStatus(session sqlx.Session, id, verifyStatus, publishStatus, previewStatus int64) error {
+	return m.changed(session, id, func(session sqlx.Session) error {
+		return m.PmsProductModel.TxUpdateStatus(session, id, verifyStatus, publishStatus, previewStatus)
+	})
+}
+
+func (m *productModel) SetPromotionWindow(session sqlx.Session, id, promotionType int64, start, end time.Time) error {
+	return m.changed(session, id, func(session sqlx.Session) error {
+		return m.PmsProductModel.SetPromotionWindow(session, id, promotionType, start, end)
+	})
+}
+
+func (m *productModel) ClearPromotionWindow(session sqlx.Session, id int64) error {
+	return m.changed(session, id, func(session sqlx.Session) error {
+		return m.PmsProductModel.ClearPromotionWindow(session, id)
+	})
+}
+
+func (m *productModel) StartPromotionWindow(session sqlx.Session, id int64) error {
+	return m.changed(session, id, func(session sqlx.Session) error {
+		return m.PmsProductModel.StartPromotionWindow(session, id)
+	})
+}
+
+func (m *productModel) EndPromotionWindow(session sqlx.Session, id int64) error {
+	return m.changed(session, id, func(session sqlx.Session) error {
+		return m.PmsProductModel.EndPromotionWindow(session, id)
+	})
+}
+
+func (m *productModel) RollupSkus(session sqlx.Session, id int64) error {
+	return m.changed(session, id, func(session sqlx.Session) error {
+		return m.PmsProductModel.RollupSkus(session, id)
+	})
+}
+
+// changed records the update of product id by write, comparing the row read
+// before and after it.
+func (m *productModel) changed(session sqlx.Session, id int64, write func(session sqlx.Session) error) error {
+	return m.recorder.transact(session, func(session sqlx.Session) error {
+		old, err := m.PmsProductModel.TxFindOneForUpdate(session, id)
+		switch err {
+		case nil:
+		case model.ErrNotFound:
+			// nothing to record, write says what it does without the row
+			return write(session)
+		default:
+			return err
+		}
+
+		if err := write(session); err != nil {
+			return err
+		}
+		updated, err := m.PmsProductModel.TxFindOneForUpdate(session, id)
+		if err != nil {
+			return err
+		}
+
+		return m.recorder.Record(session, TableProduct, id, ActionUpdate, m.actor, old, updated)
+	})
+}
+
+// NewSkuStockModel wraps m to record its writes as done by actor, stock
+// moves included.
+func NewSkuStockModel(m model.PmsSkuStockModel, recorder *Recorder, actor string) model.PmsSkuStockModel {
+	return &skuStockModel{
+		PmsSkuStockModel: m,
+		recorder:         recorder,
+		actor:            actor,
+	}
+}
+
+func (m *skuStockModel) Insert(data model.PmsSkuStock) (ret sql.Result, err error) {
+	err = m.recorder.transact(nil, func(session sqlx.Session) error {
+		ret, err = m.TxInsert(session, data)
+		return err
+	})
+	return
+}
+
+func (m *skuStockModel) TxInsert(session sqlx.Session, data model.PmsSkuStock) (sql.Result, error) {
+	ret, err := m.PmsSkuStockModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, m.recorder.inserted(session, TableSkuStock, m.actor, ret, data)
+}
+
+func (m *skuStockModel) Update(data model.PmsSkuStock) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *skuStockModel) TxUpdate(session sqlx.Session, data model.PmsSkuStock) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableSkuStock, data.Id, ActionUpdate, m.actor, old, data)
+}
+
+func (m *skuStockModel) UpdateInfo(data model.PmsSkuStock) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxUpdateInfo(session, data)
+	})
+}
+
+func (m *skuStockModel) TxUpdateInfo(session sqlx.Session, data model.PmsSkuStock) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.TxUpdateInfo(session, data); err != nil {
+		return err
+	}
+
+	data.Stock, data.LockStock, data.Sale = old.Stock, old.LockStock, old.Sale
+	return m.recorder.Record(session, TableSkuStock, data.Id, ActionUpdate, m.actor, old, data)
+}
+
+func (m *skuStockModel) Delete(id int64) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxDelete(session, id)
+	})
+}
+
+func (m *skuStockModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableSkuStock, id, ActionDelete, m.actor, old, nil)
+}
+
+func (m *skuStockModel) LockStock(session sqlx.Session, id, quantity int64) error {
+	return m.moved(session, id, func(session sqlx.Session) error {
+		return m.PmsSkuStockModel.LockStock(session, id, quantity)
+	})
+}
+
+func (m *skuStockModel) UnlockStock(session sqlx.Session, id, quantity int64) error {
+	return m.moved(session, id, func(session sqlx.Session) error {
+		return m.PmsSkuStockModel.UnlockStock(session, id, quantity)
+	})
+}
+
+func (m *skuStockModel) DeductStock(session sqlx.Session, id, quantity int64) error {
+	return m.moved(session, id, func(session sqlx.Session) error {
+		return m.PmsSkuStockModel.DeductStock(session, id, quantity)
+	})
+}
+
+func (m *skuStockModel) AddStock(session sqlx.Session, id, quantity int64) error {
+	return m.moved(session, id, func(session sqlx.Session) error {
+		return m.PmsSkuStockModel.AddStock(session, id, quantity)
+	})
+}
+
+func (m *skuStockModel) SetStock(session sqlx.Session, id, stock, lockStock int64) error {
+	return m.moved(session, id, func(session sqlx.Session) error {
+		return m.PmsSkuStockModel.SetStock(session, id, stock, lockStock)
+	})
+}
+
+// moved records the update of SKU id by move, comparing the row read before
+// and after it.
+func (m *skuStockModel) moved(session sqlx.Session, id int64, move func(session sqlx.Session) error) error {
+	return m.recorder.transact(session, func(session sqlx.Session) error {
+		old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, id)
+		switch err {
+		case nil:
+		case model.ErrNotFound:
+			// nothing to record, move fails as it does without the row
+			return move(session)
+		default:
+			return err
+		}
+
+		if err := move(session); err != nil {
+			return err
+		}
+		moved, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, id)
+		if err != nil {
+			return err
+		}
+
+		return m.recorder.Record(session, TableSkuStock, id, ActionUpdate, m.actor, old, moved)
+	})
+}
+
+// NewBrandModel wraps m to record its Insert, Update and Delete as done by actor.
+func NewBrandModel(m model.PmsBrandModel, recorder *Recorder, actor string) model.PmsBrandModel {
+	return &brandModel{
+		PmsBrandModel: m,
+		recorder:      recorder,
+		actor:         actor,
+	}
+}
+
+func (m *brandModel) Insert(data model.PmsBrand) (ret sql.Result, err error) {
+	err = m.recorder.transact(nil, func(session sqlx.Session) error {
+		ret, err = m.TxInsert(session, data)
+		return err
+	})
+	return
+}
+
+func (m *brandModel) TxInsert(session sqlx.Session, data model.PmsBrand) (sql.Result, error) {
+	ret, err := m.PmsBrandModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, m.recorder.inserted(session, TableBrand, m.actor, ret, data)
+}
+
+func (m *brandModel) Update(data model.PmsBrand) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *brandModel) TxUpdate(session sqlx.Session, data model.PmsBrand) error {
+	old, err := m.PmsBrandModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsBrandModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableBrand, data.Id, ActionUpdate, m.actor, old, data)
+}
+
+func (m *brandModel) Delete(id int64) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxDelete(session, id)
+	})
+}
+
+func (m *brandModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsBrandModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsBrandModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableBrand, id, ActionDelete, m.actor, old, nil)
+}
+
+// NewAttributeModel wraps m to record its Insert, Update and Delete as done by actor.
+func NewAttributeModel(m model.PmsProductAttributeModel, recorder *Recorder, actor string) model.PmsProductAttributeModel {
+	return &attributeModel{
+		PmsProductAttributeModel: m,
+		recorder:                 recorder,
+		actor:                    actor,
+	}
+}
+
+func (m *attributeModel) Insert(data model.PmsProductAttribute) (ret sql.Result, err error) {
+	err = m.recorder.transact(nil, func(session sqlx.Session) error {
+		ret, err = m.TxInsert(session, data)
+		return err
+	})
+	return
+}
+
+func (m *attributeModel) TxInsert(session sqlx.Session, data model.PmsProductAttribute) (sql.Result, error) {
+	ret, err := m.PmsProductAttributeModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, m.recorder.inserted(session, TableAttribute, m.actor, ret, data)
+}
+
+func (m *attributeModel) Update(data model.PmsProductAttribute) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *attributeModel) TxUpdate(session sqlx.Session, data model.PmsProductAttribute) error {
+	old, err := m.PmsProductAttributeModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductAttributeModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableAttribute, data.Id, ActionUpdate, m.actor, old, data)
+}
+
+func (m *attributeModel) Delete(id int64) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxDelete(session, id)
+	})
+}
+
+func (m *attributeModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsProductAttributeModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductAttributeModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableAttribute, id, ActionDelete, m.actor, old, nil)
+}
+
+// NewLadderModel wraps m to record its inserts, updates and deletes as done by actor.
+func NewLadderModel(m model.PmsProductLadderModel, recorder *Recorder, actor string) model.PmsProductLadderModel {
+	return &ladderModel{
+		PmsProductLadderModel: m,
+		recorder:              recorder,
+		actor:                 actor,
+	}
+}
+
+func (m *ladderModel) Insert(data model.PmsProductLadder) (ret sql.Result, err error) {
+	err = m.recorder.transact(nil, func(session sqlx.Session) error {
+		ret, err = m.TxInsert(session, data)
+		return err
+	})
+	return
+}
+
+func (m *ladderModel) TxInsert(session sqlx.Session, data model.PmsProductLadder) (sql.Result, error) {
+	ret, err := m.PmsProductLadderModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, m.recorder.inserted(session, TableLadder, m.actor, ret, data)
+}
+
+func (m *ladderModel) Update(data model.PmsProductLadder) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *ladderModel) TxUpdate(session sqlx.Session, data model.PmsProductLadder) error {
+	old, err := m.PmsProductLadderModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductLadderModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableLadder, data.Id, ActionUpdate, m.actor, old, data)
+}
+
+func (m *ladderModel) Delete(id int64) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxDelete(session, id)
+	})
+}
+
+func (m *ladderModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsProductLadderModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductLadderModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableLadder, id, ActionDelete, m.actor, old, nil)
+}
+
+func (m *ladderModel) TxDeleteByProductId(session sqlx.Session, productId int64) error {
+	old, err := m.PmsProductLadderModel.TxFindByProductId(session, productId)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductLadderModel.TxDeleteByProductId(session, productId); err != nil {
+		return err
+	}
+
+	for _, row := range old {
+		if err := m.recorder.Record(session, TableLadder, row.Id, ActionDelete, m.actor, row, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewFullReductionModel wraps m to record its inserts, updates and deletes as done by actor.
+func NewFullReductionModel(m model.PmsProductFullReductionModel, recorder *Recorder, actor string) model.PmsProductFullReductionModel {
+	return &fullReductionModel{
+		PmsProductFullReductionModel: m,
+		recorder:                     recorder,
+		actor:                        actor,
+	}
+}
+
+func (m *fullReductionModel) Insert(data model.PmsProductFullReduction) (ret sql.Result, err error) {
+	err = m.recorder.transact(nil, func(session sqlx.Session) error {
+		ret, err = m.TxInsert(session, data)
+		return err
+	})
+	return
+}
+
+func (m *fullReductionModel) TxInsert(session sqlx.Session, data model.PmsProductFullReduction) (sql.Result, error) {
+	ret, err := m.PmsProductFullReductionModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, m.recorder.inserted(session, TableFullReduction, m.actor, ret, data)
+}
+
+func (m *fullReductionModel) Update(data model.PmsProductFullReduction) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *fullReductionModel) TxUpdate(session sqlx.Session, data model.PmsProductFullReduction) error {
+	old, err := m.PmsProductFullReductionModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductFullReductionModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableFullReduction, data.Id, ActionUpdate, m.actor, old, data)
+}
+
+func (m *fullReductionModel) Delete(id int64) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxDelete(session, id)
+	})
+}
+
+func (m *fullReductionModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsProductFullReductionModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductFullReductionModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableFullReduction, id, ActionDelete, m.actor, old, nil)
+}
+
+func (m *fullReductionModel) TxDeleteByProductId(session sqlx.Session, productId int64) error {
+	old, err := m.PmsProductFullReductionModel.TxFindByProductId(session, productId)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductFullReductionModel.TxDeleteByProductId(session, productId); err != nil {
+		return err
+	}
+
+	for _, row := range old {
+		if err := m.recorder.Record(session, TableFullReduction, row.Id, ActionDelete, m.actor, row, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewAttributeValueModel wraps m to record its inserts, updates and deletes as done by actor.
+func NewAttributeValueModel(m model.PmsProductAttributeValueModel, recorder *Recorder, actor string) model.PmsProductAttributeValueModel {
+	return &attributeValueModel{
+		PmsProductAttributeValueModel: m,
+		recorder:                      recorder,
+		actor:                         actor,
+	}
+}
+
+func (m *attributeValueModel) Insert(data model.PmsProductAttributeValue) (ret sql.Result, err error) {
+	err = m.recorder.transact(nil, func(session sqlx.Session) error {
+		ret, err = m.TxInsert(session, data)
+		return err
+	})
+	return
+}
+
+func (m *attributeValueModel) TxInsert(session sqlx.Session, data model.PmsProductAttributeValue) (sql.Result, error) {
+	ret, err := m.PmsProductAttributeValueModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, m.recorder.inserted(session, TableAttributeValue, m.actor, ret, data)
+}
+
+func (m *attributeValueModel) Update(data model.PmsProductAttributeValue) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *attributeValueModel) TxUpdate(session sqlx.Session, data model.PmsProductAttributeValue) error {
+	old, err := m.PmsProductAttributeValueModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductAttributeValueModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableAttributeValue, data.Id, ActionUpdate, m.actor, old, data)
+}
+
+func (m *attributeValueModel) Delete(id int64) error {
+	return m.recorder.transact(nil, func(session sqlx.Session) error {
+		return m.TxDelete(session, id)
+	})
+}
+
+func (m *attributeValueModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsProductAttributeValueModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductAttributeValueModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	return m.recorder.Record(session, TableAttributeValue, id, ActionDelete, m.actor, old, nil)
+}
+
+func (m *attributeValueModel) TxDeleteByProductId(session sqlx.Session, productId int64) error {
+	old, err := m.PmsProductAttributeValueModel.TxFindByProductId(session, productId)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductAttributeValueModel.TxDeleteByProductId(session, productId); err != nil {
+		return err
+	}
+
+	for _, row := range old {
+		if err := m.recorder.Record(session, TableAttributeValue, row.Id, ActionDelete, m.actor, row, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
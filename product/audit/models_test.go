@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"database/sql"
+	"testing"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+type (
+	// fakeConn runs each transaction in a new fakeSession.
+	fakeConn struct {
+		sqlx.SqlConn
+		transactions int
+	}
+
+	fakeSession struct {
+		sqlx.Session
+		id int
+	}
+
+	fakeLogModel struct {
+		model.PmsAuditLogModel
+		logs     []model.PmsAuditLog
+		sessions []sqlx.Session
+	}
+
+	fakeProductModel struct {
+		model.PmsProductModel
+		rows     map[int64]model.PmsProduct
+		sessions []sqlx.Session
+	}
+
+	fakeSkuStockModel struct {
+		model.PmsSkuStockModel
+		rows map[int64]model.PmsSkuStock
+	}
+)
+
+func (c *fakeConn) Transact(fn func(session sqlx.Session) error) error {
+	c.transactions++
+	return fn(&fakeSession{id: c.transactions})
+}
+
+func (m *fakeLogModel) TxInsert(session sqlx.Session, data model.PmsAuditLog) (sql.Result, error) {
+	m.logs = append(m.logs, data)
+	m.sessions = append(m.sessions, session)
+	return nil, nil
+}
+
+func (m *fakeProductModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*model.PmsProduct, error) {
+	m.sessions = append(m.sessions, session)
+	row, ok := m.rows[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &row, nil
+}
+
+func (m *fakeProductModel) TxUpdate(session sqlx.Session, data model.PmsProduct) error {
+	m.sessions = append(m.sessions, session)
+	// sort is NOT NULL DEFAULT 0, a NULL is written as 0
+	data.Sort.Valid = true
+	m.rows[data.Id] = data
+	return nil
+}
+
+func (m *fakeProductModel) StartPromotionWindow(session sqlx.Session, id int64) error {
+	m.sessions = append(m.sessions, session)
+	row := m.rows[id]
+	row.PreviousPromotionType, row.PromotionType = row.PromotionType, row.WindowPromotionType
+	m.rows[id] = row
+	return nil
+}
+
+func (m *fakeSkuStockModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*model.PmsSkuStock, error) {
+	row, ok := m.rows[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &row, nil
+}
+
+func (m *fakeSkuStockModel) LockStock(session sqlx.Session, id, quantity int64) error {
+	row, ok := m.rows[id]
+	if !ok || row.Stock-row.LockStock < quantity {
+		return model.ErrInsufficientStock
+	}
+	row.LockStock += quantity
+	m.rows[id] = row
+	return nil
+}
+
+func newFakeRecorder() (*Recorder, *fakeConn, *fakeLogModel) {
+	conn := &fakeConn{}
+	logModel := &fakeLogModel{}
+	return NewRecorder(conn, logModel), conn, logModel
+}
+
+func TestProductUpdateRecordedInSession(t *testing.T) {
+	recorder, conn, logModel := newFakeRecorder()
+	products := &fakeProductModel{rows: map[int64]model.PmsProduct{
+		1: {Id: 1, Name: "old", Sort: sql.NullInt64{Valid: true}},
+	}}
+	m := NewProductModel(products, recorder, "alice")
+
+	if err := m.Update(model.PmsProduct{Id: 1, Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if conn.transactions != 1 || len(logModel.logs) != 1 {
+		t.Fatalf("%d transactions, %d logs, want 1 and 1", conn.transactions, len(logModel.logs))
+	}
+	// the before image is read, the row written and read back and the log
+	// inserted in one session
+	for i, session := range append(products.sessions, logModel.sessions...) {
+		if session != products.sessions[0] {
+			t.Errorf("call %d ran in another session", i)
+		}
+	}
+
+	// the NULL sort written as its default isn't a change
+	log := logModel.logs[0]
+	if log.Action != ActionUpdate || log.Actor != "alice" || log.RowId != 1 ||
+		log.Changes != `[{"field":"name","before":"old","after":"new"}]` {
+		t.Errorf("log = %+v", log)
+	}
+}
+
+func TestProductWindowRecordedInCallerSession(t *testing.T) {
+	recorder, conn, logModel := newFakeRecorder()
+	products := &fakeProductModel{rows: map[int64]model.PmsProduct{
+		1: {
+			Id:                  1,
+			PromotionType:       sql.NullInt64{Int64: model.PromotionTypeNone, Valid: true},
+			WindowPromotionType: sql.NullInt64{Int64: model.PromotionTypeFlashSale, Valid: true},
+		},
+	}}
+	m := NewProductModel(products, recorder, ActorSystem)
+
+	session := &fakeSession{id: 100}
+	if err := m.StartPromotionWindow(session, 1); err != nil {
+		t.Fatal(err)
+	}
+	if conn.transactions != 0 {
+		t.Errorf("%d transactions started in the caller's session", conn.transactions)
+	}
+	if len(logModel.logs) != 1 || logModel.sessions[0] != session {
+		t.Fatalf("logs %+v not in the caller's session", logModel.logs)
+	}
+	changes, err := ParseChanges(&logModel.logs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Field != "promotion_type" || *changes[0].After != "5" {
+		t.Errorf("changes = %+v", changes)
+	}
+}
+
+func TestStockMoveRecorded(t *testing.T) {
+	recorder, _, logModel := newFakeRecorder()
+	skus := &fakeSkuStockModel{rows: map[int64]model.PmsSkuStock{1: {Id: 1, Stock: 5}}}
+	m := NewSkuStockModel(skus, recorder, ActorSystem)
+
+	if err := m.LockStock(nil, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(logModel.logs) != 1 || logModel.logs[0].Changes != `[{"field":"lock_stock","before":"0","after":"2"}]` {
+		t.Errorf("logs = %+v", logModel.logs)
+	}
+
+	// failing moves, and moves of missing SKUs, fail as they do unaudited
+	if err := m.LockStock(nil, 1, 4); err != model.ErrInsufficientStock {
+		t.Errorf("error = %v, want ErrInsufficientStock", err)
+	}
+	if err := m.LockStock(nil, 2, 1); err != model.ErrInsufficientStock {
+		t.Errorf("missing sku: error = %v, want ErrInsufficientStock", err)
+	}
+	if len(logModel.logs) != 1 {
+		t.Errorf("%d logs, failed moves recorded", len(logModel.logs))
+	}
+}
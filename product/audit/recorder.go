@@ -0,0 +1,228 @@
+package audit
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+// actions of pms_audit_log
+const (
+	ActionInsert = "insert"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// tables audited
+const (
+	TableProduct        = "pms_product"
+	TableSkuStock       = "pms_sku_stock"
+	TableBrand          = "pms_brand"
+	TableAttribute      = "pms_product_attribute"
+	TableLadder         = "pms_product_ladder"
+	TableFullReduction  = "pms_product_full_reduction"
+	TableAttributeValue = "pms_product_attribute_value"
+
+	// ActorSystem is recorded for writes made without a known actor.
+	ActorSystem = "system"
+
+	timeFormat = "2006-01-02 15:04:05"
+)
+
+// columns set by the database, never diffed
+var ignoredColumns = map[string]struct{}{
+	"id":          {},
+	"create_time": {},
+	"update_time": {},
+}
+
+type (
+	// FieldChange is the value of a column before and after a write,
+	// nil for NULL, or for the side of an insert or delete without a row.
+	FieldChange struct {
+		Field  string  `json:"field"`
+		Before *string `json:"before"`
+		After  *string `json:"after"`
+	}
+
+	// Recorder records writes to the audited tables in pms_audit_log, in the
+	// transactions of the writes.
+	Recorder struct {
+		conn     sqlx.SqlConn
+		logModel model.PmsAuditLogModel
+	}
+)
+
+func NewRecorder(conn sqlx.SqlConn, logModel model.PmsAuditLogModel) *Recorder {
+	return &Recorder{
+		conn:     conn,
+		logModel: logModel,
+	}
+}
+
+// Record logs the write of row rowId of table by actor in session, or
+// outside of a transaction if session is nil. before is nil for inserts and
+// after is nil for deletes, otherwise both are model structs of the same
+// type. Updates that change nothing are not logged.
+func (r *Recorder) Record(session sqlx.Session, table string, rowId int64, action, actor string,
+	before, after interface{}) error {
+	changes := Diff(before, after)
+	if action == ActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	if len(actor) == 0 {
+		actor = ActorSystem
+	}
+
+	log := model.PmsAuditLog{
+		TableName: table,
+		RowId:     rowId,
+		Action:    action,
+		Actor:     actor,
+		Changes:   string(data),
+	}
+	if session == nil {
+		_, err = r.logModel.Insert(log)
+	} else {
+		_, err = r.logModel.TxInsert(session, log)
+	}
+	return err
+}
+
+// inserted records an insert in session, the row ID taken from its result.
+func (r *Recorder) inserted(session sqlx.Session, table, actor string, ret sql.Result, data interface{}) error {
+	id, err := ret.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return r.Record(session, table, id, ActionInsert, actor, nil, data)
+}
+
+// transact runs fn in session, or in a new transaction if session is nil.
+func (r *Recorder) transact(session sqlx.Session, fn func(session sqlx.Session) error) error {
+	if session != nil {
+		return fn(session)
+	}
+
+	return r.conn.Transact(fn)
+}
+
+// History pages through the logs of a row, oldest first.
+func (r *Recorder) History(table string, rowId, lastId int64, limit int) ([]*model.PmsAuditLog, error) {
+	return r.logModel.FindByRow(table, rowId, lastId, limit)
+}
+
+// ParseChanges decodes the changes of a log.
+func ParseChanges(log *model.PmsAuditLog) ([]FieldChange, error) {
+	var changes []FieldChange
+	if err := json.Unmarshal([]byte(log.Changes), &changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// Diff compares the db columns of two model structs, or pointers to them,
+// either of which may be nil, and returns the columns that differ.
+func Diff(before, after interface{}) []FieldChange {
+	beforeValues, beforeFields := columnValues(before)
+	afterValues, afterFields := columnValues(after)
+	fields := afterFields
+	if len(fields) == 0 {
+		fields = beforeFields
+	}
+
+	var changes []FieldChange
+	for _, field := range fields {
+		b, a := beforeValues[field], afterValues[field]
+		if equal(b, a) {
+			continue
+		}
+
+		changes = append(changes, FieldChange{
+			Field:  field,
+			Before: b,
+			After:  a,
+		})
+	}
+
+	return changes
+}
+
+// columnValues formats the db columns of v, also returning them in declaration order.
+func columnValues(v interface{}) (map[string]*string, []string) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	rt := rv.Type()
+	values := make(map[string]*string, rt.NumField())
+	var fields []string
+	for i := 0; i < rt.NumField(); i++ {
+		column := rt.Field(i).Tag.Get("db")
+		if len(column) == 0 || column == "-" {
+			continue
+		}
+		if _, ok := ignoredColumns[column]; ok {
+			continue
+		}
+
+		values[column] = formatValue(rv.Field(i).Interface())
+		fields = append(fields, column)
+	}
+
+	return values, fields
+}
+
+func formatValue(v interface{}) *string {
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil {
+			s := err.Error()
+			return &s
+		}
+		v = dv
+	}
+
+	var s string
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		s = val.Format(timeFormat)
+	case []byte:
+		s = string(val)
+	default:
+		s = fmt.Sprint(val)
+	}
+
+	return &s
+}
+
+func equal(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
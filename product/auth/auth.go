@@ -0,0 +1,115 @@
+// Package auth issues the tokens of the back office routes, naming the
+// operator and their role, and tells what each role is allowed to do.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"malltmp/product/audit"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// claims of the tokens, put in the request context by the jwt handler of go-zero
+const (
+	ClaimOperator = "operator"
+	ClaimRole     = "role"
+)
+
+const (
+	// RoleViewer reads the catalog.
+	RoleViewer Role = "viewer"
+	// RoleEditor reads and edits the catalog and takes products through publishing,
+	// but approval.
+	RoleEditor Role = "editor"
+	// RoleAuditor reads the catalog, approves or rejects products and reads the audit log.
+	RoleAuditor Role = "auditor"
+	// RoleAdmin does everything, deletes and restores included.
+	RoleAdmin Role = "admin"
+	// RoleOrder is the order service, reserving stock for orders.
+	RoleOrder Role = "order"
+)
+
+const (
+	// PermRead reads products, SKUs, stock ledgers, revisions and schedules.
+	PermRead Permission = iota
+	// PermWrite creates and updates the catalog, imports, and submits,
+	// publishes and schedules products.
+	PermWrite
+	// PermReview approves and rejects products and reads the audit log.
+	PermReview
+	// PermManage deletes catalog rows and restores revisions.
+	PermManage
+	// PermReserve reserves stock for orders, and confirms and cancels the reservations.
+	PermReserve
+)
+
+var grants = map[Role][]Permission{
+	RoleViewer:  {PermRead},
+	RoleEditor:  {PermRead, PermWrite},
+	RoleAuditor: {PermRead, PermReview},
+	RoleAdmin:   {PermRead, PermWrite, PermReview, PermManage, PermReserve},
+	RoleOrder:   {PermReserve},
+}
+
+type (
+	Role       string
+	Permission int
+)
+
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if _, ok := grants[role]; !ok {
+		return "", fmt.Errorf("unknown role %q, one of viewer, editor, auditor, admin or order", s)
+	}
+
+	return role, nil
+}
+
+// Can tells whether r is granted p. Unknown roles are granted nothing.
+func (r Role) Can(p Permission) bool {
+	for _, granted := range grants[r] {
+		if granted == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoleFrom returns the role of the token of the request of ctx, empty without one.
+func RoleFrom(ctx context.Context) Role {
+	role, _ := ctx.Value(ClaimRole).(string)
+	return Role(role)
+}
+
+// OperatorFrom returns the operator of the token of the request of ctx,
+// audit.ActorSystem without one.
+func OperatorFrom(ctx context.Context) string {
+	if operator, ok := ctx.Value(ClaimOperator).(string); ok && len(operator) > 0 {
+		return operator
+	}
+
+	return audit.ActorSystem
+}
+
+// NewToken signs a token for operator acting as role, expiring after expire.
+func NewToken(secret, operator string, role Role, expire time.Duration) (string, error) {
+	if len(operator) == 0 {
+		return "", fmt.Errorf("operator must not be empty")
+	}
+	if _, ok := grants[role]; !ok {
+		return "", fmt.Errorf("unknown role %q", role)
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iat":         now.Unix(),
+		"exp":         now.Add(expire).Unix(),
+		ClaimOperator: operator,
+		ClaimRole:     string(role),
+	})
+	return token.SignedString([]byte(secret))
+}
@@ -0,0 +1,41 @@
+package auth
+
+import "testing"
+
+func TestRoleCan(t *testing.T) {
+	all := []Permission{PermRead, PermWrite, PermReview, PermManage, PermReserve}
+	tests := []struct {
+		role Role
+		want []Permission
+	}{
+		{RoleViewer, []Permission{PermRead}},
+		{RoleEditor, []Permission{PermRead, PermWrite}},
+		{RoleAuditor, []Permission{PermRead, PermReview}},
+		{RoleAdmin, all},
+		{RoleOrder, []Permission{PermReserve}},
+		{"", nil},
+		{"root", nil},
+	}
+	for _, tt := range tests {
+		granted := make(map[Permission]bool, len(tt.want))
+		for _, p := range tt.want {
+			granted[p] = true
+		}
+		for _, p := range all {
+			if got := tt.role.Can(p); got != granted[p] {
+				t.Errorf("role %q can %d = %t, want %t", tt.role, p, got, granted[p])
+			}
+		}
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	for _, role := range []Role{RoleViewer, RoleEditor, RoleAuditor, RoleAdmin, RoleOrder} {
+		if got, err := ParseRole(string(role)); got != role || err != nil {
+			t.Errorf("ParseRole(%q) = %q, %v", role, got, err)
+		}
+	}
+	if _, err := ParseRole("root"); err == nil {
+		t.Error("ParseRole(root) took an unknown role")
+	}
+}
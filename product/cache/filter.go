@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/hash"
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/syncx"
+)
+
+const filterBatch = 5000
+
+// ProductFilter is a bloom filter of the IDs of the existing products, which
+// tells for sure that a product doesn't exist, so it isn't looked up. It is
+// rebuilt from all products every interval, which catches the products
+// inserted by other processes, whatever the order their IDs commit in. Until
+// built, every product may exist.
+type ProductFilter struct {
+	productModel model.PmsProductModel
+	interval     time.Duration
+	done         *syncx.DoneChan
+	built        uint32
+
+	lock sync.RWMutex
+	bits []uint64
+	// the filter being rebuilt, nil between rebuilds
+	next   []uint64
+	size   uint64
+	hashes uint64
+}
+
+// NewProductFilter returns a filter sized for capacity products to be told
+// existing wrongly at a rate of falsePositive, which grows past capacity,
+// rebuilt every interval.
+func NewProductFilter(productModel model.PmsProductModel, capacity int, falsePositive float64,
+	interval time.Duration) *ProductFilter {
+	if capacity < 1 {
+		capacity = 1
+	}
+	n := float64(capacity)
+	size := uint64(math.Ceil(-n * math.Log(falsePositive) / (math.Ln2 * math.Ln2)))
+	if size < 64 {
+		size = 64
+	}
+	hashes := uint64(math.Round(float64(size) / n * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+
+	return &ProductFilter{
+		productModel: productModel,
+		interval:     interval,
+		done:         syncx.NewDoneChan(),
+		bits:         make([]uint64, (size+63)/64),
+		size:         size,
+		hashes:       hashes,
+	}
+}
+
+// Start builds the filter from all products right away, then rebuilds it
+// every interval until Stop is called.
+func (f *ProductFilter) Start() {
+	f.refresh()
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.refresh()
+		case <-f.done.Done():
+			return
+		}
+	}
+}
+
+func (f *ProductFilter) Stop() {
+	f.done.Close()
+}
+
+// refresh builds a new filter from all products and swaps it in, keeping the
+// current one if the products can't be read. The products added meanwhile are
+// added to both.
+func (f *ProductFilter) refresh() {
+	start := time.Now()
+	f.lock.Lock()
+	f.next = make([]uint64, len(f.bits))
+	f.lock.Unlock()
+
+	var (
+		lastId int64
+		count  int
+	)
+	for {
+		ids, err := f.productModel.FindIds(lastId, filterBatch)
+		if err != nil {
+			logx.Errorf("product filter failed to find the products after %d: %v", lastId, err)
+			f.lock.Lock()
+			f.next = nil
+			f.lock.Unlock()
+			return
+		}
+
+		f.lock.Lock()
+		for _, id := range ids {
+			f.set(f.next, id)
+		}
+		f.lock.Unlock()
+		count += len(ids)
+		if len(ids) > 0 {
+			lastId = ids[len(ids)-1]
+		}
+		if len(ids) < filterBatch {
+			break
+		}
+	}
+
+	f.lock.Lock()
+	f.bits, f.next = f.next, nil
+	f.lock.Unlock()
+
+	if atomic.CompareAndSwapUint32(&f.built, 0, 1) {
+		logx.Infof("product filter built with %d products in %s", count, time.Since(start))
+	}
+}
+
+// Add tells the filter that the product exists.
+func (f *ProductFilter) Add(productId int64) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.set(f.bits, productId)
+	if f.next != nil {
+		f.set(f.next, productId)
+	}
+}
+
+// MayExist returns false if the product surely doesn't exist.
+func (f *ProductFilter) MayExist(productId int64) bool {
+	if atomic.LoadUint32(&f.built) == 0 {
+		return true
+	}
+
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	for _, bit := range f.locations(productId) {
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *ProductFilter) set(bits []uint64, productId int64) {
+	for _, bit := range f.locations(productId) {
+		bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// locations returns the bits of the product, by double hashing its ID.
+func (f *ProductFilter) locations(productId int64) []uint64 {
+	var data [8]byte
+	binary.LittleEndian.PutUint64(data[:], uint64(productId))
+	sum := hash.Hash(data[:])
+	h1, h2 := sum&math.MaxUint32, sum>>32|1
+
+	locations := make([]uint64, f.hashes)
+	for i := range locations {
+		locations[i] = (h1 + uint64(i)*h2) % f.size
+	}
+	return locations
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"malltmp/product/model"
+)
+
+type fakeProductModel struct {
+	model.PmsProductModel
+	ids []int64
+}
+
+func (m *fakeProductModel) FindIds(lastId int64, limit int) ([]int64, error) {
+	var resp []int64
+	for _, id := range m.ids {
+		if id > lastId && len(resp) < limit {
+			resp = append(resp, id)
+		}
+	}
+	return resp, nil
+}
+
+func TestProductFilterRefresh(t *testing.T) {
+	products := &fakeProductModel{ids: []int64{1, 2, 5}}
+	f := NewProductFilter(products, 100, 0.0001, time.Minute)
+	if !f.MayExist(3) {
+		t.Error("product 3 filtered before the filter is built")
+	}
+
+	f.refresh()
+	for _, id := range products.ids {
+		if !f.MayExist(id) {
+			t.Errorf("product %d filtered", id)
+		}
+	}
+	if f.MayExist(3) {
+		t.Error("product 3 not filtered")
+	}
+
+	// 3 commits after 5, and 6 is added by this process
+	products.ids = []int64{1, 2, 3, 5}
+	f.Add(6)
+	if !f.MayExist(6) {
+		t.Error("product 6 filtered once added")
+	}
+	f.refresh()
+	if !f.MayExist(3) {
+		t.Error("product 3 filtered after a refresh")
+	}
+}
@@ -0,0 +1,274 @@
+// Package cache keeps what is built from a product in process, until the
+// product changes or the entry expires.
+package cache
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/mathx"
+	"github.com/tal-tech/go-zero/core/metric"
+	"github.com/tal-tech/go-zero/core/syncx"
+	"github.com/tal-tech/go-zero/core/timex"
+)
+
+const (
+	statInterval = time.Minute
+	// entries expire within [0.9, 1.1] of their expiry, not all at once
+	expiryDeviation = 0.1
+	resultHit       = "hit"
+	resultMiss      = "miss"
+	resultFiltered  = "filtered"
+)
+
+var metricCacheTotal = metric.NewCounterVec(&metric.CounterVecOpts{
+	Namespace: "product",
+	Subsystem: "cache",
+	Name:      "lookups_total",
+	Help:      "product cache lookups by cache name and result, hit, miss or filtered.",
+	Labels:    []string{"name", "result"},
+})
+
+type (
+	// ProductCacheOption customizes a ProductCache.
+	ProductCacheOption func(c *ProductCache)
+
+	// ProductCache is an LRU cache of values built per product, each kept
+	// until it expires or Del is called with its product, which a change of
+	// the product's rows must do.
+	ProductCache struct {
+		name           string
+		expire         time.Duration
+		notFoundExpire time.Duration
+		limit          int
+		filter         *ProductFilter
+		flight         syncx.SharedCalls
+		unstable       mathx.Unstable
+		done           *syncx.DoneChan
+
+		lock    sync.Mutex
+		entries map[int64]*list.Element
+		lru     *list.List
+		// products being fetched, true once deleted meanwhile
+		fetching map[int64]bool
+
+		hit      uint64
+		miss     uint64
+		filtered uint64
+	}
+
+	productEntry struct {
+		productId int64
+		value     interface{}
+		err       error
+		expireAt  time.Duration
+	}
+)
+
+// NewProductCache returns a cache keeping values for about expire.
+func NewProductCache(name string, expire time.Duration, opts ...ProductCacheOption) *ProductCache {
+	c := &ProductCache{
+		name:     name,
+		expire:   expire,
+		flight:   syncx.NewSharedCalls(),
+		unstable: mathx.NewUnstable(expiryDeviation),
+		entries:  make(map[int64]*list.Element),
+		lru:      list.New(),
+		fetching: make(map[int64]bool),
+		done:     syncx.NewDoneChan(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithLimit keeps at most limit products, dropping the least recently used.
+func WithLimit(limit int) ProductCacheOption {
+	return func(c *ProductCache) {
+		c.limit = limit
+	}
+}
+
+// WithNotFoundExpire keeps model.ErrNotFound for about expire, so missing
+// products aren't fetched on every lookup. Inserting the product drops it.
+func WithNotFoundExpire(expire time.Duration) ProductCacheOption {
+	return func(c *ProductCache) {
+		c.notFoundExpire = expire
+	}
+}
+
+// WithFilter answers model.ErrNotFound right away for the products filter
+// tells not existing.
+func WithFilter(filter *ProductFilter) ProductCacheOption {
+	return func(c *ProductCache) {
+		c.filter = filter
+	}
+}
+
+// Take returns the cached value of the product, or fetches and caches it,
+// once for all its concurrent callers. Errors other than model.ErrNotFound
+// aren't cached, nor is what was fetched while the product changed, being
+// possibly built from the rows before the change.
+func (c *ProductCache) Take(productId int64, fetch func() (interface{}, error)) (interface{}, error) {
+	if c.filter != nil && !c.filter.MayExist(productId) {
+		c.record(resultFiltered)
+		return nil, model.ErrNotFound
+	}
+	if entry, ok := c.get(productId); ok {
+		c.record(resultHit)
+		return entry.value, entry.err
+	}
+
+	var fresh bool
+	val, err := c.flight.Do(strconv.FormatInt(productId, 10), func() (interface{}, error) {
+		if entry, ok := c.get(productId); ok {
+			return entry.value, entry.err
+		}
+
+		c.lock.Lock()
+		c.fetching[productId] = false
+		c.lock.Unlock()
+
+		val, err := fetch()
+		fresh = true
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		deleted := c.fetching[productId]
+		delete(c.fetching, productId)
+		switch {
+		case err == model.ErrNotFound && c.notFoundExpire > 0:
+			if !deleted {
+				c.add(&productEntry{productId: productId, err: err}, c.notFoundExpire)
+			}
+		case err != nil:
+			return nil, err
+		case !deleted:
+			c.add(&productEntry{productId: productId, value: val}, c.expire)
+		}
+
+		return val, err
+	})
+	if err != nil && err != model.ErrNotFound {
+		return nil, err
+	}
+
+	// what is shared by an ongoing fetch counts as hits, like what is found
+	if fresh {
+		c.record(resultMiss)
+	} else {
+		c.record(resultHit)
+	}
+	return val, err
+}
+
+// Del drops the value of the product, and the value being fetched if any.
+func (c *ProductCache) Del(productId int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[productId]; ok {
+		c.remove(elem)
+	}
+	if _, ok := c.fetching[productId]; ok {
+		c.fetching[productId] = true
+	}
+}
+
+func (c *ProductCache) get(productId int64) (*productEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.entries[productId]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*productEntry)
+	if timex.Since(entry.expireAt) >= 0 {
+		c.remove(elem)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+	return entry, true
+}
+
+// add caches entry for about expire, c.lock held.
+func (c *ProductCache) add(entry *productEntry, expire time.Duration) {
+	productId := entry.productId
+	entry.expireAt = timex.Now() + c.unstable.AroundDuration(expire)
+	if elem, ok := c.entries[productId]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[productId] = c.lru.PushFront(entry)
+	if c.limit > 0 && c.lru.Len() > c.limit {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove drops the entry of elem, c.lock held.
+func (c *ProductCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*productEntry).productId)
+}
+
+func (c *ProductCache) size() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *ProductCache) record(result string) {
+	switch result {
+	case resultHit:
+		atomic.AddUint64(&c.hit, 1)
+	case resultMiss:
+		atomic.AddUint64(&c.miss, 1)
+	case resultFiltered:
+		atomic.AddUint64(&c.filtered, 1)
+	}
+	metricCacheTotal.Inc(c.name, result)
+}
+
+// Start logs the stats of the cache every minute until Stop is called.
+func (c *ProductCache) Start() {
+	ticker := time.NewTicker(statInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.logStats()
+		case <-c.done.Done():
+			return
+		}
+	}
+}
+
+func (c *ProductCache) Stop() {
+	c.done.Close()
+}
+
+func (c *ProductCache) logStats() {
+	hit := atomic.SwapUint64(&c.hit, 0)
+	miss := atomic.SwapUint64(&c.miss, 0)
+	filtered := atomic.SwapUint64(&c.filtered, 0)
+	total := hit + miss + filtered
+	if total == 0 {
+		return
+	}
+	logx.Statf("cache(%s) - qpm: %d, hit_ratio: %.1f%%, elements: %d, hit: %d, miss: %d, filtered: %d",
+		c.name, total, 100*float32(hit)/float32(total), c.size(), hit, miss, filtered)
+}
@@ -0,0 +1,335 @@
+package catalog
+
+import (
+	"database/sql"
+	"errors"
+
+	"malltmp/product/model"
+	"malltmp/product/pricing"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+var (
+	ErrProductDeleted = errors.New("product is deleted")
+	ErrBrandNotFound  = errors.New("brand not found")
+)
+
+// Editor makes the changes merchants make to products on behalf of actor,
+// validated and saved as a revision of the product. Its models audit the
+// writes.
+type Editor struct {
+	conn                sqlx.SqlConn
+	productModel        model.PmsProductModel
+	skuModel            model.PmsSkuStockModel
+	brandModel          model.PmsBrandModel
+	attributeValueModel model.PmsProductAttributeValueModel
+	ladderModel         model.PmsProductLadderModel
+	fullReductionModel  model.PmsProductFullReductionModel
+	revisions           *Revisions
+	actor               string
+}
+
+func NewEditor(conn sqlx.SqlConn, productModel model.PmsProductModel, skuModel model.PmsSkuStockModel,
+	brandModel model.PmsBrandModel, attributeValueModel model.PmsProductAttributeValueModel,
+	ladderModel model.PmsProductLadderModel, fullReductionModel model.PmsProductFullReductionModel,
+	revisions *Revisions, actor string) *Editor {
+	return &Editor{
+		conn:                conn,
+		productModel:        productModel,
+		skuModel:            skuModel,
+		brandModel:          brandModel,
+		attributeValueModel: attributeValueModel,
+		ladderModel:         ladderModel,
+		fullReductionModel:  fullReductionModel,
+		revisions:           revisions,
+		actor:               actor,
+	}
+}
+
+// CreateProduct adds a product without SKUs, as a draft, returning its ID.
+func (e *Editor) CreateProduct(data model.PmsProduct) (int64, error) {
+	if err := ValidateProduct(&data); err != nil {
+		return 0, err
+	}
+	if err := e.setBrandName(&data); err != nil {
+		return 0, err
+	}
+
+	data.Stock = sql.NullInt64{Valid: true}
+	data.Sale = sql.NullInt64{Valid: true}
+	data.DeleteStatus = sql.NullInt64{Valid: true}
+	ret, err := e.productModel.Insert(data)
+	if err != nil {
+		return 0, duplicateField(err)
+	}
+	id, err := ret.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, e.saveRevision(id)
+}
+
+// UpdateProduct changes what merchants edit of a product. Stock, sale, the
+// review, publish and delete status, and the promotion window are kept; they
+// have their own ways to change.
+func (e *Editor) UpdateProduct(data model.PmsProduct) error {
+	old, err := e.productModel.FindOne(data.Id)
+	if err != nil {
+		return err
+	}
+	if old.DeleteStatus.Int64 == 1 {
+		return ErrProductDeleted
+	}
+	if err := ValidateProduct(&data); err != nil {
+		return err
+	}
+	if err := e.setBrandName(&data); err != nil {
+		return err
+	}
+
+	data.Stock = old.Stock
+	data.Sale = old.Sale
+	data.DeleteStatus = old.DeleteStatus
+	data.PromotionStartTime = old.PromotionStartTime
+	data.PromotionEndTime = old.PromotionEndTime
+	data.WindowPromotionType = old.WindowPromotionType
+	data.PreviousPromotionType = old.PreviousPromotionType
+	if old.PreviousPromotionType.Valid {
+		// the promotion window is on, its type stays until it ends
+		data.PreviousPromotionType = data.PromotionType
+		data.PromotionType = old.PromotionType
+	}
+	if err := e.productModel.Update(data); err != nil {
+		return duplicateField(err)
+	}
+
+	return e.saveRevision(data.Id)
+}
+
+// DeleteProduct marks the product deleted, keeping its rows for orders
+// referring to it.
+func (e *Editor) DeleteProduct(id int64) error {
+	product, err := e.productModel.FindOne(id)
+	if err != nil {
+		return err
+	}
+	if product.DeleteStatus.Int64 == 1 {
+		return nil
+	}
+
+	product.DeleteStatus = sql.NullInt64{Int64: 1, Valid: true}
+	return e.productModel.Update(*product)
+}
+
+// CreateSku adds a SKU to a product, with its stock, returning its ID.
+func (e *Editor) CreateSku(productId int64, data model.PmsSkuStock) (int64, error) {
+	if err := e.checkProduct(productId); err != nil {
+		return 0, err
+	}
+	if err := ValidateSku(&data); err != nil {
+		return 0, err
+	}
+
+	data.ProductId = sql.NullInt64{Int64: productId, Valid: true}
+	data.LockStock = 0
+	data.Sale = sql.NullInt64{Valid: true}
+	ret, err := e.skuModel.Insert(data)
+	if err != nil {
+		return 0, duplicateField(err)
+	}
+	id, err := ret.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, e.saveRevision(productId)
+}
+
+// UpdateSku changes all of a SKU but its product and stock, which changes
+// with the inventory.
+func (e *Editor) UpdateSku(data model.PmsSkuStock) error {
+	old, err := e.skuModel.FindOne(data.Id)
+	if err != nil {
+		return err
+	}
+	if err := e.checkProduct(old.ProductId.Int64); err != nil {
+		return err
+	}
+
+	data.ProductId = old.ProductId
+	data.Stock, data.LockStock, data.Sale = old.Stock, old.LockStock, old.Sale
+	if err := ValidateSku(&data); err != nil {
+		return err
+	}
+	if err := e.skuModel.UpdateInfo(data); err != nil {
+		return duplicateField(err)
+	}
+
+	return e.saveRevision(old.ProductId.Int64)
+}
+
+// DeleteSku removes a SKU, unless it has stock locked for orders.
+func (e *Editor) DeleteSku(id int64) error {
+	sku, err := e.skuModel.FindOne(id)
+	if err != nil {
+		return err
+	}
+	if sku.LockStock > 0 {
+		return &SkuLockedError{SkuId: sku.Id, LockStock: sku.LockStock}
+	}
+	if err := e.skuModel.Delete(id); err != nil {
+		return err
+	}
+
+	return e.saveRevision(sku.ProductId.Int64)
+}
+
+// ReplaceAttributeValues sets the attribute values of a product to values.
+func (e *Editor) ReplaceAttributeValues(productId int64, values []*model.PmsProductAttributeValue) error {
+	if err := ValidateAttributeValues(values); err != nil {
+		return err
+	}
+
+	err := e.replace(productId, e.attributeValueModel.TxDeleteByProductId, func(session sqlx.Session) error {
+		return insertAll(len(values), func(i int) (sql.Result, error) {
+			values[i].ProductId = sql.NullInt64{Int64: productId, Valid: true}
+			return e.attributeValueModel.TxInsert(session, *values[i])
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.saveRevision(productId)
+}
+
+// ReplaceLadders sets the ladder tiers of a product to ladders, checked
+// against the price of the product.
+func (e *Editor) ReplaceLadders(productId int64, ladders []*model.PmsProductLadder) error {
+	product, err := e.productModel.FindOne(productId)
+	if err != nil {
+		return err
+	}
+	if err := pricing.ValidateLadders(product.Price.Money, ladders); err != nil {
+		return err
+	}
+
+	err = e.replace(productId, e.ladderModel.TxDeleteByProductId, func(session sqlx.Session) error {
+		return insertAll(len(ladders), func(i int) (sql.Result, error) {
+			ladders[i].ProductId = sql.NullInt64{Int64: productId, Valid: true}
+			return e.ladderModel.TxInsert(session, *ladders[i])
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.saveRevision(productId)
+}
+
+// ReplaceFullReductions sets the full reduction tiers of a product to reductions.
+func (e *Editor) ReplaceFullReductions(productId int64, reductions []*model.PmsProductFullReduction) error {
+	if err := pricing.ValidateReductions(reductions); err != nil {
+		return err
+	}
+
+	err := e.replace(productId, e.fullReductionModel.TxDeleteByProductId, func(session sqlx.Session) error {
+		return insertAll(len(reductions), func(i int) (sql.Result, error) {
+			reductions[i].ProductId = sql.NullInt64{Int64: productId, Valid: true}
+			return e.fullReductionModel.TxInsert(session, *reductions[i])
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.saveRevision(productId)
+}
+
+// replace deletes the rows of a product and inserts the new ones in one
+// transaction, with the product locked so replaces don't interleave.
+func (e *Editor) replace(productId int64, deleteAll func(session sqlx.Session, productId int64) error,
+	insert func(session sqlx.Session) error) error {
+	return e.conn.Transact(func(session sqlx.Session) error {
+		product, err := e.productModel.TxFindOneForUpdate(session, productId)
+		if err != nil {
+			return err
+		}
+		if product.DeleteStatus.Int64 == 1 {
+			return ErrProductDeleted
+		}
+
+		if err := deleteAll(session, productId); err != nil {
+			return err
+		}
+		return insert(session)
+	})
+}
+
+func (e *Editor) checkProduct(productId int64) error {
+	product, err := e.productModel.FindOne(productId)
+	if err != nil {
+		return err
+	}
+	if product.DeleteStatus.Int64 == 1 {
+		return ErrProductDeleted
+	}
+
+	return nil
+}
+
+// setBrandName copies the name of the product's brand, which it keeps for listing.
+func (e *Editor) setBrandName(data *model.PmsProduct) error {
+	if !data.BrandId.Valid || data.BrandId.Int64 == 0 {
+		data.BrandId = sql.NullInt64{}
+		data.BrandName = sql.NullString{}
+		return nil
+	}
+
+	brand, err := e.brandModel.FindOne(data.BrandId.Int64)
+	switch err {
+	case nil:
+		data.BrandName = brand.Name
+		return nil
+	case model.ErrNotFound:
+		return ErrBrandNotFound
+	default:
+		return err
+	}
+}
+
+func (e *Editor) saveRevision(productId int64) error {
+	_, err := e.revisions.Save(productId, e.actor)
+	return err
+}
+
+// duplicateField tells which field of a write took a unique key, passing
+// other errors on.
+func duplicateField(err error) error {
+	var dup *model.DuplicateError
+	if !errors.As(err, &dup) {
+		return err
+	}
+
+	switch dup.Key {
+	case model.KeyProductSn:
+		return &FieldError{Field: "product_sn", Message: "is taken by another product"}
+	case model.KeySkuCode:
+		return &FieldError{Field: "sku_code", Message: "is taken by another sku"}
+	default:
+		return err
+	}
+}
+
+// insertAll runs n inserts, stopping at the first failing.
+func insertAll(n int, insert func(i int) (sql.Result, error)) error {
+	for i := 0; i < n; i++ {
+		if _, err := insert(i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
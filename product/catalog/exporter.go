@@ -0,0 +1,391 @@
+package catalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"malltmp/product/model"
+)
+
+const (
+	FormatCsv   = "csv"
+	FormatJsonl = "jsonl"
+
+	exportBatch = 200
+)
+
+// columns the csv export adds to the import ones. The specs of a SKU stay in
+// sp_data, as stored, their keys differing from product to product, so an
+// export is a report and not a file to import.
+const (
+	colProductId  = "product_id"
+	colBrandId    = "brand_id"
+	colUpdateTime = "update_time"
+	colAttributes = "attributes"
+	colSkuId      = "sku_id"
+	colLockStock  = "lock_stock"
+	colSale       = "sale"
+	colSpData     = "sp_data"
+)
+
+var exportColumns = []string{
+	colProductId, colProductSn, colName, colBrandId, colBrand, colProductCategoryId, colProductCategoryName,
+	colSubTitle, colKeywords, colUnit, colWeight, colPrice, colOriginalPrice, colPromotionPrice,
+	colGiftPoint, colGiftGrowth, colLowStock, colUpdateTime, colAttributes,
+	colSkuId, colSkuCode, colSkuPrice, colSkuPromotionPrice, colStock, colLockStock, colSale, colSkuLowStock,
+	colSpData,
+}
+
+type (
+	// ExportProduct is a published product as exported, one per line in JSON Lines.
+	ExportProduct struct {
+		Id                  int64              `json:"id"`
+		ProductSn           string             `json:"product_sn"`
+		Name                string             `json:"name"`
+		Brand               *ExportBrand       `json:"brand,omitempty"`
+		ProductCategoryId   int64              `json:"product_category_id"`
+		ProductCategoryName string             `json:"product_category_name"`
+		SubTitle            string             `json:"sub_title"`
+		Keywords            string             `json:"keywords"`
+		Unit                string             `json:"unit"`
+		Weight              float64            `json:"weight"`
+		Price               model.NullMoney    `json:"price"`
+		OriginalPrice       model.NullMoney    `json:"original_price"`
+		PromotionPrice      model.NullMoney    `json:"promotion_price"`
+		GiftPoint           int64              `json:"gift_point"`
+		GiftGrowth          int64              `json:"gift_growth"`
+		LowStock            int64              `json:"low_stock"`
+		UpdateTime          time.Time          `json:"update_time"`
+		Attributes          []*ExportAttribute `json:"attributes"`
+		Skus                []*ExportSku       `json:"skus"`
+	}
+
+	ExportBrand struct {
+		Id          int64  `json:"id"`
+		Name        string `json:"name"`
+		FirstLetter string `json:"first_letter"`
+		Logo        string `json:"logo"`
+	}
+
+	ExportAttribute struct {
+		Id    int64  `json:"id"`
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	ExportSku struct {
+		Id             int64           `json:"id"`
+		SkuCode        string          `json:"sku_code"`
+		Price          model.NullMoney `json:"price"`
+		PromotionPrice model.NullMoney `json:"promotion_price"`
+		Stock          int64           `json:"stock"`
+		LockStock      int64           `json:"lock_stock"`
+		Sale           int64           `json:"sale"`
+		LowStock       int64           `json:"low_stock"`
+		Specs          []model.SkuSpec `json:"specs"`
+	}
+
+	// Exporter streams published products with their brand, attributes and
+	// SKUs, one batch of products in memory at a time.
+	Exporter struct {
+		productModel        model.PmsProductModel
+		skuModel            model.PmsSkuStockModel
+		brandModel          model.PmsBrandModel
+		attributeModel      model.PmsProductAttributeModel
+		attributeValueModel model.PmsProductAttributeValueModel
+	}
+
+	exportWriter interface {
+		write(product *ExportProduct) error
+		flush() error
+	}
+
+	csvExportWriter struct {
+		w *csv.Writer
+	}
+
+	jsonlExportWriter struct {
+		w   *bufio.Writer
+		enc *json.Encoder
+	}
+)
+
+func NewExporter(productModel model.PmsProductModel, skuModel model.PmsSkuStockModel, brandModel model.PmsBrandModel,
+	attributeModel model.PmsProductAttributeModel, attributeValueModel model.PmsProductAttributeValueModel) *Exporter {
+	return &Exporter{
+		productModel:        productModel,
+		skuModel:            skuModel,
+		brandModel:          brandModel,
+		attributeModel:      attributeModel,
+		attributeValueModel: attributeValueModel,
+	}
+}
+
+// Export writes the products matching filter to w in format, FormatCsv or
+// FormatJsonl, and returns how many products were written.
+func (ex *Exporter) Export(w io.Writer, format string, filter model.PmsProductFilter) (int, error) {
+	var writer exportWriter
+	switch format {
+	case FormatCsv:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportColumns); err != nil {
+			return 0, err
+		}
+		writer = &csvExportWriter{w: cw}
+	case FormatJsonl:
+		bw := bufio.NewWriter(w)
+		writer = &jsonlExportWriter{w: bw, enc: json.NewEncoder(bw)}
+	default:
+		return 0, fmt.Errorf("unknown export format %q", format)
+	}
+
+	var (
+		count  int
+		lastId int64
+		brands = make(map[int64]*ExportBrand)
+	)
+	for {
+		products, err := ex.productModel.FindPublished(filter, lastId, exportBatch)
+		if err != nil {
+			return count, err
+		}
+
+		batch, err := ex.assemble(products, brands)
+		if err != nil {
+			return count, err
+		}
+		for _, product := range batch {
+			if err := writer.write(product); err != nil {
+				return count, err
+			}
+		}
+		if err := writer.flush(); err != nil {
+			return count, err
+		}
+		count += len(batch)
+
+		if len(products) < exportBatch {
+			return count, nil
+		}
+		lastId = products[len(products)-1].Id
+	}
+}
+
+func (ex *Exporter) assemble(products []*model.PmsProduct, brands map[int64]*ExportBrand) ([]*ExportProduct, error) {
+	productIds := make([]int64, 0, len(products))
+	for _, product := range products {
+		productIds = append(productIds, product.Id)
+	}
+
+	skus, err := ex.skuModel.FindByProductIds(productIds)
+	if err != nil {
+		return nil, err
+	}
+	skusByProduct := make(map[int64][]*ExportSku)
+	for _, sku := range skus {
+		specs, err := model.ParseSpData(sku.SpData)
+		if err != nil {
+			return nil, fmt.Errorf("sku %d: invalid sp_data: %v", sku.Id, err)
+		}
+		skusByProduct[sku.ProductId.Int64] = append(skusByProduct[sku.ProductId.Int64], &ExportSku{
+			Id:             sku.Id,
+			SkuCode:        sku.SkuCode,
+			Price:          sku.Price,
+			PromotionPrice: sku.PromotionPrice,
+			Stock:          sku.Stock,
+			LockStock:      sku.LockStock,
+			Sale:           sku.Sale.Int64,
+			LowStock:       sku.LowStock.Int64,
+			Specs:          specs,
+		})
+	}
+
+	attributesByProduct, err := ex.findAttributes(productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*ExportProduct, 0, len(products))
+	for _, product := range products {
+		brand, err := ex.findBrand(brands, product)
+		if err != nil {
+			return nil, err
+		}
+
+		resp = append(resp, &ExportProduct{
+			Id:                  product.Id,
+			ProductSn:           product.ProductSn,
+			Name:                product.Name,
+			Brand:               brand,
+			ProductCategoryId:   product.ProductCategoryId.Int64,
+			ProductCategoryName: product.ProductCategoryName.String,
+			SubTitle:            product.SubTitle.String,
+			Keywords:            product.Keywords.String,
+			Unit:                product.Unit.String,
+			Weight:              product.Weight.Float64,
+			Price:               product.Price,
+			OriginalPrice:       product.OriginalPrice,
+			PromotionPrice:      product.PromotionPrice,
+			GiftPoint:           product.GiftPoint,
+			GiftGrowth:          product.GiftGrowth,
+			LowStock:            product.LowStock.Int64,
+			UpdateTime:          product.UpdateTime,
+			Attributes:          attributesByProduct[product.Id],
+			Skus:                skusByProduct[product.Id],
+		})
+	}
+
+	return resp, nil
+}
+
+func (ex *Exporter) findAttributes(productIds []int64) (map[int64][]*ExportAttribute, error) {
+	values, err := ex.attributeValueModel.FindByProductIds(productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	attributeIds := make([]int64, 0, len(values))
+	for _, value := range values {
+		if value.ProductAttributeId.Valid {
+			attributeIds = append(attributeIds, value.ProductAttributeId.Int64)
+		}
+	}
+	// values of attributes since deleted are exported without a name
+	attributes, _, err := ex.attributeModel.FindMany(attributeIds)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(attributes))
+	for _, attribute := range attributes {
+		names[attribute.Id] = attribute.Name.String
+	}
+
+	resp := make(map[int64][]*ExportAttribute)
+	for _, value := range values {
+		productId := value.ProductId.Int64
+		resp[productId] = append(resp[productId], &ExportAttribute{
+			Id:    value.ProductAttributeId.Int64,
+			Name:  names[value.ProductAttributeId.Int64],
+			Value: value.Value.String,
+		})
+	}
+
+	return resp, nil
+}
+
+func (ex *Exporter) findBrand(brands map[int64]*ExportBrand, product *model.PmsProduct) (*ExportBrand, error) {
+	if !product.BrandId.Valid {
+		return nil, nil
+	}
+	if brand, ok := brands[product.BrandId.Int64]; ok {
+		return brand, nil
+	}
+
+	brand, err := ex.brandModel.FindOne(product.BrandId.Int64)
+	switch err {
+	case nil:
+		brands[brand.Id] = &ExportBrand{
+			Id:          brand.Id,
+			Name:        brand.Name.String,
+			FirstLetter: brand.FirstLetter.String,
+			Logo:        brand.Logo.String,
+		}
+	case model.ErrNotFound:
+		// the brand is gone, keep the name denormalized on the product
+		brands[product.BrandId.Int64] = &ExportBrand{
+			Id:   product.BrandId.Int64,
+			Name: product.BrandName.String,
+		}
+	default:
+		return nil, err
+	}
+
+	return brands[product.BrandId.Int64], nil
+}
+
+func (w *csvExportWriter) write(product *ExportProduct) error {
+	attributes, err := json.Marshal(product.Attributes)
+	if err != nil {
+		return err
+	}
+
+	var brandId, brandName string
+	if product.Brand != nil {
+		brandId = strconv.FormatInt(product.Brand.Id, 10)
+		brandName = product.Brand.Name
+	}
+	head := []string{
+		strconv.FormatInt(product.Id, 10),
+		product.ProductSn,
+		product.Name,
+		brandId,
+		brandName,
+		strconv.FormatInt(product.ProductCategoryId, 10),
+		product.ProductCategoryName,
+		product.SubTitle,
+		product.Keywords,
+		product.Unit,
+		strconv.FormatFloat(product.Weight, 'f', -1, 64),
+		formatNullMoney(product.Price),
+		formatNullMoney(product.OriginalPrice),
+		formatNullMoney(product.PromotionPrice),
+		strconv.FormatInt(product.GiftPoint, 10),
+		strconv.FormatInt(product.GiftGrowth, 10),
+		strconv.FormatInt(product.LowStock, 10),
+		product.UpdateTime.Format(time.RFC3339),
+		string(attributes),
+	}
+
+	// a product without SKUs still makes a row, with the SKU columns empty
+	if len(product.Skus) == 0 {
+		return w.w.Write(append(head, make([]string, len(exportColumns)-len(head))...))
+	}
+	for _, sku := range product.Skus {
+		spData, err := model.FormatSpData(sku.Specs)
+		if err != nil {
+			return err
+		}
+
+		record := append(head[:len(head):len(head)],
+			strconv.FormatInt(sku.Id, 10),
+			sku.SkuCode,
+			formatNullMoney(sku.Price),
+			formatNullMoney(sku.PromotionPrice),
+			strconv.FormatInt(sku.Stock, 10),
+			strconv.FormatInt(sku.LockStock, 10),
+			strconv.FormatInt(sku.Sale, 10),
+			strconv.FormatInt(sku.LowStock, 10),
+			spData.String,
+		)
+		if err := w.w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *csvExportWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *jsonlExportWriter) write(product *ExportProduct) error {
+	return w.enc.Encode(product)
+}
+
+func (w *jsonlExportWriter) flush() error {
+	return w.w.Flush()
+}
+
+func formatNullMoney(m model.NullMoney) string {
+	if !m.Valid {
+		return ""
+	}
+	return m.Money.String()
+}
@@ -0,0 +1,489 @@
+package catalog
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+// columns of the import file, one row per SKU, rows of the same product_sn
+// make one product whose fields are taken from its first row
+const (
+	colProductSn           = "product_sn"
+	colName                = "name"
+	colBrand               = "brand"
+	colProductCategoryId   = "product_category_id"
+	colProductCategoryName = "product_category_name"
+	colSubTitle            = "sub_title"
+	colDescription         = "description"
+	colKeywords            = "keywords"
+	colUnit                = "unit"
+	colWeight              = "weight"
+	colPrice               = "price"
+	colOriginalPrice       = "original_price"
+	colPromotionPrice      = "promotion_price"
+	colGiftPoint           = "gift_point"
+	colGiftGrowth          = "gift_growth"
+	colLowStock            = "low_stock"
+	colSkuCode             = "sku_code"
+	colSkuPrice            = "sku_price"
+	colSkuPromotionPrice   = "sku_promotion_price"
+	colStock               = "stock"
+	colSkuLowStock         = "sku_low_stock"
+	// spec:颜色 makes {"key":"颜色","value":...} in sp_data
+	specPrefix = "spec:"
+
+	utf8Bom = "\ufeff"
+)
+
+var (
+	requiredColumns = []string{colProductSn, colName, colPrice, colSkuCode}
+	// import columns of the SKU fields named differently from the product's
+	skuColumns = map[string]string{
+		"price":           colSkuPrice,
+		"promotion_price": colSkuPromotionPrice,
+		"low_stock":       colSkuLowStock,
+	}
+)
+
+type (
+	// RowError reports why a row of the import file is rejected.
+	// Line counts from 1 for the header, Column is empty for row-wide errors.
+	RowError struct {
+		Line    int
+		Column  string
+		Message string
+	}
+
+	// ImportReport tells what an import created, or would create on a dry run.
+	ImportReport struct {
+		DryRun   bool
+		Products int
+		Skus     int
+		Errors   []RowError
+	}
+
+	// Importer creates products and their SKUs from a CSV file, as saved by
+	// Excel or any spreadsheet. A product is skipped as a whole when any of
+	// its rows is invalid. Each product is created with its SKUs and saved as
+	// its first revision in one transaction, on behalf of actor.
+	Importer struct {
+		conn         sqlx.SqlConn
+		productModel model.PmsProductModel
+		skuModel     model.PmsSkuStockModel
+		brandModel   model.PmsBrandModel
+		revisions    *Revisions
+		actor        string
+	}
+
+	importRow struct {
+		line      int
+		brandName string
+		product   model.PmsProduct
+		sku       model.PmsSkuStock
+	}
+
+	importProduct struct {
+		rows   []*importRow
+		failed bool
+	}
+
+	rowParser struct {
+		line   int
+		record []string
+		header map[string]int
+		errors []RowError
+	}
+)
+
+func NewImporter(conn sqlx.SqlConn, productModel model.PmsProductModel, skuModel model.PmsSkuStockModel,
+	brandModel model.PmsBrandModel, revisions *Revisions, actor string) *Importer {
+	return &Importer{
+		conn:         conn,
+		productModel: productModel,
+		skuModel:     skuModel,
+		brandModel:   brandModel,
+		revisions:    revisions,
+		actor:        actor,
+	}
+}
+
+// Import reads the CSV from r. With dryRun, rows are only validated.
+// The returned error is for unreadable files, invalid rows go to the report.
+func (im *Importer) Import(r io.Reader, dryRun bool) (*ImportReport, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, specs, err := readHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{DryRun: dryRun}
+	var (
+		order    []string
+		products = make(map[string]*importProduct)
+		skuLines = make(map[string]int)
+		line     = 1
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			report.Errors = append(report.Errors, RowError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		row, errs := parseRow(line, record, header, specs)
+		sn := row.product.ProductSn
+		if prev, ok := skuLines[row.sku.SkuCode]; ok && len(row.sku.SkuCode) > 0 {
+			errs = append(errs, RowError{Line: line, Column: colSkuCode,
+				Message: fmt.Sprintf("duplicates line %d", prev)})
+		} else {
+			skuLines[row.sku.SkuCode] = line
+		}
+
+		p, ok := products[sn]
+		if !ok {
+			p = new(importProduct)
+			products[sn] = p
+			order = append(order, sn)
+		}
+		p.rows = append(p.rows, row)
+		if len(errs) > 0 {
+			p.failed = true
+			report.Errors = append(report.Errors, errs...)
+		}
+	}
+
+	brands := make(map[string]*model.PmsBrand)
+	for _, sn := range order {
+		p := products[sn]
+		if p.failed {
+			continue
+		}
+
+		if err := im.checkExisting(p); err != nil {
+			report.Errors = append(report.Errors, *err)
+			continue
+		}
+
+		first := p.rows[0]
+		if len(first.brandName) > 0 {
+			brand, err := im.findBrand(brands, first.brandName)
+			if err != nil {
+				report.Errors = append(report.Errors, RowError{Line: first.line, Column: colBrand, Message: err.Error()})
+				continue
+			}
+			first.product.BrandId = sql.NullInt64{Int64: brand.Id, Valid: true}
+			first.product.BrandName = brand.Name
+		}
+
+		if !dryRun {
+			if err := im.create(p); err != nil {
+				report.Errors = append(report.Errors, *err)
+				continue
+			}
+		}
+		report.Products++
+		report.Skus += len(p.rows)
+	}
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+
+	return report, nil
+}
+
+func (im *Importer) findBrand(brands map[string]*model.PmsBrand, name string) (*model.PmsBrand, error) {
+	if brand, ok := brands[name]; ok {
+		return brand, nil
+	}
+
+	brand, err := im.brandModel.FindOneByName(name)
+	switch err {
+	case nil:
+		brands[name] = brand
+		return brand, nil
+	case model.ErrNotFound:
+		return nil, fmt.Errorf("brand %q not found", name)
+	default:
+		return nil, err
+	}
+}
+
+// checkExisting fails a product whose product_sn or sku codes are taken
+// already; imports only create products.
+func (im *Importer) checkExisting(p *importProduct) *RowError {
+	first := p.rows[0]
+	product, err := im.productModel.FindOneByProductSn(first.product.ProductSn)
+	switch err {
+	case nil:
+		return &RowError{Line: first.line, Column: colProductSn,
+			Message: fmt.Sprintf("is taken by product %d", product.Id)}
+	case model.ErrNotFound:
+	default:
+		return &RowError{Line: first.line, Message: err.Error()}
+	}
+
+	for _, row := range p.rows {
+		sku, err := im.skuModel.FindOneBySkuCode(row.sku.SkuCode)
+		switch err {
+		case nil:
+			return &RowError{Line: row.line, Column: colSkuCode, Message: fmt.Sprintf("is taken by sku %d", sku.Id)}
+		case model.ErrNotFound:
+		default:
+			return &RowError{Line: row.line, Message: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+func (im *Importer) create(p *importProduct) *RowError {
+	// the stock of the product is rolled up from its SKUs as they're inserted
+	product := p.rows[0].product
+
+	// the row failing, the first one unless a SKU fails
+	line := p.rows[0].line
+	err := im.conn.Transact(func(session sqlx.Session) error {
+		ret, err := im.productModel.TxInsert(session, product)
+		if err != nil {
+			return err
+		}
+		if product.Id, err = ret.LastInsertId(); err != nil {
+			return err
+		}
+
+		for _, row := range p.rows {
+			sku := row.sku
+			sku.ProductId = sql.NullInt64{Int64: product.Id, Valid: true}
+			if !sku.Price.Valid {
+				sku.Price = product.Price
+			}
+			if _, err := im.skuModel.TxInsert(session, sku); err != nil {
+				line = row.line
+				return err
+			}
+		}
+
+		_, err = im.revisions.TxSave(session, product.Id, im.actor)
+		return err
+	})
+	if err != nil {
+		return &RowError{Line: line, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func readHeader(reader *csv.Reader) (map[string]int, []string, error) {
+	record, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("empty import file")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := make(map[string]int, len(record))
+	var specs []string
+	for i, name := range record {
+		if i == 0 {
+			name = strings.TrimPrefix(name, utf8Bom)
+		}
+		name = strings.TrimSpace(name)
+		header[name] = i
+		if strings.HasPrefix(name, specPrefix) {
+			specs = append(specs, name)
+		}
+	}
+	for _, name := range requiredColumns {
+		if _, ok := header[name]; !ok {
+			return nil, nil, fmt.Errorf("missing column %s", name)
+		}
+	}
+
+	return header, specs, nil
+}
+
+func parseRow(line int, record []string, header map[string]int, specs []string) (*importRow, []RowError) {
+	p := &rowParser{
+		line:   line,
+		record: record,
+		header: header,
+	}
+
+	row := &importRow{
+		line:      line,
+		brandName: p.string(colBrand),
+		product: model.PmsProduct{
+			ProductSn:           p.required(colProductSn),
+			Name:                p.required(colName),
+			ProductCategoryId:   p.nullInt(colProductCategoryId),
+			ProductCategoryName: p.nullString(colProductCategoryName),
+			SubTitle:            p.nullString(colSubTitle),
+			Description:         p.string(colDescription),
+			Keywords:            p.nullString(colKeywords),
+			Unit:                p.nullString(colUnit),
+			Weight:              p.nullFloat(colWeight),
+			Price:               p.requiredMoney(colPrice),
+			OriginalPrice:       p.nullMoney(colOriginalPrice),
+			PromotionPrice:      p.nullMoney(colPromotionPrice),
+			GiftPoint:           p.nullInt(colGiftPoint).Int64,
+			GiftGrowth:          p.nullInt(colGiftGrowth).Int64,
+			LowStock:            p.nullInt(colLowStock),
+			Sale:                sql.NullInt64{Valid: true},
+			DeleteStatus:        sql.NullInt64{Valid: true},
+			PublishStatus:       sql.NullInt64{Valid: true},
+			VerifyStatus:        sql.NullInt64{Valid: true},
+			NewStatus:           sql.NullInt64{Valid: true},
+			RecommandStatus:     sql.NullInt64{Valid: true},
+			PreviewStatus:       sql.NullInt64{Valid: true},
+			PromotionType:       sql.NullInt64{Valid: true},
+		},
+		sku: model.PmsSkuStock{
+			SkuCode:        p.required(colSkuCode),
+			Price:          p.nullMoney(colSkuPrice),
+			PromotionPrice: p.nullMoney(colSkuPromotionPrice),
+			Stock:          p.nullInt(colStock).Int64,
+			LowStock:       p.nullInt(colSkuLowStock),
+			Sale:           sql.NullInt64{Valid: true},
+		},
+	}
+	var skuSpecs []model.SkuSpec
+	for _, col := range specs {
+		if value := p.string(col); len(value) > 0 {
+			skuSpecs = append(skuSpecs, model.SkuSpec{Key: strings.TrimPrefix(col, specPrefix), Value: value})
+		}
+	}
+	spData, err := model.FormatSpData(skuSpecs)
+	if err != nil {
+		p.fail("", err.Error())
+	}
+	row.sku.SpData = spData
+
+	if len(p.errors) == 0 {
+		p.validate(ValidateProduct(&row.product), nil)
+		p.validate(ValidateSku(&row.sku), skuColumns)
+	}
+
+	return row, p.errors
+}
+
+// validate reports the FieldError of a validation under the import column,
+// named after the field unless in columns.
+func (p *rowParser) validate(err error, columns map[string]string) {
+	if err == nil {
+		return
+	}
+
+	fe, ok := err.(*FieldError)
+	if !ok {
+		p.fail("", err.Error())
+		return
+	}
+	col, ok := columns[fe.Field]
+	if !ok {
+		col = fe.Field
+	}
+	p.fail(col, fe.Message)
+}
+
+func (p *rowParser) string(col string) string {
+	i, ok := p.header[col]
+	if !ok || i >= len(p.record) {
+		return ""
+	}
+
+	return strings.TrimSpace(p.record[i])
+}
+
+func (p *rowParser) required(col string) string {
+	s := p.string(col)
+	if len(s) == 0 {
+		p.fail(col, "is required")
+	}
+	return s
+}
+
+func (p *rowParser) nullString(col string) sql.NullString {
+	s := p.string(col)
+	return sql.NullString{String: s, Valid: len(s) > 0}
+}
+
+func (p *rowParser) nullInt(col string) sql.NullInt64 {
+	s := p.string(col)
+	if len(s) == 0 {
+		return sql.NullInt64{}
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		p.fail(col, fmt.Sprintf("%q is not an integer", s))
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: v, Valid: true}
+}
+
+func (p *rowParser) nullFloat(col string) sql.NullFloat64 {
+	s := p.string(col)
+	if len(s) == 0 {
+		return sql.NullFloat64{}
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		p.fail(col, fmt.Sprintf("%q is not a number", s))
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: v, Valid: true}
+}
+
+func (p *rowParser) nullMoney(col string) model.NullMoney {
+	s := p.string(col)
+	if len(s) == 0 {
+		return model.NullMoney{}
+	}
+
+	v, err := model.ParseMoney(s)
+	if err != nil {
+		p.fail(col, fmt.Sprintf("%q is not a price", s))
+		return model.NullMoney{}
+	}
+	if v.IsNegative() {
+		p.fail(col, "must not be negative")
+	}
+	return model.NewNullMoney(v)
+}
+
+func (p *rowParser) requiredMoney(col string) model.NullMoney {
+	if len(p.string(col)) == 0 {
+		p.fail(col, "is required")
+		return model.NullMoney{}
+	}
+	return p.nullMoney(col)
+}
+
+func (p *rowParser) fail(col, msg string) {
+	p.errors = append(p.errors, RowError{Line: p.line, Column: col, Message: msg})
+}
+
+func (e RowError) Error() string {
+	if len(e.Column) == 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d, %s: %s", e.Line, e.Column, e.Message)
+}
@@ -0,0 +1,383 @@
+package catalog
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"malltmp/product/model"
+	"malltmp/product/stock"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+type (
+	// fakeDb keeps the rows written by the fake models, a failed
+	// transaction puts back the rows as they were before it.
+	fakeDb struct {
+		products  map[int64]model.PmsProduct
+		skus      map[int64]model.PmsSkuStock
+		revisions []model.PmsProductRevision
+		nextId    int64
+	}
+
+	fakeConn struct {
+		sqlx.SqlConn
+		db           *fakeDb
+		transactions int
+	}
+
+	fakeResult int64
+
+	fakeProductModel struct {
+		model.PmsProductModel
+		db *fakeDb
+	}
+
+	fakeSkuModel struct {
+		model.PmsSkuStockModel
+		db *fakeDb
+		// inserts of these sku codes fail
+		broken map[string]bool
+	}
+
+	fakeBrandModel struct {
+		model.PmsBrandModel
+		brands map[string]*model.PmsBrand
+	}
+
+	fakeAttributeValueModel struct {
+		model.PmsProductAttributeValueModel
+	}
+
+	fakeLadderModel struct {
+		model.PmsProductLadderModel
+	}
+
+	fakeFullReductionModel struct {
+		model.PmsProductFullReductionModel
+	}
+
+	fakeRevisionModel struct {
+		model.PmsProductRevisionModel
+		db *fakeDb
+	}
+)
+
+func newFakeDb() *fakeDb {
+	return &fakeDb{
+		products: make(map[int64]model.PmsProduct),
+		skus:     make(map[int64]model.PmsSkuStock),
+	}
+}
+
+func (db *fakeDb) id() int64 {
+	db.nextId++
+	return db.nextId
+}
+
+func (c *fakeConn) Transact(fn func(session sqlx.Session) error) error {
+	c.transactions++
+	products := make(map[int64]model.PmsProduct, len(c.db.products))
+	for id, p := range c.db.products {
+		products[id] = p
+	}
+	skus := make(map[int64]model.PmsSkuStock, len(c.db.skus))
+	for id, s := range c.db.skus {
+		skus[id] = s
+	}
+	revisions := append([]model.PmsProductRevision(nil), c.db.revisions...)
+
+	if err := fn(nil); err != nil {
+		c.db.products, c.db.skus, c.db.revisions = products, skus, revisions
+		return err
+	}
+
+	return nil
+}
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	return int64(r), nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+func (m *fakeProductModel) FindOneByProductSn(productSn string) (*model.PmsProduct, error) {
+	for _, p := range m.db.products {
+		if p.ProductSn == productSn {
+			return &p, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *fakeProductModel) TxFindOneForUpdate(_ sqlx.Session, id int64) (*model.PmsProduct, error) {
+	p, ok := m.db.products[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (m *fakeProductModel) TxInsert(_ sqlx.Session, data model.PmsProduct) (sql.Result, error) {
+	data.Id = m.db.id()
+	m.db.products[data.Id] = data
+	return fakeResult(data.Id), nil
+}
+
+func (m *fakeProductModel) RollupSkus(_ sqlx.Session, id int64) error {
+	p := m.db.products[id]
+	var stock int64
+	for _, s := range m.db.skus {
+		if s.ProductId.Int64 == id {
+			stock += s.Stock
+		}
+	}
+	p.Stock = sql.NullInt64{Int64: stock, Valid: true}
+	m.db.products[id] = p
+	return nil
+}
+
+func (m *fakeSkuModel) TxFindOneForUpdate(_ sqlx.Session, id int64) (*model.PmsSkuStock, error) {
+	s, ok := m.db.skus[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &s, nil
+}
+
+func (m *fakeSkuModel) FindOneBySkuCode(skuCode string) (*model.PmsSkuStock, error) {
+	for _, s := range m.db.skus {
+		if s.SkuCode == skuCode {
+			return &s, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *fakeSkuModel) TxFindByProductId(_ sqlx.Session, productId int64) ([]*model.PmsSkuStock, error) {
+	var resp []*model.PmsSkuStock
+	for id := int64(1); id <= m.db.nextId; id++ {
+		if s, ok := m.db.skus[id]; ok && s.ProductId.Int64 == productId {
+			resp = append(resp, &s)
+		}
+	}
+	return resp, nil
+}
+
+func (m *fakeSkuModel) TxInsert(_ sqlx.Session, data model.PmsSkuStock) (sql.Result, error) {
+	if m.broken[data.SkuCode] {
+		return nil, errors.New("sku insert failed")
+	}
+	data.Id = m.db.id()
+	m.db.skus[data.Id] = data
+	return fakeResult(data.Id), nil
+}
+
+func (m *fakeBrandModel) FindOneByName(name string) (*model.PmsBrand, error) {
+	brand, ok := m.brands[name]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return brand, nil
+}
+
+func (m *fakeAttributeValueModel) TxFindByProductId(sqlx.Session, int64) ([]*model.PmsProductAttributeValue, error) {
+	return nil, nil
+}
+
+func (m *fakeLadderModel) TxFindByProductId(sqlx.Session, int64) ([]*model.PmsProductLadder, error) {
+	return nil, nil
+}
+
+func (m *fakeFullReductionModel) TxFindByProductId(sqlx.Session, int64) ([]*model.PmsProductFullReduction, error) {
+	return nil, nil
+}
+
+func (m *fakeRevisionModel) TxFindLatest(_ sqlx.Session, productId int64) (*model.PmsProductRevision, error) {
+	for i := len(m.db.revisions) - 1; i >= 0; i-- {
+		if r := m.db.revisions[i]; r.ProductId == productId {
+			return &r, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *fakeRevisionModel) TxInsert(_ sqlx.Session, data model.PmsProductRevision) (sql.Result, error) {
+	data.Id = m.db.id()
+	m.db.revisions = append(m.db.revisions, data)
+	return fakeResult(data.Id), nil
+}
+
+func newFakeImporter(db *fakeDb, brokenSkus ...string) (*Importer, *fakeConn) {
+	conn := &fakeConn{db: db}
+	productModel := &fakeProductModel{db: db}
+	skus := &fakeSkuModel{db: db, broken: make(map[string]bool)}
+	for _, code := range brokenSkus {
+		skus.broken[code] = true
+	}
+	// the stock of products is rolled up from their SKUs
+	skuModel := stock.NewRollupSkuStockModel(conn, skus, productModel)
+	brandModel := &fakeBrandModel{brands: map[string]*model.PmsBrand{
+		"小米": {Id: 6, Name: sql.NullString{String: "小米", Valid: true}},
+	}}
+	revisions := NewRevisions(conn, productModel, skuModel, &fakeAttributeValueModel{}, &fakeLadderModel{},
+		&fakeFullReductionModel{}, &fakeRevisionModel{db: db})
+
+	return NewImporter(conn, productModel, skuModel, brandModel, revisions, "alice"), conn
+}
+
+func TestImport(t *testing.T) {
+	const file = utf8Bom + "product_sn,name,brand,price,sku_code,sku_price,stock,spec:颜色\n" +
+		"P1,手机,小米,1999.00,S1,,10,黑色\n" +
+		"P1,手机,小米,1999.00,S2,2099.00,5,白色\n" +
+		"P2,耳机,,99.00,S3,,3,\n"
+
+	db := newFakeDb()
+	im, conn := newFakeImporter(db)
+	report, err := im.Import(strings.NewReader(file), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Products != 2 || report.Skus != 3 || len(report.Errors) != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	if conn.transactions != 2 {
+		t.Errorf("%d transactions, want one a product", conn.transactions)
+	}
+
+	var phone *model.PmsProduct
+	for _, p := range db.products {
+		if p.ProductSn == "P1" {
+			p := p
+			phone = &p
+		}
+	}
+	if phone == nil {
+		t.Fatal("product P1 not created")
+	}
+	if phone.Stock.Int64 != 15 || phone.BrandId.Int64 != 6 || phone.BrandName.String != "小米" {
+		t.Errorf("product = %+v", phone)
+	}
+	for _, s := range db.skus {
+		if s.ProductId.Int64 == 0 || !s.Price.Valid {
+			t.Errorf("sku %s: product %d, price %v", s.SkuCode, s.ProductId.Int64, s.Price)
+		}
+		if s.SkuCode == "S1" && (s.Price != phone.Price || s.SpData.String != `[{"key":"颜色","value":"黑色"}]`) {
+			t.Errorf("sku S1 = %+v", s)
+		}
+	}
+
+	if len(db.revisions) != 2 {
+		t.Fatalf("%d revisions, want 2", len(db.revisions))
+	}
+	for _, r := range db.revisions {
+		if r.Revision != 1 || r.Actor != "alice" {
+			t.Errorf("revision = %+v", r)
+		}
+		// the revision sees the SKUs inserted in the same transaction
+		if r.ProductId == phone.Id && !strings.Contains(r.Snapshot, `"SkuCode":"S2"`) {
+			t.Errorf("snapshot of %d misses its skus: %s", r.ProductId, r.Snapshot)
+		}
+	}
+}
+
+func TestImportRejectsRows(t *testing.T) {
+	const file = "product_sn,name,brand,price,sku_code\n" +
+		"P1,手机,,abc,S1\n" +
+		"P1,手机,,1999.00,S2\n" +
+		"P2,耳机,,99.00,S2\n" +
+		"P3,音箱,华为,199.00,S4\n" +
+		"P4,,,199.00,S5\n" +
+		"TAKEN,手表,,299.00,S6\n" +
+		"P7,手环,,199.00,TAKEN\n"
+
+	db := newFakeDb()
+	db.products[1] = model.PmsProduct{Id: 1, ProductSn: "TAKEN"}
+	db.skus[2] = model.PmsSkuStock{Id: 2, SkuCode: "TAKEN"}
+	db.nextId = 2
+	im, conn := newFakeImporter(db)
+
+	report, err := im.Import(strings.NewReader(file), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RowError{
+		{Line: 2, Column: colPrice, Message: `"abc" is not a price`},
+		{Line: 4, Column: colSkuCode, Message: "duplicates line 3"},
+		{Line: 5, Column: colBrand, Message: `brand "华为" not found`},
+		{Line: 6, Column: colName, Message: "is required"},
+		{Line: 7, Column: colProductSn, Message: "is taken by product 1"},
+		{Line: 8, Column: colSkuCode, Message: "is taken by sku 2"},
+	}
+	if len(report.Errors) != len(want) {
+		t.Fatalf("errors = %+v, want %+v", report.Errors, want)
+	}
+	for i, w := range want {
+		if report.Errors[i] != w {
+			t.Errorf("error %d = %+v, want %+v", i, report.Errors[i], w)
+		}
+	}
+	if report.Products != 0 || conn.transactions != 0 || len(db.products) != 1 {
+		t.Errorf("report = %+v, %d transactions", report, conn.transactions)
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	const file = "product_sn,name,price,sku_code\nP1,手机,1999.00,S1\nP1,手机,1999.00,S2\n"
+
+	db := newFakeDb()
+	im, conn := newFakeImporter(db)
+	report, err := im.Import(strings.NewReader(file), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.DryRun || report.Products != 1 || report.Skus != 2 || len(report.Errors) != 0 {
+		t.Errorf("report = %+v", report)
+	}
+	if conn.transactions != 0 || len(db.products) != 0 || len(db.skus) != 0 {
+		t.Errorf("dry run wrote %d products, %d skus", len(db.products), len(db.skus))
+	}
+}
+
+func TestImportRollsBackProduct(t *testing.T) {
+	const file = "product_sn,name,price,sku_code\n" +
+		"P1,手机,1999.00,S1\n" +
+		"P1,手机,1999.00,S2\n" +
+		"P2,耳机,99.00,S3\n"
+
+	db := newFakeDb()
+	im, _ := newFakeImporter(db, "S2")
+	report, err := im.Import(strings.NewReader(file), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Line != 3 || report.Products != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// nothing of P1 is left, P2 is imported
+	if len(db.products) != 1 || len(db.skus) != 1 || len(db.revisions) != 1 {
+		t.Errorf("%d products, %d skus, %d revisions left, want 1 each",
+			len(db.products), len(db.skus), len(db.revisions))
+	}
+	for _, p := range db.products {
+		if p.ProductSn != "P2" {
+			t.Errorf("product %s left", p.ProductSn)
+		}
+	}
+}
+
+func TestImportEmptyFile(t *testing.T) {
+	im, _ := newFakeImporter(newFakeDb())
+	if _, err := im.Import(strings.NewReader(""), false); err == nil {
+		t.Error("empty file imported")
+	}
+	if _, err := im.Import(strings.NewReader("product_sn,name,price\n"), false); err == nil ||
+		err.Error() != "missing column sku_code" {
+		t.Errorf("error = %v, want missing column sku_code", err)
+	}
+}
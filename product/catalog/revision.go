@@ -0,0 +1,451 @@
+package catalog
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"malltmp/product/audit"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+type (
+	// Snapshot is a product with everything its page is made of. Stock, sale
+	// and the review and publish status are left out, they aren't content.
+	Snapshot struct {
+		Product         model.PmsProduct                  `json:"product"`
+		Skus            []*model.PmsSkuStock              `json:"skus"`
+		AttributeValues []*model.PmsProductAttributeValue `json:"attributeValues"`
+		Ladders         []*model.PmsProductLadder         `json:"ladders"`
+		FullReductions  []*model.PmsProductFullReduction  `json:"fullReductions"`
+	}
+
+	// SkuLockedError tells that a SKU can't be removed by a restore, having
+	// stock locked for orders.
+	SkuLockedError struct {
+		SkuId     int64
+		LockStock int64
+	}
+
+	// Revisions keeps snapshots of products, one revision per save, and
+	// restores them.
+	Revisions struct {
+		conn                sqlx.SqlConn
+		productModel        model.PmsProductModel
+		skuModel            model.PmsSkuStockModel
+		attributeValueModel model.PmsProductAttributeValueModel
+		ladderModel         model.PmsProductLadderModel
+		fullReductionModel  model.PmsProductFullReductionModel
+		revisionModel       model.PmsProductRevisionModel
+	}
+)
+
+func NewRevisions(conn sqlx.SqlConn, productModel model.PmsProductModel, skuModel model.PmsSkuStockModel,
+	attributeValueModel model.PmsProductAttributeValueModel, ladderModel model.PmsProductLadderModel,
+	fullReductionModel model.PmsProductFullReductionModel,
+	revisionModel model.PmsProductRevisionModel) *Revisions {
+	return &Revisions{
+		conn:                conn,
+		productModel:        productModel,
+		skuModel:            skuModel,
+		attributeValueModel: attributeValueModel,
+		ladderModel:         ladderModel,
+		fullReductionModel:  fullReductionModel,
+		revisionModel:       revisionModel,
+	}
+}
+
+// Save takes a snapshot of the product as its next revision. Nothing is saved
+// when the product didn't change since the latest revision, which is returned.
+func (rv *Revisions) Save(productId int64, actor string) (*model.PmsProductRevision, error) {
+	var revision *model.PmsProductRevision
+	err := rv.conn.Transact(func(session sqlx.Session) error {
+		var err error
+		revision, err = rv.TxSave(session, productId, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// TxSave is Save in session, the snapshot taken sees the rows written in it.
+func (rv *Revisions) TxSave(session sqlx.Session, productId int64, actor string) (*model.PmsProductRevision, error) {
+	// locking the product numbers its revisions one at a time
+	product, err := rv.productModel.TxFindOneForUpdate(session, productId)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := rv.snapshot(session, product)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var next int64 = 1
+	latest, err := rv.revisionModel.TxFindLatest(session, productId)
+	switch err {
+	case nil:
+		if latest.Snapshot == string(data) {
+			return latest, nil
+		}
+		next = latest.Revision + 1
+	case model.ErrNotFound:
+	default:
+		return nil, err
+	}
+
+	revision := &model.PmsProductRevision{
+		ProductId: productId,
+		Revision:  next,
+		Snapshot:  string(data),
+		Actor:     actor,
+	}
+	ret, err := rv.revisionModel.TxInsert(session, *revision)
+	if err != nil {
+		return nil, err
+	}
+	revision.Id, err = ret.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// List pages through the revisions of a product, oldest first.
+func (rv *Revisions) List(productId, lastId int64, limit int) ([]*model.PmsProductRevision, error) {
+	return rv.revisionModel.FindByProductId(productId, lastId, limit)
+}
+
+// Diff compares two revisions of a product. Fields are named by path, like
+// product.price or skus[S-12].price; SKUs are matched by code, as their IDs
+// change when they're removed and added back, ladders by count, full
+// reductions by full price and attribute values by attribute.
+func (rv *Revisions) Diff(productId, from, to int64) ([]audit.FieldChange, error) {
+	before, err := rv.load(productId, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := rv.load(productId, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := prefixChanges("product", audit.Diff(before.Product, after.Product))
+	changes = append(changes, diffRows("skus", skuRows(before.Skus), skuRows(after.Skus))...)
+	changes = append(changes, diffRows("attributeValues", attributeValueRows(before.AttributeValues),
+		attributeValueRows(after.AttributeValues))...)
+	changes = append(changes, diffRows("ladders", ladderRows(before.Ladders), ladderRows(after.Ladders))...)
+	changes = append(changes, diffRows("fullReductions", fullReductionRows(before.FullReductions),
+		fullReductionRows(after.FullReductions))...)
+
+	return changes, nil
+}
+
+// Restore puts the product back as it was in revision, in one transaction,
+// and saves the result as a new revision. Stock, sale, and the review and
+// publish status stay as they are. SKUs are matched by ID, or else by code.
+// SKUs missing from the revision are removed, unless they have stock locked;
+// SKUs missing from the product are added back without stock.
+func (rv *Revisions) Restore(productId, revision int64, actor string) (*model.PmsProductRevision, error) {
+	snapshot, err := rv.load(productId, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved *model.PmsProductRevision
+	err = rv.conn.Transact(func(session sqlx.Session) error {
+		current, err := rv.productModel.TxFindOneForUpdate(session, productId)
+		if err != nil {
+			return err
+		}
+
+		after := snapshot.Product
+		after.Id = productId
+		after.Stock = current.Stock
+		after.Sale = current.Sale
+		after.VerifyStatus = current.VerifyStatus
+		after.PublishStatus = current.PublishStatus
+		after.PreviewStatus = current.PreviewStatus
+		after.DeleteStatus = current.DeleteStatus
+		// the promotion window worker starts the window again if it's on
+		after.PreviousPromotionType = sql.NullInt64{}
+		if err := rv.productModel.TxUpdate(session, after); err != nil {
+			return err
+		}
+
+		if err := rv.restoreSkus(session, productId, snapshot.Skus); err != nil {
+			return err
+		}
+		if err := rv.restoreChildren(session, productId, snapshot); err != nil {
+			return err
+		}
+
+		saved, err = rv.TxSave(session, productId, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+func (rv *Revisions) restoreSkus(session sqlx.Session, productId int64, skus []*model.PmsSkuStock) error {
+	current, err := rv.skuModel.TxFindByProductId(session, productId)
+	if err != nil {
+		return err
+	}
+
+	// a SKU of the revision is the current one of its ID, or else of its
+	// code, so that a SKU removed and added again isn't replaced
+	byId := make(map[int64]*model.PmsSkuStock, len(current))
+	byCode := make(map[string]*model.PmsSkuStock, len(current))
+	for _, sku := range current {
+		byId[sku.Id] = sku
+		byCode[sku.SkuCode] = sku
+	}
+	matched := make(map[*model.PmsSkuStock]*model.PmsSkuStock, len(skus))
+	kept := make(map[int64]struct{}, len(skus))
+	for _, sku := range skus {
+		if match, ok := byId[sku.Id]; ok {
+			matched[sku] = match
+			kept[match.Id] = struct{}{}
+		}
+	}
+	for _, sku := range skus {
+		if _, ok := matched[sku]; ok {
+			continue
+		}
+		if match, ok := byCode[sku.SkuCode]; ok {
+			if _, taken := kept[match.Id]; !taken {
+				matched[sku] = match
+				kept[match.Id] = struct{}{}
+			}
+		}
+	}
+
+	for _, sku := range current {
+		if _, ok := kept[sku.Id]; ok {
+			continue
+		}
+
+		if sku.LockStock > 0 {
+			return &SkuLockedError{SkuId: sku.Id, LockStock: sku.LockStock}
+		}
+		if err := rv.skuModel.TxDelete(session, sku.Id); err != nil {
+			return err
+		}
+	}
+
+	for _, sku := range skus {
+		data := *sku
+		data.ProductId = sql.NullInt64{Int64: productId, Valid: true}
+		if match, ok := matched[sku]; ok {
+			data.Id = match.Id
+			if err := rv.skuModel.TxUpdateInfo(session, data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		data.Stock, data.LockStock, data.Sale = 0, 0, sql.NullInt64{Valid: true}
+		if _, err := rv.skuModel.TxInsert(session, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreChildren replaces the attribute values, ladders and full reductions.
+func (rv *Revisions) restoreChildren(session sqlx.Session, productId int64, snapshot *Snapshot) error {
+	productIdValue := sql.NullInt64{Int64: productId, Valid: true}
+
+	if err := rv.attributeValueModel.TxDeleteByProductId(session, productId); err != nil {
+		return err
+	}
+	for _, value := range snapshot.AttributeValues {
+		data := *value
+		data.ProductId = productIdValue
+		if _, err := rv.attributeValueModel.TxInsert(session, data); err != nil {
+			return err
+		}
+	}
+
+	if err := rv.ladderModel.TxDeleteByProductId(session, productId); err != nil {
+		return err
+	}
+	for _, ladder := range snapshot.Ladders {
+		data := *ladder
+		data.ProductId = productIdValue
+		if _, err := rv.ladderModel.TxInsert(session, data); err != nil {
+			return err
+		}
+	}
+
+	if err := rv.fullReductionModel.TxDeleteByProductId(session, productId); err != nil {
+		return err
+	}
+	for _, reduction := range snapshot.FullReductions {
+		data := *reduction
+		data.ProductId = productIdValue
+		if _, err := rv.fullReductionModel.TxInsert(session, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rv *Revisions) snapshot(session sqlx.Session, product *model.PmsProduct) (*Snapshot, error) {
+	skus, err := rv.skuModel.TxFindByProductId(session, product.Id)
+	if err != nil {
+		return nil, err
+	}
+	attributeValues, err := rv.attributeValueModel.TxFindByProductId(session, product.Id)
+	if err != nil {
+		return nil, err
+	}
+	ladders, err := rv.ladderModel.TxFindByProductId(session, product.Id)
+	if err != nil {
+		return nil, err
+	}
+	fullReductions, err := rv.fullReductionModel.TxFindByProductId(session, product.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		Product:         *product,
+		Skus:            skus,
+		AttributeValues: attributeValues,
+		Ladders:         ladders,
+		FullReductions:  fullReductions,
+	}
+	p := &snapshot.Product
+	p.Stock, p.Sale = sql.NullInt64{}, sql.NullInt64{}
+	p.VerifyStatus, p.PublishStatus, p.PreviewStatus = sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}
+	p.DeleteStatus = sql.NullInt64{}
+	p.UpdateTime = time.Time{}
+	if p.PreviousPromotionType.Valid {
+		// a started promotion window, keep the promotion type of the product
+		p.PromotionType, p.PreviousPromotionType = p.PreviousPromotionType, sql.NullInt64{}
+	}
+	for _, sku := range snapshot.Skus {
+		sku.Stock, sku.LockStock, sku.Sale = 0, 0, sql.NullInt64{}
+	}
+
+	return snapshot, nil
+}
+
+func (rv *Revisions) load(productId, revision int64) (*Snapshot, error) {
+	r, err := rv.revisionModel.FindOneByProductIdRevision(productId, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal([]byte(r.Snapshot), &snapshot); err != nil {
+		return nil, fmt.Errorf("revision %d of product %d: %v", revision, productId, err)
+	}
+
+	return &snapshot, nil
+}
+
+func (e *SkuLockedError) Error() string {
+	return fmt.Sprintf("sku %d has %d stock locked, can't be removed", e.SkuId, e.LockStock)
+}
+
+// diffRows compares the rows of a collection, keyed by what identifies them,
+// in the order of the keys so that the same revisions always diff the same.
+func diffRows(name string, before, after map[string]interface{}) []audit.FieldChange {
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sortKeys(keys)
+
+	var changes []audit.FieldChange
+	for _, key := range keys {
+		changes = append(changes, prefixChanges(fmt.Sprintf("%s[%s]", name, key),
+			audit.Diff(before[key], after[key]))...)
+	}
+
+	return changes
+}
+
+// sortKeys sorts the keys of rows, ids, counts or prices, by their value.
+func sortKeys(keys []string) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, aerr := strconv.ParseFloat(keys[i], 64)
+		b, berr := strconv.ParseFloat(keys[j], 64)
+		if aerr != nil || berr != nil || a == b {
+			return keys[i] < keys[j]
+		}
+		return a < b
+	})
+}
+
+func prefixChanges(prefix string, changes []audit.FieldChange) []audit.FieldChange {
+	for i := range changes {
+		changes[i].Field = prefix + "." + changes[i].Field
+	}
+
+	return changes
+}
+
+func skuRows(skus []*model.PmsSkuStock) map[string]interface{} {
+	rows := make(map[string]interface{}, len(skus))
+	for _, sku := range skus {
+		row := *sku
+		// a SKU removed and added back has a new ID, but is the same
+		row.Id = 0
+		rows[sku.SkuCode] = row
+	}
+
+	return rows
+}
+
+func attributeValueRows(values []*model.PmsProductAttributeValue) map[string]interface{} {
+	rows := make(map[string]interface{}, len(values))
+	for _, value := range values {
+		rows[strconv.FormatInt(value.ProductAttributeId.Int64, 10)] = value
+	}
+
+	return rows
+}
+
+func ladderRows(ladders []*model.PmsProductLadder) map[string]interface{} {
+	rows := make(map[string]interface{}, len(ladders))
+	for _, ladder := range ladders {
+		rows[strconv.FormatInt(ladder.Count.Int64, 10)] = ladder
+	}
+
+	return rows
+}
+
+func fullReductionRows(reductions []*model.PmsProductFullReduction) map[string]interface{} {
+	rows := make(map[string]interface{}, len(reductions))
+	for _, reduction := range reductions {
+		rows[reduction.FullPrice.Money.String()] = reduction
+	}
+
+	return rows
+}
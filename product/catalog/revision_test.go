@@ -0,0 +1,189 @@
+package catalog
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"malltmp/product/model"
+	"malltmp/product/stock"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+func (m *fakeProductModel) TxUpdate(_ sqlx.Session, data model.PmsProduct) error {
+	m.db.products[data.Id] = data
+	return nil
+}
+
+func (m *fakeSkuModel) TxUpdateInfo(_ sqlx.Session, data model.PmsSkuStock) error {
+	s := m.db.skus[data.Id]
+	data.Stock, data.LockStock, data.Sale = s.Stock, s.LockStock, s.Sale
+	m.db.skus[data.Id] = data
+	return nil
+}
+
+func (m *fakeSkuModel) TxDelete(_ sqlx.Session, id int64) error {
+	delete(m.db.skus, id)
+	return nil
+}
+
+func (m *fakeAttributeValueModel) TxDeleteByProductId(sqlx.Session, int64) error {
+	return nil
+}
+
+func (m *fakeLadderModel) TxDeleteByProductId(sqlx.Session, int64) error {
+	return nil
+}
+
+func (m *fakeFullReductionModel) TxDeleteByProductId(sqlx.Session, int64) error {
+	return nil
+}
+
+func (m *fakeRevisionModel) FindOneByProductIdRevision(productId, revision int64) (*model.PmsProductRevision, error) {
+	for _, r := range m.db.revisions {
+		if r.ProductId == productId && r.Revision == revision {
+			return &r, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func newFakeRevisions(db *fakeDb) *Revisions {
+	conn := &fakeConn{db: db}
+	productModel := &fakeProductModel{db: db}
+	skuModel := stock.NewRollupSkuStockModel(conn, &fakeSkuModel{db: db}, productModel)
+	return NewRevisions(conn, productModel, skuModel, &fakeAttributeValueModel{},
+		&fakeLadderModel{}, &fakeFullReductionModel{}, &fakeRevisionModel{db: db})
+}
+
+func saveSnapshot(t *testing.T, db *fakeDb, snapshot Snapshot) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.revisions = append(db.revisions, model.PmsProductRevision{
+		Id:        db.id(),
+		ProductId: snapshot.Product.Id,
+		Revision:  int64(len(db.revisions) + 1),
+		Snapshot:  string(data),
+	})
+}
+
+func TestRestore(t *testing.T) {
+	productId := sql.NullInt64{Int64: 1, Valid: true}
+	db := newFakeDb()
+	db.products[1] = model.PmsProduct{Id: 1, Name: "new", Stock: sql.NullInt64{Int64: 8, Valid: true}}
+	db.skus[2] = model.PmsSkuStock{Id: 2, ProductId: productId, SkuCode: "S2-new", Stock: 5, LockStock: 1}
+	db.skus[3] = model.PmsSkuStock{Id: 3, ProductId: productId, SkuCode: "S3", Stock: 3}
+	db.nextId = 3
+	saveSnapshot(t, db, Snapshot{
+		Product: model.PmsProduct{Id: 1, Name: "old"},
+		Skus: []*model.PmsSkuStock{
+			{Id: 2, ProductId: productId, SkuCode: "S2"},
+			{Id: 1, ProductId: productId, SkuCode: "S1"},
+		},
+	})
+
+	revision, err := newFakeRevisions(db).Restore(1, 1, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision.Revision != 2 || revision.Actor != "alice" {
+		t.Errorf("revision = %+v, want the second", revision)
+	}
+
+	product := db.products[1]
+	if product.Name != "old" || product.Stock.Int64 != 5 {
+		t.Errorf("product = %+v, want old with the stock of its skus", product)
+	}
+	// S2 keeps its stock, S3 is removed, S1 comes back without stock
+	if len(db.skus) != 2 || db.skus[2].SkuCode != "S2" || db.skus[2].Stock != 5 || db.skus[2].LockStock != 1 {
+		t.Errorf("skus = %+v", db.skus)
+	}
+	for _, s := range db.skus {
+		if s.SkuCode == "S1" && (s.Stock != 0 || s.ProductId != productId) {
+			t.Errorf("restored sku = %+v", s)
+		}
+	}
+}
+
+func TestRestoreKeepsLockedSku(t *testing.T) {
+	productId := sql.NullInt64{Int64: 1, Valid: true}
+	db := newFakeDb()
+	db.products[1] = model.PmsProduct{Id: 1, Name: "new"}
+	db.skus[2] = model.PmsSkuStock{Id: 2, ProductId: productId, SkuCode: "S2", Stock: 5, LockStock: 2}
+	db.nextId = 2
+	saveSnapshot(t, db, Snapshot{Product: model.PmsProduct{Id: 1, Name: "old"}})
+
+	_, err := newFakeRevisions(db).Restore(1, 1, "alice")
+	if lerr, ok := err.(*SkuLockedError); !ok || lerr.SkuId != 2 || lerr.LockStock != 2 {
+		t.Fatalf("error = %v, want a SkuLockedError", err)
+	}
+	if db.products[1].Name != "new" || len(db.skus) != 1 || len(db.revisions) != 1 {
+		t.Errorf("failed restore left product %+v, %d skus, %d revisions",
+			db.products[1], len(db.skus), len(db.revisions))
+	}
+}
+
+func lowStock(n int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: n, Valid: true}
+}
+
+func TestDiffRowsInKeyOrder(t *testing.T) {
+	before := skuRows([]*model.PmsSkuStock{
+		{Id: 10, SkuCode: "10", LowStock: lowStock(1)},
+		{Id: 2, SkuCode: "2", LowStock: lowStock(1)},
+	})
+	after := skuRows([]*model.PmsSkuStock{
+		{Id: 10, SkuCode: "10", LowStock: lowStock(2)},
+		{Id: 9, SkuCode: "9", LowStock: lowStock(1)},
+	})
+
+	var fields []string
+	for _, change := range diffRows("skus", before, after) {
+		if change.Field == "skus[2].low_stock" || change.Field == "skus[9].low_stock" ||
+			change.Field == "skus[10].low_stock" {
+			fields = append(fields, change.Field)
+		}
+	}
+	want := []string{"skus[2].low_stock", "skus[9].low_stock", "skus[10].low_stock"}
+	if len(fields) != len(want) {
+		t.Fatalf("fields = %v, want %v", fields, want)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("fields = %v, want %v", fields, want)
+			break
+		}
+	}
+}
+
+func TestDiffSkuAddedBack(t *testing.T) {
+	before := skuRows([]*model.PmsSkuStock{{Id: 2, SkuCode: "S2", LowStock: lowStock(1)}})
+	after := skuRows([]*model.PmsSkuStock{{Id: 7, SkuCode: "S2", LowStock: lowStock(1)}})
+
+	if changes := diffRows("skus", before, after); len(changes) != 0 {
+		t.Errorf("changes = %+v, want none", changes)
+	}
+}
+
+func TestRestoreSkuAddedBack(t *testing.T) {
+	productId := sql.NullInt64{Int64: 1, Valid: true}
+	db := newFakeDb()
+	db.products[1] = model.PmsProduct{Id: 1, Name: "new"}
+	// S2 was removed and added back as 3
+	db.skus[3] = model.PmsSkuStock{Id: 3, ProductId: productId, SkuCode: "S2", Stock: 5, LockStock: 1}
+	db.nextId = 3
+	saveSnapshot(t, db, Snapshot{
+		Product: model.PmsProduct{Id: 1, Name: "old"},
+		Skus:    []*model.PmsSkuStock{{Id: 2, ProductId: productId, SkuCode: "S2", LowStock: lowStock(2)}},
+	})
+
+	if _, err := newFakeRevisions(db).Restore(1, 1, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if s := db.skus[3]; len(db.skus) != 1 || s.LowStock.Int64 != 2 || s.Stock != 5 || s.LockStock != 1 {
+		t.Errorf("skus = %+v, want S2 updated in place", db.skus)
+	}
+}
@@ -0,0 +1,160 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"malltmp/product/model"
+)
+
+// values of the enum columns of pms_product_attribute
+const (
+	maxAttributeSelectType = 2 // 0->唯一；1->单选；2->多选
+	maxAttributeInputType  = 1 // 0->手工录入；1->从列表中选取
+	maxAttributeType       = 1 // 0->规格；1->参数
+)
+
+type (
+	// FieldError tells why a column of a row is invalid, Field being the column.
+	FieldError struct {
+		Field   string
+		Message string
+	}
+
+	moneyField struct {
+		name  string
+		value model.NullMoney
+	}
+
+	intField struct {
+		name  string
+		value int64
+	}
+)
+
+// ValidateProduct checks the columns of a product set by merchants, the same
+// whether it is imported or edited.
+func ValidateProduct(p *model.PmsProduct) error {
+	switch {
+	case len(strings.TrimSpace(p.Name)) == 0:
+		return &FieldError{Field: "name", Message: "is required"}
+	case len(strings.TrimSpace(p.ProductSn)) == 0:
+		return &FieldError{Field: "product_sn", Message: "is required"}
+	case !p.Price.Valid:
+		return &FieldError{Field: "price", Message: "is required"}
+	}
+
+	if err := validatePrices(
+		moneyField{"price", p.Price},
+		moneyField{"original_price", p.OriginalPrice},
+		moneyField{"promotion_price", p.PromotionPrice},
+	); err != nil {
+		return err
+	}
+	if p.Weight.Float64 < 0 {
+		return &FieldError{Field: "weight", Message: "must not be negative"}
+	}
+	if p.PromotionType.Int64 < model.PromotionTypeNone || p.PromotionType.Int64 > model.PromotionTypeFlashSale {
+		return &FieldError{Field: "promotion_type", Message: "is not a promotion type"}
+	}
+
+	return validateNotNegative(
+		intField{"gift_point", p.GiftPoint},
+		intField{"gift_growth", p.GiftGrowth},
+		intField{"low_stock", p.LowStock.Int64},
+		intField{"use_point_limit", p.UsePointLimit.Int64},
+		intField{"promotion_per_limit", p.PromotionPerLimit.Int64},
+	)
+}
+
+// ValidateSku checks the columns of a SKU set by merchants.
+func ValidateSku(s *model.PmsSkuStock) error {
+	if len(strings.TrimSpace(s.SkuCode)) == 0 {
+		return &FieldError{Field: "sku_code", Message: "is required"}
+	}
+	if err := validatePrices(
+		moneyField{"price", s.Price},
+		moneyField{"promotion_price", s.PromotionPrice},
+	); err != nil {
+		return err
+	}
+	if _, err := model.ParseSpData(s.SpData); err != nil {
+		return &FieldError{Field: "sp_data", Message: err.Error()}
+	}
+
+	return validateNotNegative(
+		intField{"stock", s.Stock},
+		intField{"low_stock", s.LowStock.Int64},
+	)
+}
+
+// ValidateBrand checks the columns of a brand.
+func ValidateBrand(b *model.PmsBrand) error {
+	if len(strings.TrimSpace(b.Name.String)) == 0 {
+		return &FieldError{Field: "name", Message: "is required"}
+	}
+	if len([]rune(b.FirstLetter.String)) > 1 {
+		return &FieldError{Field: "first_letter", Message: "must be one letter"}
+	}
+
+	return nil
+}
+
+// ValidateAttribute checks the columns of a product attribute.
+func ValidateAttribute(a *model.PmsProductAttribute) error {
+	switch {
+	case len(strings.TrimSpace(a.Name.String)) == 0:
+		return &FieldError{Field: "name", Message: "is required"}
+	case !a.ProductAttributeCategoryId.Valid:
+		return &FieldError{Field: "product_attribute_category_id", Message: "is required"}
+	case a.SelectType.Int64 < 0 || a.SelectType.Int64 > maxAttributeSelectType:
+		return &FieldError{Field: "select_type", Message: "is not a select type"}
+	case a.InputType.Int64 < 0 || a.InputType.Int64 > maxAttributeInputType:
+		return &FieldError{Field: "input_type", Message: "is not an input type"}
+	case a.Type.Int64 < 0 || a.Type.Int64 > maxAttributeType:
+		return &FieldError{Field: "type", Message: "is not an attribute type"}
+	}
+
+	return nil
+}
+
+// ValidateAttributeValues checks the attribute values of a product, one per attribute.
+func ValidateAttributeValues(values []*model.PmsProductAttributeValue) error {
+	seen := make(map[int64]bool, len(values))
+	for _, v := range values {
+		if !v.ProductAttributeId.Valid {
+			return &FieldError{Field: "product_attribute_id", Message: "is required"}
+		}
+		if seen[v.ProductAttributeId.Int64] {
+			return &FieldError{Field: "product_attribute_id",
+				Message: fmt.Sprintf("%d is repeated", v.ProductAttributeId.Int64)}
+		}
+		seen[v.ProductAttributeId.Int64] = true
+	}
+
+	return nil
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+func validatePrices(prices ...moneyField) error {
+	for _, price := range prices {
+		if price.value.Valid && price.value.Money.IsNegative() {
+			return &FieldError{Field: price.name, Message: "must not be negative"}
+		}
+	}
+
+	return nil
+}
+
+func validateNotNegative(values ...intField) error {
+	for _, v := range values {
+		if v.value < 0 {
+			return &FieldError{Field: v.name, Message: "must not be negative"}
+		}
+	}
+
+	return nil
+}
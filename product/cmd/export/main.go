@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"malltmp/product/catalog"
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/conf"
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+var (
+	configFile   = flag.String("f", "etc/product-api.yaml", "the config file")
+	format       = flag.String("format", catalog.FormatCsv, "the export format, csv or jsonl")
+	output       = flag.String("o", "", "the file to write, stdout if empty")
+	brandId      = flag.Int64("brand", 0, "only export products of the brand id")
+	categoryId   = flag.Int64("category", 0, "only export products of the category id")
+	updatedSince = flag.String("updated-since", "", "only export products updated since, as 2006-01-02 or RFC3339")
+)
+
+type Config struct {
+	Mysql struct {
+		DataSource string
+	}
+}
+
+func main() {
+	flag.Parse()
+	os.Exit(run())
+}
+
+// run exports and returns the exit code, so that the deferred close of the
+// output runs before exiting.
+func run() int {
+	filter := model.PmsProductFilter{
+		BrandId:           *brandId,
+		ProductCategoryId: *categoryId,
+	}
+	if len(*updatedSince) > 0 {
+		since, err := parseTime(*updatedSince)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -updated-since: %v\n", err)
+			return 2
+		}
+		filter.UpdatedSince = since
+	}
+
+	var c Config
+	conf.MustLoad(*configFile, &c)
+
+	var out io.Writer = os.Stdout
+	if len(*output) > 0 {
+		f, err := os.Create(*output)
+		logx.Must(err)
+		defer f.Close()
+		out = f
+	}
+
+	conn := sqlx.NewMysql(c.Mysql.DataSource)
+	exporter := catalog.NewExporter(model.NewPmsProductModel(conn), model.NewPmsSkuStockModel(conn),
+		model.NewPmsBrandModel(conn), model.NewPmsProductAttributeModel(conn),
+		model.NewPmsProductAttributeValueModel(conn))
+
+	count, err := exporter.Export(out, *format, filter)
+	fmt.Fprintf(os.Stderr, "%d products exported\n", count)
+	if err != nil {
+		logx.Error(err)
+		return 1
+	}
+
+	return 0
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
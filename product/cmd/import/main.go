@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"malltmp/product/audit"
+	"malltmp/product/catalog"
+	"malltmp/product/model"
+	"malltmp/product/stock"
+
+	"github.com/tal-tech/go-zero/core/conf"
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+var (
+	configFile = flag.String("f", "etc/product-api.yaml", "the config file")
+	file       = flag.String("file", "", "the csv file to import")
+	dryRun     = flag.Bool("dry-run", false, "only validate the file")
+)
+
+type Config struct {
+	Mysql struct {
+		DataSource string
+	}
+}
+
+func main() {
+	flag.Parse()
+	if len(*file) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var c Config
+	conf.MustLoad(*configFile, &c)
+
+	in, err := os.Open(*file)
+	logx.Must(err)
+	defer in.Close()
+
+	conn := sqlx.NewMysql(c.Mysql.DataSource)
+	recorder := audit.NewRecorder(conn, model.NewPmsAuditLogModel(conn))
+	productModel := audit.NewProductModel(model.NewPmsProductModel(conn), recorder, audit.ActorSystem)
+	skuStockModel := stock.NewRollupSkuStockModel(conn, stock.NewLedgerSkuStockModel(conn,
+		audit.NewSkuStockModel(model.NewPmsSkuStockModel(conn), recorder, audit.ActorSystem),
+		model.NewPmsSkuStockLedgerModel(conn)), productModel)
+	revisions := catalog.NewRevisions(conn, productModel, skuStockModel, model.NewPmsProductAttributeValueModel(conn),
+		model.NewPmsProductLadderModel(conn), model.NewPmsProductFullReductionModel(conn),
+		model.NewPmsProductRevisionModel(conn))
+	importer := catalog.NewImporter(conn, productModel, skuStockModel, model.NewPmsBrandModel(conn), revisions,
+		audit.ActorSystem)
+
+	report, err := importer.Import(in, *dryRun)
+	logx.Must(err)
+
+	for _, e := range report.Errors {
+		fmt.Println(e.Error())
+	}
+	if report.DryRun {
+		fmt.Printf("%d products, %d skus are valid, %d errors, nothing imported\n",
+			report.Products, report.Skus, len(report.Errors))
+	} else {
+		fmt.Printf("%d products, %d skus imported, %d errors\n", report.Products, report.Skus, len(report.Errors))
+	}
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
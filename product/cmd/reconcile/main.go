@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"malltmp/product/model"
+	"malltmp/product/stock"
+
+	"github.com/tal-tech/go-zero/core/conf"
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+var (
+	configFile = flag.String("f", "etc/product-api.yaml", "the config file")
+	dryRun     = flag.Bool("dry-run", false, "only report the differences")
+)
+
+type Config struct {
+	Mysql struct {
+		DataSource string
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	var c Config
+	conf.MustLoad(*configFile, &c)
+
+	conn := sqlx.NewMysql(c.Mysql.DataSource)
+	reconciler := stock.NewReconciler(model.NewPmsProductModel(conn))
+	diffs, err := reconciler.Reconcile(*dryRun)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PRODUCT\tSTOCK\tSKU STOCK\tSALE\tSKU SALE")
+	for _, diff := range diffs {
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\n", diff.ProductId, diff.Stock, diff.SkuStock, diff.Sale, diff.SkuSale)
+	}
+	w.Flush()
+
+	if *dryRun {
+		fmt.Printf("%d products differ from their SKUs, nothing changed\n", len(diffs))
+	} else {
+		fmt.Printf("%d products rolled up from their SKUs\n", len(diffs))
+	}
+	if err != nil {
+		logx.Error(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"malltmp/product/auth"
+
+	"github.com/tal-tech/go-zero/core/conf"
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+var (
+	configFile = flag.String("f", "etc/product-api.yaml", "the config file")
+	operator   = flag.String("operator", "", "who the token is for, recorded in the audit log")
+	role       = flag.String("role", string(auth.RoleViewer), "viewer, editor, auditor, admin or order")
+	expire     = flag.Duration("expire", 0, "how long the token lasts, Auth.AccessExpire by default")
+)
+
+type Config struct {
+	Auth struct {
+		AccessSecret string
+		AccessExpire int64 `json:",default=86400"`
+	}
+}
+
+func main() {
+	flag.Parse()
+	if len(*operator) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var c Config
+	conf.MustLoad(*configFile, &c)
+
+	r, err := auth.ParseRole(*role)
+	logx.Must(err)
+	if *expire <= 0 {
+		*expire = time.Duration(c.Auth.AccessExpire) * time.Second
+	}
+
+	token, err := auth.NewToken(c.Auth.AccessSecret, *operator, r, *expire)
+	logx.Must(err)
+	fmt.Println(token)
+}
@@ -0,0 +1,101 @@
+// Package event tells the rest of the process which products changed, so
+// what was built from their rows can be dropped. Changes are seen through
+// the models wrapped here and through transactions of the conn of NewConn;
+// other processes writing the same database aren't heard of.
+package event
+
+import (
+	"sync"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+type (
+	// ProductHandler is called with the ID of a product whose rows changed.
+	ProductHandler func(productId int64)
+
+	// Bus dispatches product changes to its subscribers, synchronously on
+	// the goroutine of the write, so handlers must be quick.
+	Bus struct {
+		lock     sync.RWMutex
+		handlers []ProductHandler
+	}
+
+	// txConn holds back the changes of a transaction until it commits.
+	txConn struct {
+		sqlx.SqlConn
+		bus *Bus
+	}
+
+	// txSession collects the products changed within a transaction.
+	txSession struct {
+		sqlx.Session
+		productIds []int64
+	}
+)
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe has handler called on every product change from now on.
+func (b *Bus) Subscribe(handler ProductHandler) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// ProductChanged tells the subscribers that the rows of the products changed,
+// each product once.
+func (b *Bus) ProductChanged(productIds ...int64) {
+	b.lock.RLock()
+	handlers := b.handlers
+	b.lock.RUnlock()
+
+	seen := make(map[int64]struct{}, len(productIds))
+	for _, id := range productIds {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		for _, handle := range handlers {
+			handle(id)
+		}
+	}
+}
+
+// NewConn wraps conn so that the products changed in its transactions are
+// told once the transaction commits, and not at all if it rolls back. Told
+// earlier, a reader could cache the rows not yet committed over.
+func NewConn(conn sqlx.SqlConn, bus *Bus) sqlx.SqlConn {
+	return &txConn{
+		SqlConn: conn,
+		bus:     bus,
+	}
+}
+
+func (c *txConn) Transact(fn func(session sqlx.Session) error) error {
+	tx := new(txSession)
+	err := c.SqlConn.Transact(func(session sqlx.Session) error {
+		tx.Session = session
+		return fn(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.bus.ProductChanged(tx.productIds...)
+	return nil
+}
+
+// changed tells bus about the products, right away unless session is a
+// transaction of a conn from NewConn, which tells them on commit.
+func (b *Bus) changed(session sqlx.Session, productIds ...int64) {
+	if tx, ok := session.(*txSession); ok {
+		tx.productIds = append(tx.productIds, productIds...)
+		return
+	}
+
+	b.ProductChanged(productIds...)
+}
@@ -0,0 +1,580 @@
+package event
+
+import (
+	"database/sql"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+type (
+	productModel struct {
+		model.PmsProductModel
+		bus *Bus
+	}
+
+	skuStockModel struct {
+		model.PmsSkuStockModel
+		bus *Bus
+	}
+
+	attributeValueModel struct {
+		model.PmsProductAttributeValueModel
+		bus *Bus
+	}
+
+	ladderModel struct {
+		model.PmsProductLadderModel
+		bus *Bus
+	}
+
+	fullReductionModel struct {
+		model.PmsProductFullReductionModel
+		bus *Bus
+	}
+)
+
+// NewProductModel wraps m to tell bus of the products it writes.
+func NewProductModel(m model.PmsProductModel, bus *Bus) model.PmsProductModel {
+	return &productModel{
+		PmsProductModel: m,
+		bus:             bus,
+	}
+}
+
+func (m *productModel) Insert(data model.PmsProduct) (sql.Result, error) {
+	ret, err := m.PmsProductModel.Insert(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := ret.LastInsertId(); err == nil {
+		m.bus.ProductChanged(id)
+	}
+	return ret, nil
+}
+
+func (m *productModel) TxInsert(session sqlx.Session, data model.PmsProduct) (sql.Result, error) {
+	ret, err := m.PmsProductModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := ret.LastInsertId(); err == nil {
+		m.bus.changed(session, id)
+	}
+	return ret, nil
+}
+
+func (m *productModel) RollupSkus(session sqlx.Session, id int64) error {
+	if err := m.PmsProductModel.RollupSkus(session, id); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, id)
+	return nil
+}
+
+func (m *productModel) TxUpdateStatus(session sqlx.Session, id, verifyStatus, publishStatus, previewStatus int64) error {
+	if err := m.PmsProductModel.TxUpdateStatus(session, id, verifyStatus, publishStatus, previewStatus); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, id)
+	return nil
+}
+
+func (m *productModel) SetPromotionWindow(session sqlx.Session, id, promotionType int64, start, end time.Time) error {
+	if err := m.PmsProductModel.SetPromotionWindow(session, id, promotionType, start, end); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, id)
+	return nil
+}
+
+func (m *productModel) ClearPromotionWindow(session sqlx.Session, id int64) error {
+	if err := m.PmsProductModel.ClearPromotionWindow(session, id); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, id)
+	return nil
+}
+
+func (m *productModel) StartPromotionWindow(session sqlx.Session, id int64) error {
+	if err := m.PmsProductModel.StartPromotionWindow(session, id); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, id)
+	return nil
+}
+
+func (m *productModel) EndPromotionWindow(session sqlx.Session, id int64) error {
+	if err := m.PmsProductModel.EndPromotionWindow(session, id); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, id)
+	return nil
+}
+
+func (m *productModel) TxUpdate(session sqlx.Session, data model.PmsProduct) error {
+	if err := m.PmsProductModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, data.Id)
+	return nil
+}
+
+func (m *productModel) Update(data model.PmsProduct) error {
+	if err := m.PmsProductModel.Update(data); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(data.Id)
+	return nil
+}
+
+func (m *productModel) TxDelete(session sqlx.Session, id int64) error {
+	if err := m.PmsProductModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, id)
+	return nil
+}
+
+func (m *productModel) Delete(id int64) error {
+	if err := m.PmsProductModel.Delete(id); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(id)
+	return nil
+}
+
+// NewSkuStockModel wraps m to tell bus of the products whose SKUs it writes,
+// stock moves included.
+func NewSkuStockModel(m model.PmsSkuStockModel, bus *Bus) model.PmsSkuStockModel {
+	return &skuStockModel{
+		PmsSkuStockModel: m,
+		bus:              bus,
+	}
+}
+
+func (m *skuStockModel) Insert(data model.PmsSkuStock) (sql.Result, error) {
+	ret, err := m.PmsSkuStockModel.Insert(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m.bus.ProductChanged(productIds(data.ProductId)...)
+	return ret, nil
+}
+
+func (m *skuStockModel) LockStock(session sqlx.Session, id, quantity int64) error {
+	return m.moved(session, id, m.PmsSkuStockModel.LockStock(session, id, quantity))
+}
+
+func (m *skuStockModel) UnlockStock(session sqlx.Session, id, quantity int64) error {
+	return m.moved(session, id, m.PmsSkuStockModel.UnlockStock(session, id, quantity))
+}
+
+func (m *skuStockModel) DeductStock(session sqlx.Session, id, quantity int64) error {
+	return m.moved(session, id, m.PmsSkuStockModel.DeductStock(session, id, quantity))
+}
+
+func (m *skuStockModel) AddStock(session sqlx.Session, id, quantity int64) error {
+	return m.moved(session, id, m.PmsSkuStockModel.AddStock(session, id, quantity))
+}
+
+func (m *skuStockModel) SetStock(session sqlx.Session, id, stock, lockStock int64) error {
+	return m.moved(session, id, m.PmsSkuStockModel.SetStock(session, id, stock, lockStock))
+}
+
+func (m *skuStockModel) TxInsert(session sqlx.Session, data model.PmsSkuStock) (sql.Result, error) {
+	ret, err := m.PmsSkuStockModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	m.bus.changed(session, productIds(data.ProductId)...)
+	return ret, nil
+}
+
+func (m *skuStockModel) TxUpdate(session sqlx.Session, data model.PmsSkuStock) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *skuStockModel) TxUpdateInfo(session sqlx.Session, data model.PmsSkuStock) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.TxUpdateInfo(session, data); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *skuStockModel) UpdateInfo(data model.PmsSkuStock) error {
+	old, err := m.PmsSkuStockModel.FindOne(data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.UpdateInfo(data); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *skuStockModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(old.ProductId)...)
+	return nil
+}
+
+func (m *skuStockModel) Update(data model.PmsSkuStock) error {
+	old, err := m.PmsSkuStockModel.FindOne(data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.Update(data); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *skuStockModel) Delete(id int64) error {
+	old, err := m.PmsSkuStockModel.FindOne(id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.Delete(id); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(productIds(old.ProductId)...)
+	return nil
+}
+
+// moved tells bus of the product of SKU id after its stock moved without err.
+func (m *skuStockModel) moved(session sqlx.Session, id int64, err error) error {
+	if err != nil {
+		return err
+	}
+
+	sku, err := m.PmsSkuStockModel.FindOne(id)
+	if err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(sku.ProductId)...)
+	return nil
+}
+
+// NewAttributeValueModel wraps m to tell bus of the products whose attribute values it writes.
+func NewAttributeValueModel(m model.PmsProductAttributeValueModel, bus *Bus) model.PmsProductAttributeValueModel {
+	return &attributeValueModel{
+		PmsProductAttributeValueModel: m,
+		bus:                           bus,
+	}
+}
+
+func (m *attributeValueModel) Insert(data model.PmsProductAttributeValue) (sql.Result, error) {
+	ret, err := m.PmsProductAttributeValueModel.Insert(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m.bus.ProductChanged(productIds(data.ProductId)...)
+	return ret, nil
+}
+
+func (m *attributeValueModel) TxInsert(session sqlx.Session, data model.PmsProductAttributeValue) (sql.Result, error) {
+	ret, err := m.PmsProductAttributeValueModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	m.bus.changed(session, productIds(data.ProductId)...)
+	return ret, nil
+}
+
+func (m *attributeValueModel) TxDeleteByProductId(session sqlx.Session, productId int64) error {
+	if err := m.PmsProductAttributeValueModel.TxDeleteByProductId(session, productId); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productId)
+	return nil
+}
+
+func (m *attributeValueModel) TxUpdate(session sqlx.Session, data model.PmsProductAttributeValue) error {
+	old, err := m.PmsProductAttributeValueModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductAttributeValueModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *attributeValueModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsProductAttributeValueModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductAttributeValueModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(old.ProductId)...)
+	return nil
+}
+
+func (m *attributeValueModel) Update(data model.PmsProductAttributeValue) error {
+	old, err := m.PmsProductAttributeValueModel.FindOne(data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductAttributeValueModel.Update(data); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *attributeValueModel) Delete(id int64) error {
+	old, err := m.PmsProductAttributeValueModel.FindOne(id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductAttributeValueModel.Delete(id); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(productIds(old.ProductId)...)
+	return nil
+}
+
+// NewLadderModel wraps m to tell bus of the products whose ladders it writes.
+func NewLadderModel(m model.PmsProductLadderModel, bus *Bus) model.PmsProductLadderModel {
+	return &ladderModel{
+		PmsProductLadderModel: m,
+		bus:                   bus,
+	}
+}
+
+func (m *ladderModel) Insert(data model.PmsProductLadder) (sql.Result, error) {
+	ret, err := m.PmsProductLadderModel.Insert(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m.bus.ProductChanged(productIds(data.ProductId)...)
+	return ret, nil
+}
+
+func (m *ladderModel) TxInsert(session sqlx.Session, data model.PmsProductLadder) (sql.Result, error) {
+	ret, err := m.PmsProductLadderModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	m.bus.changed(session, productIds(data.ProductId)...)
+	return ret, nil
+}
+
+func (m *ladderModel) TxDeleteByProductId(session sqlx.Session, productId int64) error {
+	if err := m.PmsProductLadderModel.TxDeleteByProductId(session, productId); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productId)
+	return nil
+}
+
+func (m *ladderModel) TxUpdate(session sqlx.Session, data model.PmsProductLadder) error {
+	old, err := m.PmsProductLadderModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductLadderModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *ladderModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsProductLadderModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductLadderModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(old.ProductId)...)
+	return nil
+}
+
+func (m *ladderModel) Update(data model.PmsProductLadder) error {
+	old, err := m.PmsProductLadderModel.FindOne(data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductLadderModel.Update(data); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *ladderModel) Delete(id int64) error {
+	old, err := m.PmsProductLadderModel.FindOne(id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductLadderModel.Delete(id); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(productIds(old.ProductId)...)
+	return nil
+}
+
+// NewFullReductionModel wraps m to tell bus of the products whose full reductions it writes.
+func NewFullReductionModel(m model.PmsProductFullReductionModel, bus *Bus) model.PmsProductFullReductionModel {
+	return &fullReductionModel{
+		PmsProductFullReductionModel: m,
+		bus:                          bus,
+	}
+}
+
+func (m *fullReductionModel) Insert(data model.PmsProductFullReduction) (sql.Result, error) {
+	ret, err := m.PmsProductFullReductionModel.Insert(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m.bus.ProductChanged(productIds(data.ProductId)...)
+	return ret, nil
+}
+
+func (m *fullReductionModel) TxInsert(session sqlx.Session, data model.PmsProductFullReduction) (sql.Result, error) {
+	ret, err := m.PmsProductFullReductionModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	m.bus.changed(session, productIds(data.ProductId)...)
+	return ret, nil
+}
+
+func (m *fullReductionModel) TxDeleteByProductId(session sqlx.Session, productId int64) error {
+	if err := m.PmsProductFullReductionModel.TxDeleteByProductId(session, productId); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productId)
+	return nil
+}
+
+func (m *fullReductionModel) TxUpdate(session sqlx.Session, data model.PmsProductFullReduction) error {
+	old, err := m.PmsProductFullReductionModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductFullReductionModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *fullReductionModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsProductFullReductionModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductFullReductionModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	m.bus.changed(session, productIds(old.ProductId)...)
+	return nil
+}
+
+func (m *fullReductionModel) Update(data model.PmsProductFullReduction) error {
+	old, err := m.PmsProductFullReductionModel.FindOne(data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductFullReductionModel.Update(data); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(productIds(old.ProductId, data.ProductId)...)
+	return nil
+}
+
+func (m *fullReductionModel) Delete(id int64) error {
+	old, err := m.PmsProductFullReductionModel.FindOne(id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsProductFullReductionModel.Delete(id); err != nil {
+		return err
+	}
+
+	m.bus.ProductChanged(productIds(old.ProductId)...)
+	return nil
+}
+
+// productIds returns the valid IDs of ids, rows may belong to no product.
+func productIds(ids ...sql.NullInt64) []int64 {
+	var resp []int64
+	for _, id := range ids {
+		if id.Valid {
+			resp = append(resp, id.Int64)
+		}
+	}
+
+	return resp
+}
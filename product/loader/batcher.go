@@ -0,0 +1,111 @@
+// Package loader coalesces the lookups of products and SKUs by ID: identical
+// lookups in flight share one, and distinct ones arriving within a short
+// window are found with one query.
+package loader
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/syncx"
+)
+
+type (
+	// batcher finds the IDs asked for within wait of the first one, up to
+	// maxBatch of them, with one call of find.
+	batcher struct {
+		find     func(ids []int64) (map[int64]interface{}, error)
+		wait     time.Duration
+		maxBatch int
+		flight   syncx.SharedCalls
+
+		lock    sync.Mutex
+		pending *batch
+	}
+
+	batch struct {
+		ids  []int64
+		done chan struct{}
+		rows map[int64]interface{}
+		err  error
+	}
+)
+
+func newBatcher(wait time.Duration, maxBatch int, find func(ids []int64) (map[int64]interface{}, error)) *batcher {
+	if maxBatch <= 0 {
+		maxBatch = 1
+	}
+
+	return &batcher{
+		find:     find,
+		wait:     wait,
+		maxBatch: maxBatch,
+		flight:   syncx.NewSharedCalls(),
+	}
+}
+
+// load returns the row of id, shared with the concurrent loads of id, or
+// model.ErrNotFound.
+func (b *batcher) load(id int64) (interface{}, error) {
+	return b.flight.Do(strconv.FormatInt(id, 10), func() (interface{}, error) {
+		bt := b.add(id)
+		<-bt.done
+		if bt.err != nil {
+			return nil, bt.err
+		}
+
+		row, ok := bt.rows[id]
+		if !ok {
+			return nil, model.ErrNotFound
+		}
+		return row, nil
+	})
+}
+
+// add puts id in the pending batch, starting one if there is none, and runs
+// the batch once full.
+func (b *batcher) add(id int64) *batch {
+	b.lock.Lock()
+	bt := b.pending
+	if bt == nil {
+		bt = &batch{done: make(chan struct{})}
+		b.pending = bt
+		if b.maxBatch > 1 {
+			time.AfterFunc(b.wait, func() {
+				b.flush(bt)
+			})
+		}
+	}
+	bt.ids = append(bt.ids, id)
+	full := len(bt.ids) >= b.maxBatch
+	if full {
+		b.pending = nil
+	}
+	b.lock.Unlock()
+
+	if full {
+		b.run(bt)
+	}
+	return bt
+}
+
+// flush runs bt when its wait is over, unless it ran full already.
+func (b *batcher) flush(bt *batch) {
+	b.lock.Lock()
+	if b.pending != bt {
+		b.lock.Unlock()
+		return
+	}
+	b.pending = nil
+	b.lock.Unlock()
+
+	b.run(bt)
+}
+
+func (b *batcher) run(bt *batch) {
+	bt.rows, bt.err = b.find(bt.ids)
+	close(bt.done)
+}
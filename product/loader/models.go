@@ -0,0 +1,98 @@
+package loader
+
+import (
+	"time"
+
+	"malltmp/product/model"
+)
+
+type (
+	productModel struct {
+		model.PmsProductModel
+		batcher *batcher
+	}
+
+	skuStockModel struct {
+		model.PmsSkuStockModel
+		batcher *batcher
+	}
+)
+
+// NewProductModel wraps m to coalesce its FindOne calls, batching those
+// within wait, up to maxBatch, into one FindMany. Rows found may be as old as
+// the lookup they joined; read for writes from m itself.
+func NewProductModel(m model.PmsProductModel, wait time.Duration, maxBatch int) model.PmsProductModel {
+	return &productModel{
+		PmsProductModel: m,
+		batcher: newBatcher(wait, maxBatch, func(ids []int64) (map[int64]interface{}, error) {
+			products, _, err := m.FindMany(ids)
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make(map[int64]interface{}, len(products))
+			for _, product := range products {
+				rows[product.Id] = product
+			}
+			return rows, nil
+		}),
+	}
+}
+
+func (m *productModel) FindOne(id int64) (*model.PmsProduct, error) {
+	row, err := m.batcher.load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the row is shared by the lookups coalesced, each gets its copy
+	product := *row.(*model.PmsProduct)
+	return &product, nil
+}
+
+// FindOneByProductSn resolves productSn to its product ID, loaded like FindOne.
+func (m *productModel) FindOneByProductSn(productSn string) (*model.PmsProduct, error) {
+	id, err := m.FindIdByProductSn(productSn)
+	if err != nil {
+		return nil, err
+	}
+	return m.FindOne(id)
+}
+
+// NewSkuStockModel wraps m to coalesce its FindOne calls like NewProductModel.
+func NewSkuStockModel(m model.PmsSkuStockModel, wait time.Duration, maxBatch int) model.PmsSkuStockModel {
+	return &skuStockModel{
+		PmsSkuStockModel: m,
+		batcher: newBatcher(wait, maxBatch, func(ids []int64) (map[int64]interface{}, error) {
+			skus, _, err := m.FindMany(ids)
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make(map[int64]interface{}, len(skus))
+			for _, sku := range skus {
+				rows[sku.Id] = sku
+			}
+			return rows, nil
+		}),
+	}
+}
+
+func (m *skuStockModel) FindOne(id int64) (*model.PmsSkuStock, error) {
+	row, err := m.batcher.load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	sku := *row.(*model.PmsSkuStock)
+	return &sku, nil
+}
+
+// FindOneBySkuCode resolves skuCode to its SKU ID, loaded like FindOne.
+func (m *skuStockModel) FindOneBySkuCode(skuCode string) (*model.PmsSkuStock, error) {
+	id, err := m.FindIdBySkuCode(skuCode)
+	if err != nil {
+		return nil, err
+	}
+	return m.FindOne(id)
+}
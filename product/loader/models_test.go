@@ -0,0 +1,142 @@
+package loader
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"malltmp/product/model"
+)
+
+type (
+	fakeProductModel struct {
+		model.PmsProductModel
+		lock    sync.Mutex
+		rows    map[int64]model.PmsProduct
+		batches [][]int64
+	}
+
+	fakeSkuStockModel struct {
+		model.PmsSkuStockModel
+		rows    map[int64]model.PmsSkuStock
+		batches [][]int64
+	}
+)
+
+func (m *fakeProductModel) FindMany(ids []int64) ([]*model.PmsProduct, []int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.batches = append(m.batches, ids)
+
+	var found []*model.PmsProduct
+	var missing []int64
+	for _, id := range ids {
+		if row, ok := m.rows[id]; ok {
+			found = append(found, &row)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return found, missing, nil
+}
+
+func (m *fakeProductModel) FindIdByProductSn(productSn string) (int64, error) {
+	for id, row := range m.rows {
+		if row.ProductSn == productSn {
+			return id, nil
+		}
+	}
+	return 0, model.ErrNotFound
+}
+
+func (m *fakeSkuStockModel) FindMany(ids []int64) ([]*model.PmsSkuStock, []int64, error) {
+	m.batches = append(m.batches, ids)
+
+	var found []*model.PmsSkuStock
+	var missing []int64
+	for _, id := range ids {
+		if row, ok := m.rows[id]; ok {
+			found = append(found, &row)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return found, missing, nil
+}
+
+func (m *fakeSkuStockModel) FindIdBySkuCode(skuCode string) (int64, error) {
+	for id, row := range m.rows {
+		if row.SkuCode == skuCode {
+			return id, nil
+		}
+	}
+	return 0, model.ErrNotFound
+}
+
+func TestProductModelBatches(t *testing.T) {
+	products := &fakeProductModel{rows: map[int64]model.PmsProduct{
+		1: {Id: 1, ProductSn: "P1"},
+		2: {Id: 2, ProductSn: "P2"},
+	}}
+	m := NewProductModel(products, 20*time.Millisecond, 10)
+
+	var wg sync.WaitGroup
+	got := make([]*model.PmsProduct, 3)
+	errs := make([]error, 3)
+	for i, id := range []int64{1, 2, 3} {
+		wg.Add(1)
+		go func(i int, id int64) {
+			defer wg.Done()
+			got[i], errs[i] = m.FindOne(id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	if len(products.batches) != 1 || len(products.batches[0]) != 3 {
+		t.Errorf("batches = %v, want one of 3", products.batches)
+	}
+	if errs[0] != nil || got[0].Id != 1 || errs[1] != nil || got[1].Id != 2 {
+		t.Errorf("found %v, %v, errors %v", got[0], got[1], errs)
+	}
+	if errs[2] != model.ErrNotFound {
+		t.Errorf("missing product: error = %v", errs[2])
+	}
+}
+
+func TestFindOneByProductSn(t *testing.T) {
+	products := &fakeProductModel{rows: map[int64]model.PmsProduct{1: {Id: 1, ProductSn: "P1"}}}
+	m := NewProductModel(products, time.Millisecond, 10)
+
+	product, err := m.FindOneByProductSn("P1")
+	if err != nil || product.Id != 1 {
+		t.Fatalf("product = %v, error %v", product, err)
+	}
+	// loaded by ID, not read past the loader
+	if len(products.batches) != 1 || products.batches[0][0] != 1 {
+		t.Errorf("batches = %v, want [[1]]", products.batches)
+	}
+
+	if _, err := m.FindOneByProductSn("P2"); err != model.ErrNotFound {
+		t.Errorf("missing product_sn: error = %v", err)
+	}
+	if len(products.batches) != 1 {
+		t.Errorf("missing product_sn loaded %v", products.batches)
+	}
+}
+
+func TestFindOneBySkuCode(t *testing.T) {
+	skus := &fakeSkuStockModel{rows: map[int64]model.PmsSkuStock{4: {Id: 4, SkuCode: "S4"}}}
+	m := NewSkuStockModel(skus, time.Millisecond, 10)
+
+	sku, err := m.FindOneBySkuCode("S4")
+	if err != nil || sku.Id != 4 {
+		t.Fatalf("sku = %v, error %v", sku, err)
+	}
+	if len(skus.batches) != 1 || skus.batches[0][0] != 4 {
+		t.Errorf("batches = %v, want [[4]]", skus.batches)
+	}
+
+	if _, err := m.FindOneBySkuCode("S5"); err != model.ErrNotFound {
+		t.Errorf("missing sku_code: error = %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// unique keys of the tables, the Key of a DuplicateError
+const (
+	KeyProductSn = "uk_product_sn"
+	KeySkuCode   = "uk_sku_code"
+)
+
+// mysqlDuplicateEntry is the MySQL error number of a write violating a unique key.
+const mysqlDuplicateEntry = 1062
+
+// ErrDuplicate is what a DuplicateError is, for errors.Is.
+var ErrDuplicate = errors.New("duplicate entry")
+
+var duplicateEntryRe = regexp.MustCompile(`^Duplicate entry '(.*)' for key '(?:[^'.]*\.)?([^']*)'$`)
+
+// DuplicateError is a write rejected for repeating the value Entry of the
+// unique key Key, uk_product_sn say.
+type DuplicateError struct {
+	Key   string
+	Entry string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate entry %q for key %s", e.Entry, e.Key)
+}
+
+func (e *DuplicateError) Is(target error) bool {
+	return target == ErrDuplicate
+}
+
+// checkDuplicate turns the MySQL error of a write violating a unique key into
+// a *DuplicateError, returning other errors as they are.
+func checkDuplicate(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlDuplicateEntry {
+		return err
+	}
+
+	dup := new(DuplicateError)
+	if match := duplicateEntryRe.FindStringSubmatch(mysqlErr.Message); match != nil {
+		dup.Entry, dup.Key = match[1], match[2]
+	}
+	return dup
+}
@@ -0,0 +1,299 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const moneyScale = 100
+
+var ErrInvalidMoney = errors.New("invalid money value")
+
+type (
+	// Money is a fixed-point amount with two decimal places, matching the
+	// `decimal(10,2)` columns. The underlying value is in cents (分).
+	Money int64
+
+	// NullMoney is the nullable form of Money, like sql.NullFloat64.
+	NullMoney struct {
+		Money Money
+		Valid bool // Valid is true if Money is not NULL
+	}
+)
+
+// NewMoney builds a Money from a yuan part and a cents part, e.g. NewMoney(26, 99) is 26.99.
+func NewMoney(yuan, cents int64) Money {
+	if yuan < 0 {
+		return Money(yuan*moneyScale - cents)
+	}
+	return Money(yuan*moneyScale + cents)
+}
+
+// ParseMoney parses a decimal string such as "2699.00" or "-0.5".
+// Digits past the second decimal place are rounded half away from zero.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return 0, ErrInvalidMoney
+	}
+
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if len(intPart) == 0 && len(fracPart) == 0 {
+		return 0, ErrInvalidMoney
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, ErrInvalidMoney
+	}
+
+	var units int64
+	if len(intPart) > 0 {
+		v, err := strconv.ParseInt(intPart, 10, 64)
+		if err != nil || v > math.MaxInt64/moneyScale-1 {
+			return 0, ErrInvalidMoney
+		}
+		units = v * moneyScale
+	}
+
+	var cents int64
+	for i := 0; i < 2; i++ {
+		cents *= 10
+		if i < len(fracPart) {
+			cents += int64(fracPart[i] - '0')
+		}
+	}
+	if len(fracPart) > 2 && fracPart[2] >= '5' {
+		cents++
+	}
+	units += cents
+
+	if neg {
+		units = -units
+	}
+	return Money(units), nil
+}
+
+// MustParseMoney is like ParseMoney but panics on error, for constants.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(fmt.Sprintf("model: invalid money %q", s))
+	}
+	return m
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Cents returns the amount in cents.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 returns the amount as a float, only for display or metrics.
+func (m Money) Float64() float64 {
+	return float64(m) / moneyScale
+}
+
+func (m Money) Add(o Money) Money {
+	return m + o
+}
+
+func (m Money) Sub(o Money) Money {
+	return m - o
+}
+
+// Mul multiplies the amount by an integer quantity.
+func (m Money) Mul(n int64) Money {
+	return m * Money(n)
+}
+
+// Discount applies a rate such as 0.80 (八折) stored as Money, rounding half away from zero.
+func (m Money) Discount(rate Money) Money {
+	return Money(divRound(int64(m)*int64(rate), moneyScale))
+}
+
+// Reduce subtracts a reduction from the amount, never going below zero.
+func (m Money) Reduce(reduction Money) Money {
+	if reduction >= m {
+		return 0
+	}
+	return m - reduction
+}
+
+// Div divides the amount by n, rounding half away from zero.
+func (m Money) Div(n int64) Money {
+	return Money(divRound(int64(m), n))
+}
+
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m < o:
+		return -1
+	case m > o:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) IsZero() bool {
+	return m == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m < 0
+}
+
+func (m Money) String() string {
+	v := int64(m)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/moneyScale, v%moneyScale)
+}
+
+// Scan implements the sql.Scanner interface.
+func (m *Money) Scan(value interface{}) error {
+	var nm NullMoney
+	if err := nm.Scan(value); err != nil {
+		return err
+	}
+	if !nm.Valid {
+		return fmt.Errorf("model: cannot scan NULL into Money")
+	}
+
+	*m = nm.Money
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+	return nil
+}
+
+// NewNullMoney returns a valid NullMoney.
+func NewNullMoney(m Money) NullMoney {
+	return NullMoney{Money: m, Valid: true}
+}
+
+// Or returns the amount, or def when it is NULL.
+func (n NullMoney) Or(def Money) Money {
+	if !n.Valid {
+		return def
+	}
+	return n.Money
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullMoney) Scan(value interface{}) error {
+	var (
+		m   Money
+		err error
+	)
+	switch v := value.(type) {
+	case nil:
+		n.Money, n.Valid = 0, false
+		return nil
+	case []byte:
+		m, err = ParseMoney(string(v))
+	case string:
+		m, err = ParseMoney(v)
+	case int64:
+		m = Money(v * moneyScale)
+	case float64:
+		m = Money(math.Round(v * moneyScale))
+	default:
+		return fmt.Errorf("model: cannot scan %T into Money", value)
+	}
+	if err != nil {
+		return err
+	}
+
+	n.Money, n.Valid = m, true
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullMoney) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Money.Value()
+}
+
+func (n NullMoney) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Money.MarshalJSON()
+}
+
+func (n *NullMoney) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.Money, n.Valid = 0, false
+		return nil
+	}
+	if err := n.Money.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+func divRound(a, b int64) int64 {
+	if b < 0 {
+		a, b = -a, -b
+	}
+	q, r := a/b, a%b
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= b {
+		if a < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
@@ -0,0 +1,229 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"2699.00", 269900},
+		{"2699", 269900},
+		{"0.5", 50},
+		{".5", 50},
+		{"-0.5", -50},
+		{"+1.2", 120},
+		{" 3.14 ", 314},
+		{"0.994", 99},
+		{"0.995", 100},
+		{"9.995", 1000},
+		{"-0.995", -100},
+		{"0.005", 1},
+		{"-0.005", -1},
+		{"0.0049", 0},
+		{"1.", 100},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if err != nil {
+			t.Errorf("ParseMoney(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoneyInvalid(t *testing.T) {
+	for _, in := range []string{"", " ", "-", "+", ".", "-.", "1.2.3", "abc", "1e5", "1,00", "--1",
+		"99999999999999999999"} {
+		if _, err := ParseMoney(in); err != ErrInvalidMoney {
+			t.Errorf("ParseMoney(%q) error = %v, want ErrInvalidMoney", in, err)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{-5, "-0.05"},
+		{269900, "2699.00"},
+		{NewMoney(26, 99), "26.99"},
+		{NewMoney(-26, 99), "-26.99"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyDiscount(t *testing.T) {
+	tests := []struct {
+		amount, rate string
+		want         string
+	}{
+		{"100.00", "0.80", "80.00"},
+		{"1.99", "0.85", "1.69"},
+		{"0.10", "0.85", "0.09"},
+		{"0.10", "0.25", "0.03"},
+		{"-0.10", "0.85", "-0.09"},
+		{"-0.10", "0.25", "-0.03"},
+		{"0.01", "0.50", "0.01"},
+		{"0.01", "0.49", "0.00"},
+	}
+	for _, tt := range tests {
+		got := MustParseMoney(tt.amount).Discount(MustParseMoney(tt.rate))
+		if got.String() != tt.want {
+			t.Errorf("%s.Discount(%s) = %s, want %s", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyDiv(t *testing.T) {
+	tests := []struct {
+		amount string
+		n      int64
+		want   string
+	}{
+		{"1.00", 3, "0.33"},
+		{"2.00", 3, "0.67"},
+		{"0.05", 2, "0.03"},
+		{"-0.05", 2, "-0.03"},
+		{"0.05", -2, "-0.03"},
+		{"-0.05", -2, "0.03"},
+		{"0.04", 3, "0.01"},
+	}
+	for _, tt := range tests {
+		got := MustParseMoney(tt.amount).Div(tt.n)
+		if got.String() != tt.want {
+			t.Errorf("%s.Div(%d) = %s, want %s", tt.amount, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyReduce(t *testing.T) {
+	m := MustParseMoney("10.00")
+	if got := m.Reduce(MustParseMoney("3.50")); got.String() != "6.50" {
+		t.Errorf("Reduce = %s, want 6.50", got)
+	}
+	if got := m.Reduce(MustParseMoney("12.00")); !got.IsZero() {
+		t.Errorf("Reduce past zero = %s, want 0.00", got)
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want Money
+	}{
+		{[]byte("12.30"), 1230},
+		{"12.3", 1230},
+		{int64(5), 500},
+		{0.1 + 0.2, 30},
+		{float64(19.99), 1999},
+	}
+	for _, tt := range tests {
+		var m Money
+		if err := m.Scan(tt.in); err != nil {
+			t.Errorf("Scan(%#v) error: %v", tt.in, err)
+			continue
+		}
+		if m != tt.want {
+			t.Errorf("Scan(%#v) = %d, want %d", tt.in, m, tt.want)
+		}
+	}
+
+	var m Money
+	if err := m.Scan(nil); err == nil {
+		t.Error("Scan(nil) into Money should fail")
+	}
+	if err := m.Scan(true); err == nil {
+		t.Error("Scan(bool) should fail")
+	}
+	if err := m.Scan("x"); err != ErrInvalidMoney {
+		t.Errorf("Scan(%q) error = %v, want ErrInvalidMoney", "x", err)
+	}
+}
+
+func TestMoneyValueRoundTrip(t *testing.T) {
+	for _, m := range []Money{0, 1, -1, 1230, -269999, NewMoney(99999999, 99)} {
+		v, err := m.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back Money
+		if err := back.Scan(v); err != nil {
+			t.Fatalf("Scan(%v) error: %v", v, err)
+		}
+		if back != m {
+			t.Errorf("Value/Scan of %d = %d", m, back)
+		}
+	}
+}
+
+func TestNullMoneyScanValue(t *testing.T) {
+	var n NullMoney
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Fatalf("Scan(nil) = %+v, %v", n, err)
+	}
+	if v, err := n.Value(); v != nil || err != nil {
+		t.Errorf("Value of NULL = %v, %v", v, err)
+	}
+	if got := n.Or(MustParseMoney("1.00")); got.String() != "1.00" {
+		t.Errorf("Or = %s, want 1.00", got)
+	}
+
+	if err := n.Scan([]byte("8.80")); err != nil || !n.Valid || n.Money != 880 {
+		t.Fatalf("Scan(8.80) = %+v, %v", n, err)
+	}
+	v, err := n.Value()
+	if err != nil || v != "8.80" {
+		t.Errorf("Value = %v, %v, want 8.80", v, err)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	type row struct {
+		Price    Money     `json:"price"`
+		Discount NullMoney `json:"discount"`
+		Reduce   NullMoney `json:"reduce"`
+	}
+	in := row{
+		Price:    MustParseMoney("-12.05"),
+		Discount: NewNullMoney(MustParseMoney("0.80")),
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"price":-12.05,"discount":0.80,"reduce":null}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	var out row
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+
+	if err := json.Unmarshal([]byte(`{"price":"3.10","discount":"0.5"}`), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Price != 310 || !out.Discount.Valid || out.Discount.Money != 50 {
+		t.Errorf("quoted = %+v", out)
+	}
+	if err := json.Unmarshal([]byte(`{"price":"abc"}`), &out); err == nil {
+		t.Error("Unmarshal of an invalid price should fail")
+	}
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlc"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+	"github.com/tal-tech/go-zero/core/stringx"
+	"github.com/tal-tech/go-zero/tools/goctl/model/sql/builderx"
+)
+
+var (
+	pmsAuditLogFieldNames        = builderx.RawFieldNames(&PmsAuditLog{})
+	pmsAuditLogRows              = strings.Join(pmsAuditLogFieldNames, ",")
+	pmsAuditLogRowsExpectAutoSet = strings.Join(stringx.Remove(pmsAuditLogFieldNames, "`id`", "`create_time`", "`update_time`"), ",")
+)
+
+type (
+	// PmsAuditLogModel is append-only, logs are never updated or deleted.
+	PmsAuditLogModel interface {
+		Insert(data PmsAuditLog) (sql.Result, error)
+		FindOne(id int64) (*PmsAuditLog, error)
+		FindMany(ids []int64) ([]*PmsAuditLog, []int64, error)
+		FindByRow(tableName string, rowId, lastId int64, limit int) ([]*PmsAuditLog, error)
+		TxInsert(session sqlx.Session, data PmsAuditLog) (sql.Result, error)
+	}
+
+	defaultPmsAuditLogModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+
+	PmsAuditLog struct {
+		RowId      int64     `db:"row_id"`  // 行id
+		Action     string    `db:"action"`  // 操作：insert->新增；update->修改；delete->删除
+		Actor      string    `db:"actor"`   // 操作人
+		Changes    string    `db:"changes"` // 字段变更，json格式：[{"field":"price","before":"1.00","after":"2.00"}]
+		CreateTime time.Time `db:"create_time"`
+		Id         int64     `db:"id"`
+		TableName  string    `db:"table_name"` // 表名
+	}
+)
+
+func NewPmsAuditLogModel(conn sqlx.SqlConn) PmsAuditLogModel {
+	return &defaultPmsAuditLogModel{
+		conn:  conn,
+		table: "`pms_audit_log`",
+	}
+}
+
+func (m *defaultPmsAuditLogModel) Insert(data PmsAuditLog) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?)", m.table, pmsAuditLogRowsExpectAutoSet)
+	ret, err := m.conn.Exec(query, data.RowId, data.Action, data.Actor, data.Changes, data.TableName)
+	return ret, err
+}
+
+func (m *defaultPmsAuditLogModel) FindOne(id int64) (*PmsAuditLog, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1", pmsAuditLogRows, m.table)
+	var resp PmsAuditLog
+	err := m.conn.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsAuditLogModel) FindMany(ids []int64) ([]*PmsAuditLog, []int64, error) {
+	byId := make(map[int64]*PmsAuditLog, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsAuditLogRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsAuditLog
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsAuditLog, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+// FindByRow pages through the logs of a row in the order they were recorded.
+func (m *defaultPmsAuditLogModel) FindByRow(tableName string, rowId, lastId int64, limit int) ([]*PmsAuditLog, error) {
+	query := fmt.Sprintf("select %s from %s where `table_name` = ? and `row_id` = ? and `id` > ? order by `id` limit ?",
+		pmsAuditLogRows, m.table)
+	var resp []*PmsAuditLog
+	err := m.conn.QueryRows(&resp, query, tableName, rowId, lastId, limit)
+	return resp, err
+}
+
+func (m *defaultPmsAuditLogModel) TxInsert(session sqlx.Session, data PmsAuditLog) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?)", m.table, pmsAuditLogRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.RowId, data.Action, data.Actor, data.Changes, data.TableName)
+	return ret, err
+}
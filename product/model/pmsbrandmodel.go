@@ -22,6 +22,13 @@ type (
 	PmsBrandModel interface {
 		Insert(data PmsBrand) (sql.Result, error)
 		FindOne(id int64) (*PmsBrand, error)
+		FindMany(ids []int64) ([]*PmsBrand, []int64, error)
+		FindOneByName(name string) (*PmsBrand, error)
+		FindAll(lastId int64, limit int) ([]*PmsBrand, error)
+		TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsBrand, error)
+		TxInsert(session sqlx.Session, data PmsBrand) (sql.Result, error)
+		TxUpdate(session sqlx.Session, data PmsBrand) error
+		TxDelete(session sqlx.Session, id int64) error
 		Update(data PmsBrand) error
 		Delete(id int64) error
 	}
@@ -73,6 +80,90 @@ func (m *defaultPmsBrandModel) FindOne(id int64) (*PmsBrand, error) {
 	}
 }
 
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsBrandModel) FindMany(ids []int64) ([]*PmsBrand, []int64, error) {
+	byId := make(map[int64]*PmsBrand, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsBrandRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsBrand
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsBrand, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+func (m *defaultPmsBrandModel) FindOneByName(name string) (*PmsBrand, error) {
+	query := fmt.Sprintf("select %s from %s where `name` = ? limit 1", pmsBrandRows, m.table)
+	var resp PmsBrand
+	err := m.conn.QueryRow(&resp, query, name)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindAll pages through the brands.
+func (m *defaultPmsBrandModel) FindAll(lastId int64, limit int) ([]*PmsBrand, error) {
+	query := fmt.Sprintf("select %s from %s where `id` > ? order by `id` limit ?", pmsBrandRows, m.table)
+	var resp []*PmsBrand
+	err := m.conn.QueryRows(&resp, query, lastId, limit)
+	return resp, err
+}
+
+func (m *defaultPmsBrandModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsBrand, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1 for update", pmsBrandRows, m.table)
+	var resp PmsBrand
+	err := session.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultPmsBrandModel) TxInsert(session sqlx.Session, data PmsBrand) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, pmsBrandRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.Sort, data.ShowStatus, data.ProductCount, data.Logo, data.BrandStory, data.Name, data.FirstLetter, data.ProductCommentCount, data.BigPic, data.FactoryStatus)
+	return ret, err
+}
+
+func (m *defaultPmsBrandModel) TxUpdate(session sqlx.Session, data PmsBrand) error {
+	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsBrandRowsWithPlaceHolder)
+	_, err := session.Exec(query, data.Sort, data.ShowStatus, data.ProductCount, data.Logo, data.BrandStory, data.Name, data.FirstLetter, data.ProductCommentCount, data.BigPic, data.FactoryStatus, data.Id)
+	return err
+}
+
+func (m *defaultPmsBrandModel) TxDelete(session sqlx.Session, id int64) error {
+	query := fmt.Sprintf("delete from %s where `id` = ?", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
 func (m *defaultPmsBrandModel) Update(data PmsBrand) error {
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsBrandRowsWithPlaceHolder)
 	_, err := m.conn.Exec(query, data.Sort, data.ShowStatus, data.ProductCount, data.Logo, data.BrandStory, data.Name, data.FirstLetter, data.ProductCommentCount, data.BigPic, data.FactoryStatus, data.Id)
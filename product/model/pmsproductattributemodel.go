@@ -22,6 +22,12 @@ type (
 	PmsProductAttributeModel interface {
 		Insert(data PmsProductAttribute) (sql.Result, error)
 		FindOne(id int64) (*PmsProductAttribute, error)
+		FindMany(ids []int64) ([]*PmsProductAttribute, []int64, error)
+		FindByCategoryId(categoryId, lastId int64, limit int) ([]*PmsProductAttribute, error)
+		TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductAttribute, error)
+		TxInsert(session sqlx.Session, data PmsProductAttribute) (sql.Result, error)
+		TxUpdate(session sqlx.Session, data PmsProductAttribute) error
+		TxDelete(session sqlx.Session, id int64) error
 		Update(data PmsProductAttribute) error
 		Delete(id int64) error
 	}
@@ -74,6 +80,78 @@ func (m *defaultPmsProductAttributeModel) FindOne(id int64) (*PmsProductAttribut
 	}
 }
 
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsProductAttributeModel) FindMany(ids []int64) ([]*PmsProductAttribute, []int64, error) {
+	byId := make(map[int64]*PmsProductAttribute, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsProductAttributeRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsProductAttribute
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsProductAttribute, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+// FindByCategoryId pages through the attributes of an attribute category.
+func (m *defaultPmsProductAttributeModel) FindByCategoryId(categoryId, lastId int64,
+	limit int) ([]*PmsProductAttribute, error) {
+	query := fmt.Sprintf("select %s from %s where `product_attribute_category_id` = ? and `id` > ? "+
+		"order by `id` limit ?", pmsProductAttributeRows, m.table)
+	var resp []*PmsProductAttribute
+	err := m.conn.QueryRows(&resp, query, categoryId, lastId, limit)
+	return resp, err
+}
+
+func (m *defaultPmsProductAttributeModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductAttribute, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1 for update", pmsProductAttributeRows, m.table)
+	var resp PmsProductAttribute
+	err := session.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultPmsProductAttributeModel) TxInsert(session sqlx.Session, data PmsProductAttribute) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, pmsProductAttributeRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.Name, data.SelectType, data.InputType, data.Sort, data.FilterType, data.SearchType, data.HandAddStatus, data.ProductAttributeCategoryId, data.InputList, data.RelatedStatus, data.Type)
+	return ret, err
+}
+
+func (m *defaultPmsProductAttributeModel) TxUpdate(session sqlx.Session, data PmsProductAttribute) error {
+	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductAttributeRowsWithPlaceHolder)
+	_, err := session.Exec(query, data.Name, data.SelectType, data.InputType, data.Sort, data.FilterType, data.SearchType, data.HandAddStatus, data.ProductAttributeCategoryId, data.InputList, data.RelatedStatus, data.Type, data.Id)
+	return err
+}
+
+func (m *defaultPmsProductAttributeModel) TxDelete(session sqlx.Session, id int64) error {
+	query := fmt.Sprintf("delete from %s where `id` = ?", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
 func (m *defaultPmsProductAttributeModel) Update(data PmsProductAttribute) error {
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductAttributeRowsWithPlaceHolder)
 	_, err := m.conn.Exec(query, data.Name, data.SelectType, data.InputType, data.Sort, data.FilterType, data.SearchType, data.HandAddStatus, data.ProductAttributeCategoryId, data.InputList, data.RelatedStatus, data.Type, data.Id)
@@ -22,6 +22,14 @@ type (
 	PmsProductAttributeValueModel interface {
 		Insert(data PmsProductAttributeValue) (sql.Result, error)
 		FindOne(id int64) (*PmsProductAttributeValue, error)
+		FindMany(ids []int64) ([]*PmsProductAttributeValue, []int64, error)
+		FindByProductIds(productIds []int64) ([]*PmsProductAttributeValue, error)
+		TxInsert(session sqlx.Session, data PmsProductAttributeValue) (sql.Result, error)
+		TxDeleteByProductId(session sqlx.Session, productId int64) error
+		TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductAttributeValue, error)
+		TxFindByProductId(session sqlx.Session, productId int64) ([]*PmsProductAttributeValue, error)
+		TxUpdate(session sqlx.Session, data PmsProductAttributeValue) error
+		TxDelete(session sqlx.Session, id int64) error
 		Update(data PmsProductAttributeValue) error
 		Delete(id int64) error
 	}
@@ -66,6 +74,94 @@ func (m *defaultPmsProductAttributeValueModel) FindOne(id int64) (*PmsProductAtt
 	}
 }
 
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsProductAttributeValueModel) FindMany(ids []int64) ([]*PmsProductAttributeValue, []int64, error) {
+	byId := make(map[int64]*PmsProductAttributeValue, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsProductAttributeValueRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsProductAttributeValue
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsProductAttributeValue, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+func (m *defaultPmsProductAttributeValueModel) FindByProductIds(productIds []int64) ([]*PmsProductAttributeValue, error) {
+	if len(productIds) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf("select %s from %s where `product_id` in (%s) order by `product_id`, `id`",
+		pmsProductAttributeValueRows, m.table, inPlaceholders(len(productIds)))
+	var resp []*PmsProductAttributeValue
+	err := m.conn.QueryRows(&resp, query, int64sToArgs(productIds)...)
+	return resp, err
+}
+
+func (m *defaultPmsProductAttributeValueModel) TxInsert(session sqlx.Session, data PmsProductAttributeValue) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?)", m.table, pmsProductAttributeValueRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.ProductId, data.ProductAttributeId, data.Value)
+	return ret, err
+}
+
+func (m *defaultPmsProductAttributeValueModel) TxDeleteByProductId(session sqlx.Session, productId int64) error {
+	query := fmt.Sprintf("delete from %s where `product_id` = ?", m.table)
+	_, err := session.Exec(query, productId)
+	return err
+}
+
+func (m *defaultPmsProductAttributeValueModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductAttributeValue, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1 for update", pmsProductAttributeValueRows, m.table)
+	var resp PmsProductAttributeValue
+	err := session.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// TxFindByProductId finds the rows of the product in session, locking them.
+func (m *defaultPmsProductAttributeValueModel) TxFindByProductId(session sqlx.Session, productId int64) ([]*PmsProductAttributeValue, error) {
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? order by `id` for update", pmsProductAttributeValueRows, m.table)
+	var resp []*PmsProductAttributeValue
+	err := session.QueryRows(&resp, query, productId)
+	return resp, err
+}
+
+func (m *defaultPmsProductAttributeValueModel) TxUpdate(session sqlx.Session, data PmsProductAttributeValue) error {
+	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductAttributeValueRowsWithPlaceHolder)
+	_, err := session.Exec(query, data.ProductId, data.ProductAttributeId, data.Value, data.Id)
+	return err
+}
+
+func (m *defaultPmsProductAttributeValueModel) TxDelete(session sqlx.Session, id int64) error {
+	query := fmt.Sprintf("delete from %s where `id` = ?", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
 func (m *defaultPmsProductAttributeValueModel) Update(data PmsProductAttributeValue) error {
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductAttributeValueRowsWithPlaceHolder)
 	_, err := m.conn.Exec(query, data.ProductId, data.ProductAttributeId, data.Value, data.Id)
@@ -22,6 +22,15 @@ type (
 	PmsProductFullReductionModel interface {
 		Insert(data PmsProductFullReduction) (sql.Result, error)
 		FindOne(id int64) (*PmsProductFullReduction, error)
+		FindMany(ids []int64) ([]*PmsProductFullReduction, []int64, error)
+		FindByProductId(productId int64) ([]*PmsProductFullReduction, error)
+		FindByProductIds(productIds []int64) ([]*PmsProductFullReduction, error)
+		TxInsert(session sqlx.Session, data PmsProductFullReduction) (sql.Result, error)
+		TxDeleteByProductId(session sqlx.Session, productId int64) error
+		TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductFullReduction, error)
+		TxFindByProductId(session sqlx.Session, productId int64) ([]*PmsProductFullReduction, error)
+		TxUpdate(session sqlx.Session, data PmsProductFullReduction) error
+		TxDelete(session sqlx.Session, id int64) error
 		Update(data PmsProductFullReduction) error
 		Delete(id int64) error
 	}
@@ -32,10 +41,10 @@ type (
 	}
 
 	PmsProductFullReduction struct {
-		ProductId   sql.NullInt64   `db:"product_id"`
-		FullPrice   sql.NullFloat64 `db:"full_price"`
-		ReducePrice sql.NullFloat64 `db:"reduce_price"`
-		Id          int64           `db:"id"`
+		ProductId   sql.NullInt64 `db:"product_id"`
+		FullPrice   NullMoney     `db:"full_price"`
+		ReducePrice NullMoney     `db:"reduce_price"`
+		Id          int64         `db:"id"`
 	}
 )
 
@@ -66,6 +75,101 @@ func (m *defaultPmsProductFullReductionModel) FindOne(id int64) (*PmsProductFull
 	}
 }
 
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsProductFullReductionModel) FindMany(ids []int64) ([]*PmsProductFullReduction, []int64, error) {
+	byId := make(map[int64]*PmsProductFullReduction, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsProductFullReductionRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsProductFullReduction
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsProductFullReduction, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+func (m *defaultPmsProductFullReductionModel) FindByProductId(productId int64) ([]*PmsProductFullReduction, error) {
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? order by `full_price`", pmsProductFullReductionRows, m.table)
+	var resp []*PmsProductFullReduction
+	err := m.conn.QueryRows(&resp, query, productId)
+	return resp, err
+}
+
+func (m *defaultPmsProductFullReductionModel) FindByProductIds(productIds []int64) ([]*PmsProductFullReduction, error) {
+	if len(productIds) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf("select %s from %s where `product_id` in (%s) order by `product_id`, `full_price`",
+		pmsProductFullReductionRows, m.table, inPlaceholders(len(productIds)))
+	var resp []*PmsProductFullReduction
+	err := m.conn.QueryRows(&resp, query, int64sToArgs(productIds)...)
+	return resp, err
+}
+
+func (m *defaultPmsProductFullReductionModel) TxInsert(session sqlx.Session, data PmsProductFullReduction) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?)", m.table, pmsProductFullReductionRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.ProductId, data.FullPrice, data.ReducePrice)
+	return ret, err
+}
+
+func (m *defaultPmsProductFullReductionModel) TxDeleteByProductId(session sqlx.Session, productId int64) error {
+	query := fmt.Sprintf("delete from %s where `product_id` = ?", m.table)
+	_, err := session.Exec(query, productId)
+	return err
+}
+
+func (m *defaultPmsProductFullReductionModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductFullReduction, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1 for update", pmsProductFullReductionRows, m.table)
+	var resp PmsProductFullReduction
+	err := session.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// TxFindByProductId finds the rows of the product in session, locking them.
+func (m *defaultPmsProductFullReductionModel) TxFindByProductId(session sqlx.Session, productId int64) ([]*PmsProductFullReduction, error) {
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? order by `full_price` for update", pmsProductFullReductionRows, m.table)
+	var resp []*PmsProductFullReduction
+	err := session.QueryRows(&resp, query, productId)
+	return resp, err
+}
+
+func (m *defaultPmsProductFullReductionModel) TxUpdate(session sqlx.Session, data PmsProductFullReduction) error {
+	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductFullReductionRowsWithPlaceHolder)
+	_, err := session.Exec(query, data.ProductId, data.FullPrice, data.ReducePrice, data.Id)
+	return err
+}
+
+func (m *defaultPmsProductFullReductionModel) TxDelete(session sqlx.Session, id int64) error {
+	query := fmt.Sprintf("delete from %s where `id` = ?", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
 func (m *defaultPmsProductFullReductionModel) Update(data PmsProductFullReduction) error {
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductFullReductionRowsWithPlaceHolder)
 	_, err := m.conn.Exec(query, data.ProductId, data.FullPrice, data.ReducePrice, data.Id)
@@ -22,6 +22,15 @@ type (
 	PmsProductLadderModel interface {
 		Insert(data PmsProductLadder) (sql.Result, error)
 		FindOne(id int64) (*PmsProductLadder, error)
+		FindMany(ids []int64) ([]*PmsProductLadder, []int64, error)
+		FindByProductId(productId int64) ([]*PmsProductLadder, error)
+		FindByProductIds(productIds []int64) ([]*PmsProductLadder, error)
+		TxInsert(session sqlx.Session, data PmsProductLadder) (sql.Result, error)
+		TxDeleteByProductId(session sqlx.Session, productId int64) error
+		TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductLadder, error)
+		TxFindByProductId(session sqlx.Session, productId int64) ([]*PmsProductLadder, error)
+		TxUpdate(session sqlx.Session, data PmsProductLadder) error
+		TxDelete(session sqlx.Session, id int64) error
 		Update(data PmsProductLadder) error
 		Delete(id int64) error
 	}
@@ -32,11 +41,11 @@ type (
 	}
 
 	PmsProductLadder struct {
-		Id        int64           `db:"id"`
-		ProductId sql.NullInt64   `db:"product_id"`
-		Count     sql.NullInt64   `db:"count"`    // 满足的商品数量
-		Discount  sql.NullFloat64 `db:"discount"` // 折扣
-		Price     sql.NullFloat64 `db:"price"`    // 折后价格
+		Id        int64         `db:"id"`
+		ProductId sql.NullInt64 `db:"product_id"`
+		Count     sql.NullInt64 `db:"count"`    // 满足的商品数量
+		Discount  NullMoney     `db:"discount"` // 折扣
+		Price     NullMoney     `db:"price"`    // 折后价格
 	}
 )
 
@@ -67,6 +76,101 @@ func (m *defaultPmsProductLadderModel) FindOne(id int64) (*PmsProductLadder, err
 	}
 }
 
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsProductLadderModel) FindMany(ids []int64) ([]*PmsProductLadder, []int64, error) {
+	byId := make(map[int64]*PmsProductLadder, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsProductLadderRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsProductLadder
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsProductLadder, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+func (m *defaultPmsProductLadderModel) FindByProductId(productId int64) ([]*PmsProductLadder, error) {
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? order by `count`", pmsProductLadderRows, m.table)
+	var resp []*PmsProductLadder
+	err := m.conn.QueryRows(&resp, query, productId)
+	return resp, err
+}
+
+func (m *defaultPmsProductLadderModel) FindByProductIds(productIds []int64) ([]*PmsProductLadder, error) {
+	if len(productIds) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf("select %s from %s where `product_id` in (%s) order by `product_id`, `count`",
+		pmsProductLadderRows, m.table, inPlaceholders(len(productIds)))
+	var resp []*PmsProductLadder
+	err := m.conn.QueryRows(&resp, query, int64sToArgs(productIds)...)
+	return resp, err
+}
+
+func (m *defaultPmsProductLadderModel) TxInsert(session sqlx.Session, data PmsProductLadder) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?)", m.table, pmsProductLadderRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.ProductId, data.Count, data.Discount, data.Price)
+	return ret, err
+}
+
+func (m *defaultPmsProductLadderModel) TxDeleteByProductId(session sqlx.Session, productId int64) error {
+	query := fmt.Sprintf("delete from %s where `product_id` = ?", m.table)
+	_, err := session.Exec(query, productId)
+	return err
+}
+
+func (m *defaultPmsProductLadderModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductLadder, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1 for update", pmsProductLadderRows, m.table)
+	var resp PmsProductLadder
+	err := session.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// TxFindByProductId finds the rows of the product in session, locking them.
+func (m *defaultPmsProductLadderModel) TxFindByProductId(session sqlx.Session, productId int64) ([]*PmsProductLadder, error) {
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? order by `count` for update", pmsProductLadderRows, m.table)
+	var resp []*PmsProductLadder
+	err := session.QueryRows(&resp, query, productId)
+	return resp, err
+}
+
+func (m *defaultPmsProductLadderModel) TxUpdate(session sqlx.Session, data PmsProductLadder) error {
+	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductLadderRowsWithPlaceHolder)
+	_, err := session.Exec(query, data.ProductId, data.Count, data.Discount, data.Price, data.Id)
+	return err
+}
+
+func (m *defaultPmsProductLadderModel) TxDelete(session sqlx.Session, id int64) error {
+	query := fmt.Sprintf("delete from %s where `id` = ?", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
 func (m *defaultPmsProductLadderModel) Update(data PmsProductLadder) error {
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductLadderRowsWithPlaceHolder)
 	_, err := m.conn.Exec(query, data.ProductId, data.Count, data.Discount, data.Price, data.Id)
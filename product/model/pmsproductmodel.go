@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tal-tech/go-zero/core/stores/sqlc"
 	"github.com/tal-tech/go-zero/core/stores/sqlx"
@@ -22,6 +23,25 @@ type (
 	PmsProductModel interface {
 		Insert(data PmsProduct) (sql.Result, error)
 		FindOne(id int64) (*PmsProduct, error)
+		FindMany(ids []int64) ([]*PmsProduct, []int64, error)
+		FindOneByProductSn(productSn string) (*PmsProduct, error)
+		FindIdByProductSn(productSn string) (int64, error)
+		FindIds(lastId int64, limit int) ([]int64, error)
+		FindLowStock(lastId int64, limit int) ([]*PmsProduct, error)
+		FindPublished(filter PmsProductFilter, lastId int64, limit int) ([]*PmsProduct, error)
+		FindPage(query PmsProductPageQuery, cursor string, limit int) ([]*PmsProduct, string, error)
+		FindRollupDiffs(lastId int64, limit int) ([]*PmsProductRollup, error)
+		RollupSkus(session sqlx.Session, id int64) error
+		TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProduct, error)
+		TxUpdateStatus(session sqlx.Session, id, verifyStatus, publishStatus, previewStatus int64) error
+		FindPromotionWindowDue(now time.Time, lastId int64, limit int) ([]*PmsProduct, error)
+		SetPromotionWindow(session sqlx.Session, id, promotionType int64, start, end time.Time) error
+		ClearPromotionWindow(session sqlx.Session, id int64) error
+		StartPromotionWindow(session sqlx.Session, id int64) error
+		EndPromotionWindow(session sqlx.Session, id int64) error
+		TxUpdate(session sqlx.Session, data PmsProduct) error
+		TxInsert(session sqlx.Session, data PmsProduct) (sql.Result, error)
+		TxDelete(session sqlx.Session, id int64) error
 		Update(data PmsProduct) error
 		Delete(id int64) error
 	}
@@ -38,13 +58,13 @@ type (
 		Note                       sql.NullString  `db:"note" json:"note"`
 		PromotionStartTime         sql.NullTime    `db:"promotion_start_time" json:"promotion_start_time"`   // 促销开始时间
 		ProductCategoryName        sql.NullString  `db:"product_category_name" json:"product_category_name"` // 商品分类名称
-		PromotionPrice             sql.NullFloat64 `db:"promotion_price" json:"promotion_price"`             // 促销价格
+		PromotionPrice             NullMoney       `db:"promotion_price" json:"promotion_price"`             // 促销价格
 		SubTitle                   sql.NullString  `db:"sub_title" json:"sub_title"`                         // 副标题
-		OriginalPrice              sql.NullFloat64 `db:"original_price" json:"original_price"`               // 市场价
+		OriginalPrice              NullMoney       `db:"original_price" json:"original_price"`               // 市场价
 		ServiceIds                 sql.NullString  `db:"service_ids" json:"service_ids"`                     // 以逗号分割的产品服务：1->无忧退货；2->快速退款；3->免费包邮
 		DetailTitle                sql.NullString  `db:"detail_title" json:"detail_title"`
 		ProductSn                  string          `db:"product_sn" json:"product_sn"` // 货号
-		Price                      sql.NullFloat64 `db:"price" json:"price"`
+		Price                      NullMoney       `db:"price" json:"price"`
 		Stock                      sql.NullInt64   `db:"stock" json:"stock"` // 库存
 		DetailDesc                 string          `db:"detail_desc" json:"detail_desc"`
 		DetailMobileHtml           string          `db:"detail_mobile_html" json:"detail_mobile_html"` // 移动端网页详情
@@ -52,7 +72,7 @@ type (
 		FeightTemplateId           sql.NullInt64   `db:"feight_template_id" json:"feight_template_id"`
 		ProductAttributeCategoryId sql.NullInt64   `db:"product_attribute_category_id" json:"product_attribute_category_id"`
 		PublishStatus              sql.NullInt64   `db:"publish_status" json:"publish_status"` // 上架状态：0->下架；1->上架
-		VerifyStatus               sql.NullInt64   `db:"verify_status" json:"verify_status"`   // 审核状态：0->未审核；1->审核通过
+		VerifyStatus               sql.NullInt64   `db:"verify_status" json:"verify_status"`   // 审核状态：0->未审核；1->审核通过；2->审核中；3->审核驳回
 		Name                       string          `db:"name" json:"name"`
 		Description                string          `db:"description" json:"description"`       // 商品描述
 		PromotionType              sql.NullInt64   `db:"promotion_type" json:"promotion_type"` // 促销类型：0->没有促销使用原价;1->使用促销价；2->使用会员价；3->使用阶梯价格；4->使用满减价格；5->限时购
@@ -66,17 +86,47 @@ type (
 		LowStock                   sql.NullInt64   `db:"low_stock" json:"low_stock"`                     // 库存预警值
 		BrandId                    sql.NullInt64   `db:"brand_id" json:"brand_id"`
 		ProductCategoryId          sql.NullInt64   `db:"product_category_id" json:"product_category_id"`
-		DeleteStatus               sql.NullInt64   `db:"delete_status" json:"delete_status"`           // 删除状态：0->未删除；1->已删除
-		NewStatus                  sql.NullInt64   `db:"new_status" json:"new_status"`                 // 新品状态:0->不是新品；1->新品
-		RecommandStatus            sql.NullInt64   `db:"recommand_status" json:"recommand_status"`     // 推荐状态；0->不推荐；1->推荐
-		Unit                       sql.NullString  `db:"unit" json:"unit"`                             // 单位
-		Weight                     sql.NullFloat64 `db:"weight" json:"weight"`                         // 商品重量，默认为克
-		DetailHtml                 string          `db:"detail_html" json:"detail_html"`               // 产品详情网页内容
-		PromotionEndTime           sql.NullTime    `db:"promotion_end_time" json:"promotion_end_time"` // 促销结束时间
-		BrandName                  sql.NullString  `db:"brand_name" json:"brand_name"`                 // 品牌名称
+		DeleteStatus               sql.NullInt64   `db:"delete_status" json:"delete_status"`                     // 删除状态：0->未删除；1->已删除
+		NewStatus                  sql.NullInt64   `db:"new_status" json:"new_status"`                           // 新品状态:0->不是新品；1->新品
+		RecommandStatus            sql.NullInt64   `db:"recommand_status" json:"recommand_status"`               // 推荐状态；0->不推荐；1->推荐
+		Unit                       sql.NullString  `db:"unit" json:"unit"`                                       // 单位
+		Weight                     sql.NullFloat64 `db:"weight" json:"weight"`                                   // 商品重量，默认为克
+		DetailHtml                 string          `db:"detail_html" json:"detail_html"`                         // 产品详情网页内容
+		PromotionEndTime           sql.NullTime    `db:"promotion_end_time" json:"promotion_end_time"`           // 促销结束时间
+		BrandName                  sql.NullString  `db:"brand_name" json:"brand_name"`                           // 品牌名称
+		WindowPromotionType        sql.NullInt64   `db:"window_promotion_type" json:"window_promotion_type"`     // 促销时间段内使用的促销类型
+		PreviousPromotionType      sql.NullInt64   `db:"previous_promotion_type" json:"previous_promotion_type"` // 促销生效前的促销类型，促销结束后恢复
+		UpdateTime                 time.Time       `db:"update_time" json:"update_time"`                         // 更新时间
+	}
+
+	// PmsProductFilter narrows FindPublished, zero fields match any product.
+	PmsProductFilter struct {
+		BrandId           int64
+		ProductCategoryId int64
+		UpdatedSince      time.Time
+	}
+
+	// PmsProductRollup compares a product's stock and sale with the totals of its SKUs.
+	PmsProductRollup struct {
+		ProductId int64 `db:"product_id"`
+		Stock     int64 `db:"stock"`
+		Sale      int64 `db:"sale"`
+		SkuStock  int64 `db:"sku_stock"`
+		SkuSale   int64 `db:"sku_sale"`
 	}
 )
 
+// withDefaults sets the NULLs of data that the columns don't take to the
+// column defaults: sort, sale, price and the statuses are NOT NULL DEFAULT 0.
+func (p PmsProduct) withDefaults() PmsProduct {
+	for _, column := range []*sql.NullInt64{&p.Sort, &p.Sale, &p.DeleteStatus, &p.PublishStatus,
+		&p.VerifyStatus, &p.NewStatus, &p.RecommandStatus, &p.PreviewStatus} {
+		column.Valid = true
+	}
+	p.Price.Valid = true
+	return p
+}
+
 func NewPmsProductModel(conn sqlx.SqlConn) PmsProductModel {
 	return &defaultPmsProductModel{
 		conn:  conn,
@@ -85,9 +135,10 @@ func NewPmsProductModel(conn sqlx.SqlConn) PmsProductModel {
 }
 
 func (m *defaultPmsProductModel) Insert(data PmsProduct) (sql.Result, error) {
-	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, pmsProductRowsExpectAutoSet)
-	ret, err := m.conn.Exec(query, data.Sale, data.PreviewStatus, data.Keywords, data.Note, data.PromotionStartTime, data.ProductCategoryName, data.PromotionPrice, data.SubTitle, data.OriginalPrice, data.ServiceIds, data.DetailTitle, data.ProductSn, data.Price, data.Stock, data.DetailDesc, data.DetailMobileHtml, data.FeightTemplateId, data.ProductAttributeCategoryId, data.PublishStatus, data.VerifyStatus, data.Name, data.Description, data.PromotionType, data.Pic, data.GiftGrowth, data.UsePointLimit, data.AlbumPics, data.PromotionPerLimit, data.Sort, data.GiftPoint, data.LowStock, data.BrandId, data.ProductCategoryId, data.DeleteStatus, data.NewStatus, data.RecommandStatus, data.Unit, data.Weight, data.DetailHtml, data.PromotionEndTime, data.BrandName)
-	return ret, err
+	data = data.withDefaults()
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, pmsProductRowsExpectAutoSet)
+	ret, err := m.conn.Exec(query, data.Sale, data.PreviewStatus, data.Keywords, data.Note, data.PromotionStartTime, data.ProductCategoryName, data.PromotionPrice, data.SubTitle, data.OriginalPrice, data.ServiceIds, data.DetailTitle, data.ProductSn, data.Price, data.Stock, data.DetailDesc, data.DetailMobileHtml, data.FeightTemplateId, data.ProductAttributeCategoryId, data.PublishStatus, data.VerifyStatus, data.Name, data.Description, data.PromotionType, data.Pic, data.GiftGrowth, data.UsePointLimit, data.AlbumPics, data.PromotionPerLimit, data.Sort, data.GiftPoint, data.LowStock, data.BrandId, data.ProductCategoryId, data.DeleteStatus, data.NewStatus, data.RecommandStatus, data.Unit, data.Weight, data.DetailHtml, data.PromotionEndTime, data.BrandName, data.WindowPromotionType, data.PreviousPromotionType)
+	return ret, checkDuplicate(err)
 }
 
 func (m *defaultPmsProductModel) FindOne(id int64) (*PmsProduct, error) {
@@ -104,10 +155,244 @@ func (m *defaultPmsProductModel) FindOne(id int64) (*PmsProduct, error) {
 	}
 }
 
+// FindOneByProductSn finds the product of productSn by its ID, read like FindOne.
+func (m *defaultPmsProductModel) FindOneByProductSn(productSn string) (*PmsProduct, error) {
+	id, err := m.FindIdByProductSn(productSn)
+	if err != nil {
+		return nil, err
+	}
+	return m.FindOne(id)
+}
+
+// FindIdByProductSn finds the ID of the product of productSn.
+func (m *defaultPmsProductModel) FindIdByProductSn(productSn string) (int64, error) {
+	query := fmt.Sprintf("select `id` from %s where `product_sn` = ? limit 1", m.table)
+	var id int64
+	err := m.conn.QueryRow(&id, query, productSn)
+	switch err {
+	case nil:
+		return id, nil
+	case sqlc.ErrNotFound:
+		return 0, ErrNotFound
+	default:
+		return 0, err
+	}
+}
+
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsProductModel) FindMany(ids []int64) ([]*PmsProduct, []int64, error) {
+	byId := make(map[int64]*PmsProduct, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsProductRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsProduct
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsProduct, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+// FindIds pages through the IDs of all products, deleted ones included.
+func (m *defaultPmsProductModel) FindIds(lastId int64, limit int) ([]int64, error) {
+	query := fmt.Sprintf("select `id` from %s where `id` > ? order by `id` limit ?", m.table)
+	var resp []int64
+	err := m.conn.QueryRows(&resp, query, lastId, limit)
+	return resp, err
+}
+
+// FindLowStock pages through products whose stock is at or below their low_stock.
+func (m *defaultPmsProductModel) FindLowStock(lastId int64, limit int) ([]*PmsProduct, error) {
+	query := fmt.Sprintf("select %s from %s where `id` > ? and `stock` <= `low_stock` "+
+		"order by `id` limit ?", pmsProductRows, m.table)
+	var resp []*PmsProduct
+	err := m.conn.QueryRows(&resp, query, lastId, limit)
+	return resp, err
+}
+
+// FindPublished pages through published, undeleted products matching filter.
+func (m *defaultPmsProductModel) FindPublished(filter PmsProductFilter, lastId int64, limit int) ([]*PmsProduct, error) {
+	conds := []string{"`id` > ?", "`publish_status` = 1", "`delete_status` = 0"}
+	args := []interface{}{lastId}
+	if filter.BrandId > 0 {
+		conds = append(conds, "`brand_id` = ?")
+		args = append(args, filter.BrandId)
+	}
+	if filter.ProductCategoryId > 0 {
+		conds = append(conds, "`product_category_id` = ?")
+		args = append(args, filter.ProductCategoryId)
+	}
+	if !filter.UpdatedSince.IsZero() {
+		conds = append(conds, "`update_time` >= ?")
+		args = append(args, filter.UpdatedSince)
+	}
+
+	query := fmt.Sprintf("select %s from %s where %s order by `id` limit ?",
+		pmsProductRows, m.table, strings.Join(conds, " and "))
+	var resp []*PmsProduct
+	err := m.conn.QueryRows(&resp, query, append(args, limit)...)
+	return resp, err
+}
+
+// FindRollupDiffs pages through products with SKUs whose stock or sale
+// differs from the totals of their SKUs.
+func (m *defaultPmsProductModel) FindRollupDiffs(lastId int64, limit int) ([]*PmsProductRollup, error) {
+	query := fmt.Sprintf("select p.`id` as `product_id`, coalesce(p.`stock`, 0) as `stock`, coalesce(p.`sale`, 0) as `sale`, "+
+		"s.`sku_stock`, s.`sku_sale` from %s p join (select `product_id`, coalesce(sum(`stock`), 0) as `sku_stock`, "+
+		"coalesce(sum(`sale`), 0) as `sku_sale` from `pms_sku_stock` where `product_id` > ? group by `product_id`) s "+
+		"on s.`product_id` = p.`id` where coalesce(p.`stock`, 0) <> s.`sku_stock` or coalesce(p.`sale`, 0) <> s.`sku_sale` "+
+		"order by p.`id` limit ?", m.table)
+	var resp []*PmsProductRollup
+	err := m.conn.QueryRows(&resp, query, lastId, limit)
+	return resp, err
+}
+
+// RollupSkus sets the product's stock and sale to the totals of its SKUs.
+// Products without SKUs keep their own stock and sale. A nil session runs
+// outside of a transaction.
+func (m *defaultPmsProductModel) RollupSkus(session sqlx.Session, id int64) error {
+	if session == nil {
+		session = m.conn
+	}
+
+	query := fmt.Sprintf("update %s p join (select `product_id`, coalesce(sum(`stock`), 0) as `sku_stock`, "+
+		"coalesce(sum(`sale`), 0) as `sku_sale` from `pms_sku_stock` where `product_id` = ? group by `product_id`) s "+
+		"on s.`product_id` = p.`id` set p.`stock` = s.`sku_stock`, p.`sale` = s.`sku_sale` where p.`id` = ?", m.table)
+	_, err := session.Exec(query, id, id)
+	return err
+}
+
+func (m *defaultPmsProductModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProduct, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1 for update", pmsProductRows, m.table)
+	var resp PmsProduct
+	err := session.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// TxUpdateStatus sets the verify, publish and preview status of a product.
+func (m *defaultPmsProductModel) TxUpdateStatus(session sqlx.Session, id, verifyStatus, publishStatus, previewStatus int64) error {
+	query := fmt.Sprintf("update %s set `verify_status` = ?, `publish_status` = ?, `preview_status` = ? where `id` = ?", m.table)
+	_, err := session.Exec(query, verifyStatus, publishStatus, previewStatus, id)
+	return err
+}
+
+// FindPromotionWindowDue pages through products whose promotion window has
+// to be started, being in it but not started, or ended, being started but
+// out of it.
+func (m *defaultPmsProductModel) FindPromotionWindowDue(now time.Time, lastId int64, limit int) ([]*PmsProduct, error) {
+	query := fmt.Sprintf("select %s from %s where `id` > ? and `window_promotion_type` is not null and "+
+		"`promotion_start_time` is not null and `promotion_end_time` is not null and "+
+		"((`previous_promotion_type` is null and `promotion_start_time` <= ? and `promotion_end_time` > ?) or "+
+		"(`previous_promotion_type` is not null and (`promotion_start_time` > ? or `promotion_end_time` <= ?))) "+
+		"order by `id` limit ?", pmsProductRows, m.table)
+	var resp []*PmsProduct
+	err := m.conn.QueryRows(&resp, query, lastId, now, now, now, now, limit)
+	return resp, err
+}
+
+// SetPromotionWindow makes promotionType the promotion type from start to
+// end. A started window switches to promotionType right away. A nil session
+// runs outside of a transaction, so do the window methods below.
+func (m *defaultPmsProductModel) SetPromotionWindow(session sqlx.Session, id, promotionType int64, start, end time.Time) error {
+	if session == nil {
+		session = m.conn
+	}
+
+	query := fmt.Sprintf("update %s set `window_promotion_type` = ?, `promotion_start_time` = ?, `promotion_end_time` = ?, "+
+		"`promotion_type` = if(`previous_promotion_type` is null, `promotion_type`, ?) where `id` = ?", m.table)
+	_, err := session.Exec(query, promotionType, start, end, promotionType, id)
+	return err
+}
+
+// ClearPromotionWindow removes the promotion window, restoring the previous
+// promotion type if it was started.
+func (m *defaultPmsProductModel) ClearPromotionWindow(session sqlx.Session, id int64) error {
+	if session == nil {
+		session = m.conn
+	}
+
+	query := fmt.Sprintf("update %s set `promotion_type` = coalesce(`previous_promotion_type`, `promotion_type`), "+
+		"`previous_promotion_type` = null, `window_promotion_type` = null, `promotion_start_time` = null, "+
+		"`promotion_end_time` = null where `id` = ?", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
+// StartPromotionWindow saves the promotion type and switches to the window's.
+// Starting a started window is a no-op.
+func (m *defaultPmsProductModel) StartPromotionWindow(session sqlx.Session, id int64) error {
+	if session == nil {
+		session = m.conn
+	}
+
+	query := fmt.Sprintf("update %s set `previous_promotion_type` = coalesce(`promotion_type`, 0), "+
+		"`promotion_type` = `window_promotion_type` where `id` = ? and `previous_promotion_type` is null "+
+		"and `window_promotion_type` is not null", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
+// EndPromotionWindow restores the promotion type saved by StartPromotionWindow.
+// Ending a window not started is a no-op.
+func (m *defaultPmsProductModel) EndPromotionWindow(session sqlx.Session, id int64) error {
+	if session == nil {
+		session = m.conn
+	}
+
+	query := fmt.Sprintf("update %s set `promotion_type` = `previous_promotion_type`, `previous_promotion_type` = null "+
+		"where `id` = ? and `previous_promotion_type` is not null", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
+func (m *defaultPmsProductModel) TxInsert(session sqlx.Session, data PmsProduct) (sql.Result, error) {
+	data = data.withDefaults()
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, pmsProductRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.Sale, data.PreviewStatus, data.Keywords, data.Note, data.PromotionStartTime, data.ProductCategoryName, data.PromotionPrice, data.SubTitle, data.OriginalPrice, data.ServiceIds, data.DetailTitle, data.ProductSn, data.Price, data.Stock, data.DetailDesc, data.DetailMobileHtml, data.FeightTemplateId, data.ProductAttributeCategoryId, data.PublishStatus, data.VerifyStatus, data.Name, data.Description, data.PromotionType, data.Pic, data.GiftGrowth, data.UsePointLimit, data.AlbumPics, data.PromotionPerLimit, data.Sort, data.GiftPoint, data.LowStock, data.BrandId, data.ProductCategoryId, data.DeleteStatus, data.NewStatus, data.RecommandStatus, data.Unit, data.Weight, data.DetailHtml, data.PromotionEndTime, data.BrandName, data.WindowPromotionType, data.PreviousPromotionType)
+	return ret, checkDuplicate(err)
+}
+
+func (m *defaultPmsProductModel) TxDelete(session sqlx.Session, id int64) error {
+	query := fmt.Sprintf("delete from %s where `id` = ?", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
 func (m *defaultPmsProductModel) Update(data PmsProduct) error {
+	data = data.withDefaults()
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductRowsWithPlaceHolder)
-	_, err := m.conn.Exec(query, data.Sale, data.PreviewStatus, data.Keywords, data.Note, data.PromotionStartTime, data.ProductCategoryName, data.PromotionPrice, data.SubTitle, data.OriginalPrice, data.ServiceIds, data.DetailTitle, data.ProductSn, data.Price, data.Stock, data.DetailDesc, data.DetailMobileHtml, data.FeightTemplateId, data.ProductAttributeCategoryId, data.PublishStatus, data.VerifyStatus, data.Name, data.Description, data.PromotionType, data.Pic, data.GiftGrowth, data.UsePointLimit, data.AlbumPics, data.PromotionPerLimit, data.Sort, data.GiftPoint, data.LowStock, data.BrandId, data.ProductCategoryId, data.DeleteStatus, data.NewStatus, data.RecommandStatus, data.Unit, data.Weight, data.DetailHtml, data.PromotionEndTime, data.BrandName, data.Id)
-	return err
+	_, err := m.conn.Exec(query, data.Sale, data.PreviewStatus, data.Keywords, data.Note, data.PromotionStartTime, data.ProductCategoryName, data.PromotionPrice, data.SubTitle, data.OriginalPrice, data.ServiceIds, data.DetailTitle, data.ProductSn, data.Price, data.Stock, data.DetailDesc, data.DetailMobileHtml, data.FeightTemplateId, data.ProductAttributeCategoryId, data.PublishStatus, data.VerifyStatus, data.Name, data.Description, data.PromotionType, data.Pic, data.GiftGrowth, data.UsePointLimit, data.AlbumPics, data.PromotionPerLimit, data.Sort, data.GiftPoint, data.LowStock, data.BrandId, data.ProductCategoryId, data.DeleteStatus, data.NewStatus, data.RecommandStatus, data.Unit, data.Weight, data.DetailHtml, data.PromotionEndTime, data.BrandName, data.WindowPromotionType, data.PreviousPromotionType, data.Id)
+	return checkDuplicate(err)
+}
+
+func (m *defaultPmsProductModel) TxUpdate(session sqlx.Session, data PmsProduct) error {
+	data = data.withDefaults()
+	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductRowsWithPlaceHolder)
+	_, err := session.Exec(query, data.Sale, data.PreviewStatus, data.Keywords, data.Note, data.PromotionStartTime, data.ProductCategoryName, data.PromotionPrice, data.SubTitle, data.OriginalPrice, data.ServiceIds, data.DetailTitle, data.ProductSn, data.Price, data.Stock, data.DetailDesc, data.DetailMobileHtml, data.FeightTemplateId, data.ProductAttributeCategoryId, data.PublishStatus, data.VerifyStatus, data.Name, data.Description, data.PromotionType, data.Pic, data.GiftGrowth, data.UsePointLimit, data.AlbumPics, data.PromotionPerLimit, data.Sort, data.GiftPoint, data.LowStock, data.BrandId, data.ProductCategoryId, data.DeleteStatus, data.NewStatus, data.RecommandStatus, data.Unit, data.Weight, data.DetailHtml, data.PromotionEndTime, data.BrandName, data.WindowPromotionType, data.PreviousPromotionType, data.Id)
+	return checkDuplicate(err)
 }
 
 func (m *defaultPmsProductModel) Delete(id int64) error {
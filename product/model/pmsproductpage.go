@@ -0,0 +1,200 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// orders of FindPage, the columns products are sorted by, then by id
+const (
+	PmsProductOrderId    = "id"
+	PmsProductOrderSort  = "sort"
+	PmsProductOrderSale  = "sale"
+	PmsProductOrderPrice = "price"
+)
+
+var (
+	ErrInvalidOrder  = errors.New("order must be one of id, sort, sale or price")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+type (
+	// PmsProductPageQuery selects the products of FindPage and their order.
+	// Null statuses and zero IDs match any product.
+	PmsProductPageQuery struct {
+		OrderBy           string
+		Desc              bool
+		DeleteStatus      sql.NullInt64
+		PublishStatus     sql.NullInt64
+		VerifyStatus      sql.NullInt64
+		NewStatus         sql.NullInt64
+		RecommandStatus   sql.NullInt64
+		PreviewStatus     sql.NullInt64
+		BrandId           int64
+		ProductCategoryId int64
+	}
+
+	// pmsProductCursor is where a page ends: the order column and id of its
+	// last product. The order is kept to reject cursors of another order.
+	pmsProductCursor struct {
+		OrderBy string `json:"o"`
+		Desc    bool   `json:"d,omitempty"`
+		Value   int64  `json:"v,omitempty"`
+		Id      int64  `json:"i"`
+	}
+)
+
+// FindPage pages through the products matching query by keyset, resuming
+// after cursor, empty for the first page. The cursor of the next page is
+// returned, empty when there are no more products.
+func (m *defaultPmsProductModel) FindPage(query PmsProductPageQuery, cursor string,
+	limit int) ([]*PmsProduct, string, error) {
+	if len(query.OrderBy) == 0 {
+		query.OrderBy = PmsProductOrderId
+	}
+	column, err := pmsProductOrderColumn(query.OrderBy)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conds, args := query.conditions()
+	if len(cursor) > 0 {
+		after, err := decodePmsProductCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if after.OrderBy != query.OrderBy || after.Desc != query.Desc {
+			return nil, "", ErrInvalidCursor
+		}
+
+		op := ">"
+		if query.Desc {
+			op = "<"
+		}
+		if query.OrderBy == PmsProductOrderId {
+			conds = append(conds, fmt.Sprintf("`id` %s ?", op))
+			args = append(args, after.Id)
+		} else {
+			value := after.value(query.OrderBy)
+			conds = append(conds, fmt.Sprintf("(%s %s ? or (%s = ? and `id` %s ?))", column, op, column, op))
+			args = append(args, value, value, after.Id)
+		}
+	}
+
+	dir := "asc"
+	if query.Desc {
+		dir = "desc"
+	}
+	order := fmt.Sprintf("`id` %s", dir)
+	if query.OrderBy != PmsProductOrderId {
+		order = fmt.Sprintf("%s %s, %s", column, dir, order)
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " where " + strings.Join(conds, " and ")
+	}
+
+	sqlQuery := fmt.Sprintf("select %s from %s%s order by %s limit ?", pmsProductRows, m.table, where, order)
+	var resp []*PmsProduct
+	if err := m.conn.QueryRows(&resp, sqlQuery, append(args, limit)...); err != nil {
+		return nil, "", err
+	}
+	if len(resp) < limit {
+		return resp, "", nil
+	}
+
+	next, err := encodePmsProductCursor(query, resp[len(resp)-1])
+	if err != nil {
+		return nil, "", err
+	}
+	return resp, next, nil
+}
+
+func (q PmsProductPageQuery) conditions() ([]string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	for _, status := range []struct {
+		column string
+		value  sql.NullInt64
+	}{
+		{"delete_status", q.DeleteStatus},
+		{"publish_status", q.PublishStatus},
+		{"verify_status", q.VerifyStatus},
+		{"new_status", q.NewStatus},
+		{"recommand_status", q.RecommandStatus},
+		{"preview_status", q.PreviewStatus},
+	} {
+		if status.value.Valid {
+			conds = append(conds, fmt.Sprintf("`%s` = ?", status.column))
+			args = append(args, status.value.Int64)
+		}
+	}
+	if q.BrandId > 0 {
+		conds = append(conds, "`brand_id` = ?")
+		args = append(args, q.BrandId)
+	}
+	if q.ProductCategoryId > 0 {
+		conds = append(conds, "`product_category_id` = ?")
+		args = append(args, q.ProductCategoryId)
+	}
+
+	return conds, args
+}
+
+// pmsProductOrderColumn returns what products are sorted by for order.
+func pmsProductOrderColumn(order string) (string, error) {
+	switch order {
+	case PmsProductOrderId:
+		return "`id`", nil
+	case PmsProductOrderSort, PmsProductOrderSale, PmsProductOrderPrice:
+		return fmt.Sprintf("`%s`", order), nil
+	default:
+		return "", ErrInvalidOrder
+	}
+}
+
+func (c pmsProductCursor) value(order string) interface{} {
+	if order == PmsProductOrderPrice {
+		return Money(c.Value)
+	}
+	return c.Value
+}
+
+func encodePmsProductCursor(query PmsProductPageQuery, last *PmsProduct) (string, error) {
+	c := pmsProductCursor{
+		OrderBy: query.OrderBy,
+		Desc:    query.Desc,
+		Id:      last.Id,
+	}
+	switch query.OrderBy {
+	case PmsProductOrderSort:
+		c.Value = last.Sort.Int64
+	case PmsProductOrderSale:
+		c.Value = last.Sale.Int64
+	case PmsProductOrderPrice:
+		c.Value = int64(last.Price.Money)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePmsProductCursor(cursor string) (pmsProductCursor, error) {
+	var c pmsProductCursor
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
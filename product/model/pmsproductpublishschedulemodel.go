@@ -0,0 +1,212 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlc"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+	"github.com/tal-tech/go-zero/core/stringx"
+	"github.com/tal-tech/go-zero/tools/goctl/model/sql/builderx"
+)
+
+var (
+	pmsProductPublishScheduleFieldNames          = builderx.RawFieldNames(&PmsProductPublishSchedule{})
+	pmsProductPublishScheduleRows                = strings.Join(pmsProductPublishScheduleFieldNames, ",")
+	pmsProductPublishScheduleRowsExpectAutoSet   = strings.Join(stringx.Remove(pmsProductPublishScheduleFieldNames, "`id`", "`create_time`", "`update_time`"), ",")
+	pmsProductPublishScheduleRowsWithPlaceHolder = strings.Join(stringx.Remove(pmsProductPublishScheduleFieldNames, "`id`", "`create_time`", "`update_time`"), "=?,") + "=?"
+)
+
+type (
+	PmsProductPublishScheduleModel interface {
+		Insert(data PmsProductPublishSchedule) (sql.Result, error)
+		TxInsert(session sqlx.Session, data PmsProductPublishSchedule) (sql.Result, error)
+		FindOne(id int64) (*PmsProductPublishSchedule, error)
+		FindMany(ids []int64) ([]*PmsProductPublishSchedule, []int64, error)
+		FindDue(now time.Time, lastId int64, limit int) ([]*PmsProductPublishSchedule, error)
+		FindPending(productId, lastId int64, limit int) ([]*PmsProductPublishSchedule, error)
+		TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductPublishSchedule, error)
+		TxUpdateStatus(session sqlx.Session, id, from, to int64, detail string) error
+		Update(data PmsProductPublishSchedule) error
+		Delete(id int64) error
+	}
+
+	defaultPmsProductPublishScheduleModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+
+	PmsProductPublishSchedule struct {
+		Operator     string    `db:"operator"` // 操作人
+		CreateTime   time.Time `db:"create_time"`
+		Id           int64     `db:"id"`
+		ProductId    int64     `db:"product_id"`
+		Detail       string    `db:"detail"` // 执行失败原因
+		UpdateTime   time.Time `db:"update_time"`
+		Action       string    `db:"action"`        // 操作：publish->上架；unpublish->下架
+		ScheduleTime time.Time `db:"schedule_time"` // 计划执行时间
+		Status       int64     `db:"status"`        // 状态：0->待执行；1->已执行；2->执行失败；3->已取消
+	}
+)
+
+func NewPmsProductPublishScheduleModel(conn sqlx.SqlConn) PmsProductPublishScheduleModel {
+	return &defaultPmsProductPublishScheduleModel{
+		conn:  conn,
+		table: "`pms_product_publish_schedule`",
+	}
+}
+
+func (m *defaultPmsProductPublishScheduleModel) Insert(data PmsProductPublishSchedule) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?)", m.table, pmsProductPublishScheduleRowsExpectAutoSet)
+	ret, err := m.conn.Exec(query, data.Operator, data.ProductId, data.Detail, data.Action, data.ScheduleTime, data.Status)
+	return ret, err
+}
+
+func (m *defaultPmsProductPublishScheduleModel) TxInsert(session sqlx.Session, data PmsProductPublishSchedule) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?)", m.table, pmsProductPublishScheduleRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.Operator, data.ProductId, data.Detail, data.Action, data.ScheduleTime, data.Status)
+	return ret, err
+}
+
+func (m *defaultPmsProductPublishScheduleModel) FindOne(id int64) (*PmsProductPublishSchedule, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1", pmsProductPublishScheduleRows, m.table)
+	var resp PmsProductPublishSchedule
+	err := m.conn.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsProductPublishScheduleModel) FindMany(ids []int64) ([]*PmsProductPublishSchedule, []int64, error) {
+	byId := make(map[int64]*PmsProductPublishSchedule, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsProductPublishScheduleRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsProductPublishSchedule
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsProductPublishSchedule, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+// FindDue pages through pending schedules whose time has come, earliest
+// first. lastId is the last schedule of the previous page, like FindPending.
+func (m *defaultPmsProductPublishScheduleModel) FindDue(now time.Time, lastId int64, limit int) ([]*PmsProductPublishSchedule, error) {
+	var (
+		conds = []string{"`status` = ?", "`schedule_time` <= ?"}
+		args  = []interface{}{ScheduleStatusPending, now}
+	)
+	if lastId > 0 {
+		last, err := m.FindOne(lastId)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, "(`schedule_time` > ? or `schedule_time` = ? and `id` > ?)")
+		args = append(args, last.ScheduleTime, last.ScheduleTime, lastId)
+	}
+
+	query := fmt.Sprintf("select %s from %s where %s order by `schedule_time`, `id` limit ?",
+		pmsProductPublishScheduleRows, m.table, strings.Join(conds, " and "))
+	var resp []*PmsProductPublishSchedule
+	err := m.conn.QueryRows(&resp, query, append(args, limit)...)
+	return resp, err
+}
+
+// FindPending pages through pending schedules, earliest first, of all
+// products when productId is 0. lastId is the last schedule of the previous
+// page, whose schedule_time the page starts after.
+func (m *defaultPmsProductPublishScheduleModel) FindPending(productId, lastId int64, limit int) ([]*PmsProductPublishSchedule, error) {
+	var (
+		conds = []string{"`status` = ?"}
+		args  = []interface{}{ScheduleStatusPending}
+	)
+	if productId > 0 {
+		conds = append(conds, "`product_id` = ?")
+		args = append(args, productId)
+	}
+	if lastId > 0 {
+		last, err := m.FindOne(lastId)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, "(`schedule_time` > ? or `schedule_time` = ? and `id` > ?)")
+		args = append(args, last.ScheduleTime, last.ScheduleTime, lastId)
+	}
+
+	query := fmt.Sprintf("select %s from %s where %s order by `schedule_time`, `id` limit ?",
+		pmsProductPublishScheduleRows, m.table, strings.Join(conds, " and "))
+	var resp []*PmsProductPublishSchedule
+	err := m.conn.QueryRows(&resp, query, append(args, limit)...)
+	return resp, err
+}
+
+// TxFindOneForUpdate reads and locks the schedule until the transaction ends.
+func (m *defaultPmsProductPublishScheduleModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsProductPublishSchedule, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1 for update", pmsProductPublishScheduleRows, m.table)
+	var resp PmsProductPublishSchedule
+	err := session.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// TxUpdateStatus moves the schedule from status from to status to, failing
+// with ErrScheduleStatus if it is no longer in status from.
+func (m *defaultPmsProductPublishScheduleModel) TxUpdateStatus(session sqlx.Session, id, from, to int64, detail string) error {
+	query := fmt.Sprintf("update %s set `status` = ?, `detail` = ? where `id` = ? and `status` = ?", m.table)
+	ret, err := session.Exec(query, to, detail, id, from)
+	if err != nil {
+		return err
+	}
+	rows, err := ret.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrScheduleStatus
+	}
+
+	return nil
+}
+
+func (m *defaultPmsProductPublishScheduleModel) Update(data PmsProductPublishSchedule) error {
+	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsProductPublishScheduleRowsWithPlaceHolder)
+	_, err := m.conn.Exec(query, data.Operator, data.ProductId, data.Detail, data.Action, data.ScheduleTime, data.Status, data.Id)
+	return err
+}
+
+func (m *defaultPmsProductPublishScheduleModel) Delete(id int64) error {
+	query := fmt.Sprintf("delete from %s where `id` = ?", m.table)
+	_, err := m.conn.Exec(query, id)
+	return err
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlc"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+	"github.com/tal-tech/go-zero/core/stringx"
+	"github.com/tal-tech/go-zero/tools/goctl/model/sql/builderx"
+)
+
+var (
+	pmsProductRevisionFieldNames        = builderx.RawFieldNames(&PmsProductRevision{})
+	pmsProductRevisionRows              = strings.Join(pmsProductRevisionFieldNames, ",")
+	pmsProductRevisionRowsExpectAutoSet = strings.Join(stringx.Remove(pmsProductRevisionFieldNames, "`id`", "`create_time`", "`update_time`"), ",")
+)
+
+type (
+	// PmsProductRevisionModel is append-only, revisions are never updated or deleted.
+	PmsProductRevisionModel interface {
+		Insert(data PmsProductRevision) (sql.Result, error)
+		TxInsert(session sqlx.Session, data PmsProductRevision) (sql.Result, error)
+		FindOne(id int64) (*PmsProductRevision, error)
+		FindMany(ids []int64) ([]*PmsProductRevision, []int64, error)
+		FindOneByProductIdRevision(productId int64, revision int64) (*PmsProductRevision, error)
+		FindByProductId(productId, lastId int64, limit int) ([]*PmsProductRevision, error)
+		TxFindLatest(session sqlx.Session, productId int64) (*PmsProductRevision, error)
+	}
+
+	defaultPmsProductRevisionModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+
+	PmsProductRevision struct {
+		Snapshot   string    `db:"snapshot"` // 商品及其sku、属性值、阶梯价格、满减价格的完整快照，json格式
+		Actor      string    `db:"actor"`    // 操作人
+		CreateTime time.Time `db:"create_time"`
+		Id         int64     `db:"id"`
+		ProductId  int64     `db:"product_id"`
+		Revision   int64     `db:"revision"` // 版本号，每个商品从1开始递增
+	}
+)
+
+func NewPmsProductRevisionModel(conn sqlx.SqlConn) PmsProductRevisionModel {
+	return &defaultPmsProductRevisionModel{
+		conn:  conn,
+		table: "`pms_product_revision`",
+	}
+}
+
+func (m *defaultPmsProductRevisionModel) Insert(data PmsProductRevision) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?)", m.table, pmsProductRevisionRowsExpectAutoSet)
+	ret, err := m.conn.Exec(query, data.Snapshot, data.Actor, data.ProductId, data.Revision)
+	return ret, checkDuplicate(err)
+}
+
+func (m *defaultPmsProductRevisionModel) TxInsert(session sqlx.Session, data PmsProductRevision) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?)", m.table, pmsProductRevisionRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.Snapshot, data.Actor, data.ProductId, data.Revision)
+	return ret, checkDuplicate(err)
+}
+
+func (m *defaultPmsProductRevisionModel) FindOne(id int64) (*PmsProductRevision, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1", pmsProductRevisionRows, m.table)
+	var resp PmsProductRevision
+	err := m.conn.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsProductRevisionModel) FindMany(ids []int64) ([]*PmsProductRevision, []int64, error) {
+	byId := make(map[int64]*PmsProductRevision, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsProductRevisionRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsProductRevision
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsProductRevision, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+func (m *defaultPmsProductRevisionModel) FindOneByProductIdRevision(productId int64, revision int64) (*PmsProductRevision, error) {
+	var resp PmsProductRevision
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? and `revision` = ? limit 1", pmsProductRevisionRows, m.table)
+	err := m.conn.QueryRow(&resp, query, productId, revision)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByProductId pages through a product's revisions, oldest first.
+func (m *defaultPmsProductRevisionModel) FindByProductId(productId, lastId int64, limit int) ([]*PmsProductRevision, error) {
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? and `id` > ? order by `id` limit ?",
+		pmsProductRevisionRows, m.table)
+	var resp []*PmsProductRevision
+	err := m.conn.QueryRows(&resp, query, productId, lastId, limit)
+	return resp, err
+}
+
+// TxFindLatest returns the latest revision of a product, ErrNotFound if none.
+func (m *defaultPmsProductRevisionModel) TxFindLatest(session sqlx.Session, productId int64) (*PmsProductRevision, error) {
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? order by `revision` desc limit 1",
+		pmsProductRevisionRows, m.table)
+	var resp PmsProductRevision
+	err := session.QueryRow(&resp, query, productId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlc"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+	"github.com/tal-tech/go-zero/core/stringx"
+	"github.com/tal-tech/go-zero/tools/goctl/model/sql/builderx"
+)
+
+var (
+	pmsProductVerifyRecordFieldNames        = builderx.RawFieldNames(&PmsProductVerifyRecord{})
+	pmsProductVerifyRecordRows              = strings.Join(pmsProductVerifyRecordFieldNames, ",")
+	pmsProductVerifyRecordRowsExpectAutoSet = strings.Join(stringx.Remove(pmsProductVerifyRecordFieldNames, "`id`", "`create_time`", "`update_time`"), ",")
+)
+
+type (
+	// PmsProductVerifyRecordModel is append-only, records are never updated or deleted.
+	PmsProductVerifyRecordModel interface {
+		Insert(data PmsProductVerifyRecord) (sql.Result, error)
+		TxInsert(session sqlx.Session, data PmsProductVerifyRecord) (sql.Result, error)
+		FindOne(id int64) (*PmsProductVerifyRecord, error)
+		FindMany(ids []int64) ([]*PmsProductVerifyRecord, []int64, error)
+		FindByProductId(productId, lastId int64, limit int) ([]*PmsProductVerifyRecord, error)
+	}
+
+	defaultPmsProductVerifyRecordModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+
+	PmsProductVerifyRecord struct {
+		ToState    string    `db:"to_state"` // 操作后状态
+		Detail     string    `db:"detail"`   // 反馈详情，驳回时为驳回原因
+		Operator   string    `db:"operator"` // 操作人
+		CreateTime time.Time `db:"create_time"`
+		Id         int64     `db:"id"`
+		ProductId  int64     `db:"product_id"`
+		Action     string    `db:"action"`     // 操作：submit->提交审核；approve->审核通过；reject->审核驳回；publish->上架；unpublish->下架；preview->预告
+		FromState  string    `db:"from_state"` // 操作前状态
+	}
+)
+
+func NewPmsProductVerifyRecordModel(conn sqlx.SqlConn) PmsProductVerifyRecordModel {
+	return &defaultPmsProductVerifyRecordModel{
+		conn:  conn,
+		table: "`pms_product_verify_record`",
+	}
+}
+
+func (m *defaultPmsProductVerifyRecordModel) Insert(data PmsProductVerifyRecord) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?)", m.table, pmsProductVerifyRecordRowsExpectAutoSet)
+	ret, err := m.conn.Exec(query, data.ToState, data.Detail, data.Operator, data.ProductId, data.Action, data.FromState)
+	return ret, err
+}
+
+func (m *defaultPmsProductVerifyRecordModel) TxInsert(session sqlx.Session, data PmsProductVerifyRecord) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?)", m.table, pmsProductVerifyRecordRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.ToState, data.Detail, data.Operator, data.ProductId, data.Action, data.FromState)
+	return ret, err
+}
+
+func (m *defaultPmsProductVerifyRecordModel) FindOne(id int64) (*PmsProductVerifyRecord, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1", pmsProductVerifyRecordRows, m.table)
+	var resp PmsProductVerifyRecord
+	err := m.conn.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsProductVerifyRecordModel) FindMany(ids []int64) ([]*PmsProductVerifyRecord, []int64, error) {
+	byId := make(map[int64]*PmsProductVerifyRecord, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsProductVerifyRecordRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsProductVerifyRecord
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsProductVerifyRecord, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+// FindByProductId pages through a product's records in the order they were made.
+func (m *defaultPmsProductVerifyRecordModel) FindByProductId(productId, lastId int64, limit int) ([]*PmsProductVerifyRecord, error) {
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? and `id` > ? order by `id` limit ?",
+		pmsProductVerifyRecordRows, m.table)
+	var resp []*PmsProductVerifyRecord
+	err := m.conn.QueryRows(&resp, query, productId, lastId, limit)
+	return resp, err
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlc"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+	"github.com/tal-tech/go-zero/core/stringx"
+	"github.com/tal-tech/go-zero/tools/goctl/model/sql/builderx"
+)
+
+var (
+	pmsSkuStockLedgerFieldNames        = builderx.RawFieldNames(&PmsSkuStockLedger{})
+	pmsSkuStockLedgerRows              = strings.Join(pmsSkuStockLedgerFieldNames, ",")
+	pmsSkuStockLedgerRowsExpectAutoSet = strings.Join(stringx.Remove(pmsSkuStockLedgerFieldNames, "`id`", "`create_time`", "`update_time`"), ",")
+)
+
+type (
+	// PmsSkuStockLedgerModel is append-only, entries are never updated or deleted.
+	PmsSkuStockLedgerModel interface {
+		Insert(data PmsSkuStockLedger) (sql.Result, error)
+		TxInsert(session sqlx.Session, data PmsSkuStockLedger) (sql.Result, error)
+		FindOne(id int64) (*PmsSkuStockLedger, error)
+		FindMany(ids []int64) ([]*PmsSkuStockLedger, []int64, error)
+		FindBySkuId(skuId, lastId int64, limit int) ([]*PmsSkuStockLedger, error)
+		SumBySkuId(skuId int64) (*PmsSkuStockLedgerSum, error)
+	}
+
+	defaultPmsSkuStockLedgerModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+
+	PmsSkuStockLedger struct {
+		StockDelta     int64     `db:"stock_delta"`      // 库存变化量
+		LockStockDelta int64     `db:"lock_stock_delta"` // 锁定库存变化量
+		Operator       string    `db:"operator"`         // 操作人
+		RefId          string    `db:"ref_id"`           // 关联单号
+		CreateTime     time.Time `db:"create_time"`
+		Id             int64     `db:"id"`
+		SkuId          int64     `db:"sku_id"`
+		Reason         string    `db:"reason"` // 变动原因：restock->入库；lock->锁定；unlock->解锁；deduct->扣减；adjust->手工调整；remove->删除 sku
+	}
+
+	// PmsSkuStockLedgerSum is the stock and lock_stock a SKU's ledger replays to.
+	PmsSkuStockLedgerSum struct {
+		Entries   int64 `db:"entries"`
+		Stock     int64 `db:"stock"`
+		LockStock int64 `db:"lock_stock"`
+	}
+)
+
+func NewPmsSkuStockLedgerModel(conn sqlx.SqlConn) PmsSkuStockLedgerModel {
+	return &defaultPmsSkuStockLedgerModel{
+		conn:  conn,
+		table: "`pms_sku_stock_ledger`",
+	}
+}
+
+func (m *defaultPmsSkuStockLedgerModel) Insert(data PmsSkuStockLedger) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?)", m.table, pmsSkuStockLedgerRowsExpectAutoSet)
+	ret, err := m.conn.Exec(query, data.StockDelta, data.LockStockDelta, data.Operator, data.RefId, data.SkuId, data.Reason)
+	return ret, err
+}
+
+func (m *defaultPmsSkuStockLedgerModel) TxInsert(session sqlx.Session, data PmsSkuStockLedger) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?)", m.table, pmsSkuStockLedgerRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.StockDelta, data.LockStockDelta, data.Operator, data.RefId, data.SkuId, data.Reason)
+	return ret, err
+}
+
+func (m *defaultPmsSkuStockLedgerModel) FindOne(id int64) (*PmsSkuStockLedger, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1", pmsSkuStockLedgerRows, m.table)
+	var resp PmsSkuStockLedger
+	err := m.conn.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsSkuStockLedgerModel) FindMany(ids []int64) ([]*PmsSkuStockLedger, []int64, error) {
+	byId := make(map[int64]*PmsSkuStockLedger, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsSkuStockLedgerRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsSkuStockLedger
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsSkuStockLedger, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+// FindBySkuId pages through a SKU's entries in the order they were recorded.
+func (m *defaultPmsSkuStockLedgerModel) FindBySkuId(skuId, lastId int64, limit int) ([]*PmsSkuStockLedger, error) {
+	query := fmt.Sprintf("select %s from %s where `sku_id` = ? and `id` > ? order by `id` limit ?", pmsSkuStockLedgerRows, m.table)
+	var resp []*PmsSkuStockLedger
+	err := m.conn.QueryRows(&resp, query, skuId, lastId, limit)
+	return resp, err
+}
+
+// SumBySkuId replays a SKU's entries by adding up their deltas.
+func (m *defaultPmsSkuStockLedgerModel) SumBySkuId(skuId int64) (*PmsSkuStockLedgerSum, error) {
+	query := fmt.Sprintf("select count(*) as `entries`, coalesce(sum(`stock_delta`), 0) as `stock`, "+
+		"coalesce(sum(`lock_stock_delta`), 0) as `lock_stock` from %s where `sku_id` = ?", m.table)
+	var resp PmsSkuStockLedgerSum
+	err := m.conn.QueryRow(&resp, query, skuId)
+	return &resp, err
+}
@@ -22,6 +22,23 @@ type (
 	PmsSkuStockModel interface {
 		Insert(data PmsSkuStock) (sql.Result, error)
 		FindOne(id int64) (*PmsSkuStock, error)
+		FindMany(ids []int64) ([]*PmsSkuStock, []int64, error)
+		FindOneBySkuCode(skuCode string) (*PmsSkuStock, error)
+		FindIdBySkuCode(skuCode string) (int64, error)
+		FindByProductIds(productIds []int64) ([]*PmsSkuStock, error)
+		FindLowStock(lastId int64, limit int) ([]*PmsSkuStock, error)
+		LockStock(session sqlx.Session, id, quantity int64) error
+		UnlockStock(session sqlx.Session, id, quantity int64) error
+		DeductStock(session sqlx.Session, id, quantity int64) error
+		AddStock(session sqlx.Session, id, quantity int64) error
+		SetStock(session sqlx.Session, id, stock, lockStock int64) error
+		TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsSkuStock, error)
+		TxFindByProductId(session sqlx.Session, productId int64) ([]*PmsSkuStock, error)
+		TxInsert(session sqlx.Session, data PmsSkuStock) (sql.Result, error)
+		TxUpdateInfo(session sqlx.Session, data PmsSkuStock) error
+		UpdateInfo(data PmsSkuStock) error
+		TxDelete(session sqlx.Session, id int64) error
+		TxUpdate(session sqlx.Session, data PmsSkuStock) error
 		Update(data PmsSkuStock) error
 		Delete(id int64) error
 	}
@@ -32,17 +49,17 @@ type (
 	}
 
 	PmsSkuStock struct {
-		Id             int64           `db:"id"`
-		ProductId      sql.NullInt64   `db:"product_id"`
-		LowStock       sql.NullInt64   `db:"low_stock"`       // 预警库存
-		Pic            sql.NullString  `db:"pic"`             // 展示图片
-		Sale           sql.NullInt64   `db:"sale"`            // 销量
-		PromotionPrice sql.NullFloat64 `db:"promotion_price"` // 单品促销价格
-		LockStock      int64           `db:"lock_stock"`      // 锁定库存
-		SpData         sql.NullString  `db:"sp_data"`         // 商品销售属性，json格式
-		SkuCode        string          `db:"sku_code"`        // sku编码
-		Price          sql.NullFloat64 `db:"price"`
-		Stock          int64           `db:"stock"` // 库存
+		Id             int64          `db:"id"`
+		ProductId      sql.NullInt64  `db:"product_id"`
+		LowStock       sql.NullInt64  `db:"low_stock"`       // 预警库存
+		Pic            sql.NullString `db:"pic"`             // 展示图片
+		Sale           sql.NullInt64  `db:"sale"`            // 销量
+		PromotionPrice NullMoney      `db:"promotion_price"` // 单品促销价格
+		LockStock      int64          `db:"lock_stock"`      // 锁定库存
+		SpData         sql.NullString `db:"sp_data"`         // 商品销售属性，json格式
+		SkuCode        string         `db:"sku_code"`        // sku编码
+		Price          NullMoney      `db:"price"`
+		Stock          int64          `db:"stock"` // 库存
 	}
 )
 
@@ -56,7 +73,7 @@ func NewPmsSkuStockModel(conn sqlx.SqlConn) PmsSkuStockModel {
 func (m *defaultPmsSkuStockModel) Insert(data PmsSkuStock) (sql.Result, error) {
 	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, pmsSkuStockRowsExpectAutoSet)
 	ret, err := m.conn.Exec(query, data.ProductId, data.LowStock, data.Pic, data.Sale, data.PromotionPrice, data.LockStock, data.SpData, data.SkuCode, data.Price, data.Stock)
-	return ret, err
+	return ret, checkDuplicate(err)
 }
 
 func (m *defaultPmsSkuStockModel) FindOne(id int64) (*PmsSkuStock, error) {
@@ -73,10 +90,202 @@ func (m *defaultPmsSkuStockModel) FindOne(id int64) (*PmsSkuStock, error) {
 	}
 }
 
+// FindOneBySkuCode finds the SKU of skuCode by its ID, read like FindOne.
+func (m *defaultPmsSkuStockModel) FindOneBySkuCode(skuCode string) (*PmsSkuStock, error) {
+	id, err := m.FindIdBySkuCode(skuCode)
+	if err != nil {
+		return nil, err
+	}
+	return m.FindOne(id)
+}
+
+// FindIdBySkuCode finds the ID of the SKU of skuCode.
+func (m *defaultPmsSkuStockModel) FindIdBySkuCode(skuCode string) (int64, error) {
+	query := fmt.Sprintf("select `id` from %s where `sku_code` = ? limit 1", m.table)
+	var id int64
+	err := m.conn.QueryRow(&id, query, skuCode)
+	switch err {
+	case nil:
+		return id, nil
+	case sqlc.ErrNotFound:
+		return 0, ErrNotFound
+	default:
+		return 0, err
+	}
+}
+
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsSkuStockModel) FindMany(ids []int64) ([]*PmsSkuStock, []int64, error) {
+	byId := make(map[int64]*PmsSkuStock, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsSkuStockRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsSkuStock
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsSkuStock, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+func (m *defaultPmsSkuStockModel) FindByProductIds(productIds []int64) ([]*PmsSkuStock, error) {
+	if len(productIds) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf("select %s from %s where `product_id` in (%s) order by `product_id`, `id`",
+		pmsSkuStockRows, m.table, inPlaceholders(len(productIds)))
+	var resp []*PmsSkuStock
+	err := m.conn.QueryRows(&resp, query, int64sToArgs(productIds)...)
+	return resp, err
+}
+
+// FindLowStock pages through SKUs whose available stock (stock - lock_stock) is at
+// or below their low_stock, falling back to the product's low_stock when NULL.
+func (m *defaultPmsSkuStockModel) FindLowStock(lastId int64, limit int) ([]*PmsSkuStock, error) {
+	query := fmt.Sprintf("select %s from %s where `id` > ? and `stock` - `lock_stock` <= "+
+		"coalesce(`low_stock`, (select `low_stock` from `pms_product` where `pms_product`.`id` = `product_id`)) "+
+		"order by `id` limit ?", pmsSkuStockRows, m.table)
+	var resp []*PmsSkuStock
+	err := m.conn.QueryRows(&resp, query, lastId, limit)
+	return resp, err
+}
+
+// LockStock moves quantity from available stock into lock_stock, failing with
+// ErrInsufficientStock when stock - lock_stock is not enough. A nil session runs
+// outside of a transaction, so do the others below.
+func (m *defaultPmsSkuStockModel) LockStock(session sqlx.Session, id, quantity int64) error {
+	query := fmt.Sprintf("update %s set `lock_stock` = `lock_stock` + ? where `id` = ? and `stock` - `lock_stock` >= ?", m.table)
+	return m.execStock(session, ErrInsufficientStock, query, quantity, id, quantity)
+}
+
+// UnlockStock releases quantity of lock_stock back to available stock.
+func (m *defaultPmsSkuStockModel) UnlockStock(session sqlx.Session, id, quantity int64) error {
+	query := fmt.Sprintf("update %s set `lock_stock` = `lock_stock` - ? where `id` = ? and `lock_stock` >= ?", m.table)
+	return m.execStock(session, ErrInsufficientLockStock, query, quantity, id, quantity)
+}
+
+// DeductStock ships quantity of locked stock, removing it from both stock and
+// lock_stock and counting it as sold.
+func (m *defaultPmsSkuStockModel) DeductStock(session sqlx.Session, id, quantity int64) error {
+	query := fmt.Sprintf("update %s set `stock` = `stock` - ?, `lock_stock` = `lock_stock` - ?, "+
+		"`sale` = coalesce(`sale`, 0) + ? where `id` = ? and `lock_stock` >= ? and `stock` >= ?", m.table)
+	return m.execStock(session, ErrInsufficientLockStock, query, quantity, quantity, quantity, id, quantity, quantity)
+}
+
+// AddStock puts quantity more into stock, e.g. on restock.
+func (m *defaultPmsSkuStockModel) AddStock(session sqlx.Session, id, quantity int64) error {
+	query := fmt.Sprintf("update %s set `stock` = `stock` + ? where `id` = ?", m.table)
+	return m.execStock(session, ErrNotFound, query, quantity, id)
+}
+
+// SetStock overwrites stock and lock_stock, e.g. on manual adjustment.
+func (m *defaultPmsSkuStockModel) SetStock(session sqlx.Session, id, stock, lockStock int64) error {
+	if session == nil {
+		session = m.conn
+	}
+
+	query := fmt.Sprintf("update %s set `stock` = ?, `lock_stock` = ? where `id` = ?", m.table)
+	_, err := session.Exec(query, stock, lockStock, id)
+	return err
+}
+
+// TxFindByProductId finds the SKUs of the product in session, locking them.
+func (m *defaultPmsSkuStockModel) TxFindByProductId(session sqlx.Session, productId int64) ([]*PmsSkuStock, error) {
+	query := fmt.Sprintf("select %s from %s where `product_id` = ? order by `id` for update", pmsSkuStockRows, m.table)
+	var resp []*PmsSkuStock
+	err := session.QueryRows(&resp, query, productId)
+	return resp, err
+}
+
+// TxFindOneForUpdate reads and locks the SKU until the transaction ends.
+func (m *defaultPmsSkuStockModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsSkuStock, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1 for update", pmsSkuStockRows, m.table)
+	var resp PmsSkuStock
+	err := session.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultPmsSkuStockModel) TxInsert(session sqlx.Session, data PmsSkuStock) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", m.table, pmsSkuStockRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.ProductId, data.LowStock, data.Pic, data.Sale, data.PromotionPrice, data.LockStock, data.SpData, data.SkuCode, data.Price, data.Stock)
+	return ret, checkDuplicate(err)
+}
+
+// TxUpdateInfo updates all but stock, lock_stock and sale, which only change
+// with the inventory.
+func (m *defaultPmsSkuStockModel) TxUpdateInfo(session sqlx.Session, data PmsSkuStock) error {
+	query := fmt.Sprintf("update %s set `product_id` = ?, `low_stock` = ?, `pic` = ?, `promotion_price` = ?, "+
+		"`sp_data` = ?, `sku_code` = ?, `price` = ? where `id` = ?", m.table)
+	_, err := session.Exec(query, data.ProductId, data.LowStock, data.Pic, data.PromotionPrice, data.SpData,
+		data.SkuCode, data.Price, data.Id)
+	return checkDuplicate(err)
+}
+
+// UpdateInfo is TxUpdateInfo outside of a transaction.
+func (m *defaultPmsSkuStockModel) UpdateInfo(data PmsSkuStock) error {
+	return m.TxUpdateInfo(m.conn, data)
+}
+
+func (m *defaultPmsSkuStockModel) TxDelete(session sqlx.Session, id int64) error {
+	query := fmt.Sprintf("delete from %s where `id` = ?", m.table)
+	_, err := session.Exec(query, id)
+	return err
+}
+
+func (m *defaultPmsSkuStockModel) execStock(session sqlx.Session, errNoRows error, query string, args ...interface{}) error {
+	if session == nil {
+		session = m.conn
+	}
+
+	ret, err := session.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	rows, err := ret.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errNoRows
+	}
+
+	return nil
+}
+
+func (m *defaultPmsSkuStockModel) TxUpdate(session sqlx.Session, data PmsSkuStock) error {
+	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsSkuStockRowsWithPlaceHolder)
+	_, err := session.Exec(query, data.ProductId, data.LowStock, data.Pic, data.Sale, data.PromotionPrice, data.LockStock, data.SpData, data.SkuCode, data.Price, data.Stock, data.Id)
+	return checkDuplicate(err)
+}
+
 func (m *defaultPmsSkuStockModel) Update(data PmsSkuStock) error {
 	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsSkuStockRowsWithPlaceHolder)
 	_, err := m.conn.Exec(query, data.ProductId, data.LowStock, data.Pic, data.Sale, data.PromotionPrice, data.LockStock, data.SpData, data.SkuCode, data.Price, data.Stock, data.Id)
-	return err
+	return checkDuplicate(err)
 }
 
 func (m *defaultPmsSkuStockModel) Delete(id int64) error {
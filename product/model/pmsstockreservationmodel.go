@@ -0,0 +1,183 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlc"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+	"github.com/tal-tech/go-zero/core/stringx"
+	"github.com/tal-tech/go-zero/tools/goctl/model/sql/builderx"
+)
+
+var (
+	pmsStockReservationFieldNames          = builderx.RawFieldNames(&PmsStockReservation{})
+	pmsStockReservationRows                = strings.Join(pmsStockReservationFieldNames, ",")
+	pmsStockReservationRowsExpectAutoSet   = strings.Join(stringx.Remove(pmsStockReservationFieldNames, "`id`", "`create_time`", "`update_time`"), ",")
+	pmsStockReservationRowsWithPlaceHolder = strings.Join(stringx.Remove(pmsStockReservationFieldNames, "`id`", "`create_time`", "`update_time`"), "=?,") + "=?"
+)
+
+type (
+	PmsStockReservationModel interface {
+		Insert(data PmsStockReservation) (sql.Result, error)
+		FindOne(id int64) (*PmsStockReservation, error)
+		FindMany(ids []int64) ([]*PmsStockReservation, []int64, error)
+		FindOneByOrderSnSkuId(orderSn string, skuId int64) (*PmsStockReservation, error)
+		FindExpired(now time.Time, lastId int64, limit int) ([]*PmsStockReservation, error)
+		TxInsert(session sqlx.Session, data PmsStockReservation) (sql.Result, error)
+		TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsStockReservation, error)
+		TxUpdateStatus(session sqlx.Session, id, from, to int64) error
+		Update(data PmsStockReservation) error
+		Delete(id int64) error
+	}
+
+	defaultPmsStockReservationModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+
+	PmsStockReservation struct {
+		Quantity   int64     `db:"quantity"`    // 锁定数量
+		Status     int64     `db:"status"`      // 预占状态：0->锁定中；1->已确认；2->已取消；3->已过期
+		ExpireTime time.Time `db:"expire_time"` // 过期时间
+		CreateTime time.Time `db:"create_time"`
+		UpdateTime time.Time `db:"update_time"`
+		Id         int64     `db:"id"`
+		OrderSn    string    `db:"order_sn"` // 订单编号
+		SkuId      int64     `db:"sku_id"`
+	}
+)
+
+func NewPmsStockReservationModel(conn sqlx.SqlConn) PmsStockReservationModel {
+	return &defaultPmsStockReservationModel{
+		conn:  conn,
+		table: "`pms_stock_reservation`",
+	}
+}
+
+func (m *defaultPmsStockReservationModel) Insert(data PmsStockReservation) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?)", m.table, pmsStockReservationRowsExpectAutoSet)
+	ret, err := m.conn.Exec(query, data.Quantity, data.Status, data.ExpireTime, data.OrderSn, data.SkuId)
+	return ret, checkDuplicate(err)
+}
+
+func (m *defaultPmsStockReservationModel) FindOne(id int64) (*PmsStockReservation, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1", pmsStockReservationRows, m.table)
+	var resp PmsStockReservation
+	err := m.conn.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindMany finds the rows of ids in the order of ids, repeats found once, and
+// the IDs not found. It queries at most maxInValues IDs at a time.
+func (m *defaultPmsStockReservationModel) FindMany(ids []int64) ([]*PmsStockReservation, []int64, error) {
+	byId := make(map[int64]*PmsStockReservation, len(ids))
+	err := queryChunks(ids, func(chunk []int64) error {
+		query := fmt.Sprintf("select %s from %s where `id` in (%s)", pmsStockReservationRows, m.table, inPlaceholders(len(chunk)))
+		var resp []*PmsStockReservation
+		if err := m.conn.QueryRows(&resp, query, int64sToArgs(chunk)...); err != nil {
+			return err
+		}
+		for _, row := range resp {
+			byId[row.Id] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]*PmsStockReservation, 0, len(byId))
+	missing := collectIds(ids, func(id int64) bool {
+		row, ok := byId[id]
+		if ok {
+			resp = append(resp, row)
+		}
+		return ok
+	})
+	return resp, missing, nil
+}
+
+func (m *defaultPmsStockReservationModel) FindOneByOrderSnSkuId(orderSn string, skuId int64) (*PmsStockReservation, error) {
+	var resp PmsStockReservation
+	query := fmt.Sprintf("select %s from %s where `order_sn` = ? and `sku_id` = ? limit 1", pmsStockReservationRows, m.table)
+	err := m.conn.QueryRow(&resp, query, orderSn, skuId)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindExpired pages through locked reservations whose expire_time has passed.
+func (m *defaultPmsStockReservationModel) FindExpired(now time.Time, lastId int64, limit int) ([]*PmsStockReservation, error) {
+	query := fmt.Sprintf("select %s from %s where `status` = ? and `expire_time` <= ? and `id` > ? "+
+		"order by `id` limit ?", pmsStockReservationRows, m.table)
+	var resp []*PmsStockReservation
+	err := m.conn.QueryRows(&resp, query, ReservationStatusLocked, now, lastId, limit)
+	return resp, err
+}
+
+func (m *defaultPmsStockReservationModel) TxInsert(session sqlx.Session, data PmsStockReservation) (sql.Result, error) {
+	query := fmt.Sprintf("insert into %s (%s) values (?, ?, ?, ?, ?)", m.table, pmsStockReservationRowsExpectAutoSet)
+	ret, err := session.Exec(query, data.Quantity, data.Status, data.ExpireTime, data.OrderSn, data.SkuId)
+	return ret, checkDuplicate(err)
+}
+
+// TxFindOneForUpdate reads and locks the reservation until the transaction ends.
+func (m *defaultPmsStockReservationModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*PmsStockReservation, error) {
+	query := fmt.Sprintf("select %s from %s where `id` = ? limit 1 for update", pmsStockReservationRows, m.table)
+	var resp PmsStockReservation
+	err := session.QueryRow(&resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlc.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// TxUpdateStatus moves the reservation from status from to status to,
+// failing with ErrReservationStatus if it is no longer in status from.
+func (m *defaultPmsStockReservationModel) TxUpdateStatus(session sqlx.Session, id, from, to int64) error {
+	query := fmt.Sprintf("update %s set `status` = ? where `id` = ? and `status` = ?", m.table)
+	ret, err := session.Exec(query, to, id, from)
+	if err != nil {
+		return err
+	}
+	rows, err := ret.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrReservationStatus
+	}
+
+	return nil
+}
+
+func (m *defaultPmsStockReservationModel) Update(data PmsStockReservation) error {
+	query := fmt.Sprintf("update %s set %s where `id` = ?", m.table, pmsStockReservationRowsWithPlaceHolder)
+	_, err := m.conn.Exec(query, data.Quantity, data.Status, data.ExpireTime, data.OrderSn, data.SkuId, data.Id)
+	return checkDuplicate(err)
+}
+
+func (m *defaultPmsStockReservationModel) Delete(id int64) error {
+	query := fmt.Sprintf("delete from %s where `id` = ?", m.table)
+	_, err := m.conn.Exec(query, id)
+	return err
+}
@@ -0,0 +1,66 @@
+package model
+
+import "strings"
+
+// inPlaceholders returns "?,?,?" for an `in (...)` clause of n values.
+func inPlaceholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?,", n-1) + "?"
+}
+
+func int64sToArgs(vals []int64) []interface{} {
+	args := make([]interface{}, len(vals))
+	for i, v := range vals {
+		args[i] = v
+	}
+	return args
+}
+
+// maxInValues is the most IDs put in one `in (...)` clause by FindMany.
+const maxInValues = 500
+
+// queryChunks calls query with the distinct ids, in chunks of at most maxInValues.
+func queryChunks(ids []int64, query func(chunk []int64) error) error {
+	distinct := distinctIds(ids)
+	for start := 0; start < len(distinct); start += maxInValues {
+		end := start + maxInValues
+		if end > len(distinct) {
+			end = len(distinct)
+		}
+		if err := query(distinct[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectIds calls found with the distinct ids in order, returning the IDs it
+// didn't find.
+func collectIds(ids []int64, found func(id int64) bool) []int64 {
+	var missing []int64
+	for _, id := range distinctIds(ids) {
+		if !found(id) {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing
+}
+
+// distinctIds returns ids without repeats, in the order they first appear.
+func distinctIds(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	distinct := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		distinct = append(distinct, id)
+	}
+
+	return distinct
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// SkuSpec is one sales attribute of a SKU, stored in pms_sku_stock.sp_data as
+// [{"key":"颜色","value":"黑色"},{"key":"容量","value":"32G"}].
+type SkuSpec struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ParseSpData decodes the sales attributes of a SKU.
+func ParseSpData(spData sql.NullString) ([]SkuSpec, error) {
+	if !spData.Valid || len(spData.String) == 0 {
+		return nil, nil
+	}
+
+	var specs []SkuSpec
+	if err := json.Unmarshal([]byte(spData.String), &specs); err != nil {
+		return nil, err
+	}
+
+	return specs, nil
+}
+
+// FormatSpData encodes the sales attributes of a SKU.
+func FormatSpData(specs []SkuSpec) (sql.NullString, error) {
+	if len(specs) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(specs)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
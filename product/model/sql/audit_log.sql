@@ -0,0 +1,17 @@
+-- add 2021-03-22
+
+-- ----------------------------
+-- Table structure for pms_audit_log
+-- ----------------------------
+DROP TABLE IF EXISTS `pms_audit_log`;
+CREATE TABLE `pms_audit_log` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `table_name` varchar(64) NOT NULL COMMENT '表名',
+  `row_id` bigint(20) NOT NULL COMMENT '行id',
+  `action` varchar(16) NOT NULL COMMENT '操作：insert->新增；update->修改；delete->删除',
+  `actor` varchar(64) NOT NULL DEFAULT '' COMMENT '操作人',
+  `changes` text NOT NULL COMMENT '字段变更，json格式：[{"field":"price","before":"1.00","after":"2.00"}]',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_table_name_row_id_id` (`table_name`, `row_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='商品数据操作审计日志，只追加';
@@ -0,0 +1,32 @@
+-- add 2021-03-25
+
+-- ----------------------------
+-- Product pages are ordered by sort, sale or price then id, and filtered by
+-- status. Make these columns NOT NULL DEFAULT 0, so they are compared as they
+-- are and the keys below serve the pages.
+-- ----------------------------
+UPDATE `pms_product` SET `sort` = 0 WHERE `sort` IS NULL;
+UPDATE `pms_product` SET `sale` = 0 WHERE `sale` IS NULL;
+UPDATE `pms_product` SET `price` = 0 WHERE `price` IS NULL;
+UPDATE `pms_product` SET `delete_status` = 0 WHERE `delete_status` IS NULL;
+UPDATE `pms_product` SET `publish_status` = 0 WHERE `publish_status` IS NULL;
+UPDATE `pms_product` SET `verify_status` = 0 WHERE `verify_status` IS NULL;
+UPDATE `pms_product` SET `new_status` = 0 WHERE `new_status` IS NULL;
+UPDATE `pms_product` SET `recommand_status` = 0 WHERE `recommand_status` IS NULL;
+UPDATE `pms_product` SET `preview_status` = 0 WHERE `preview_status` IS NULL;
+
+ALTER TABLE `pms_product`
+  MODIFY COLUMN `sort` int(11) NOT NULL DEFAULT 0 COMMENT '排序',
+  MODIFY COLUMN `sale` int(11) NOT NULL DEFAULT 0 COMMENT '销量',
+  MODIFY COLUMN `price` decimal(10,2) NOT NULL DEFAULT 0.00,
+  MODIFY COLUMN `delete_status` int(1) NOT NULL DEFAULT 0 COMMENT '删除状态：0->未删除；1->已删除',
+  MODIFY COLUMN `publish_status` int(1) NOT NULL DEFAULT 0 COMMENT '上架状态：0->下架；1->上架',
+  MODIFY COLUMN `verify_status` int(1) NOT NULL DEFAULT 0 COMMENT '审核状态：0->未审核；1->审核通过；2->审核中；3->审核驳回',
+  MODIFY COLUMN `new_status` int(1) NOT NULL DEFAULT 0 COMMENT '新品状态:0->不是新品；1->新品',
+  MODIFY COLUMN `recommand_status` int(1) NOT NULL DEFAULT 0 COMMENT '推荐状态；0->不推荐；1->推荐',
+  MODIFY COLUMN `preview_status` int(1) NOT NULL DEFAULT 0 COMMENT '是否为预告商品：0->不是；1->是',
+  ADD KEY `idx_sort_id` (`sort`, `id`),
+  ADD KEY `idx_sale_id` (`sale`, `id`),
+  ADD KEY `idx_price_id` (`price`, `id`),
+  ADD KEY `idx_delete_status_publish_status_id` (`delete_status`, `publish_status`, `id`),
+  ADD KEY `idx_verify_status_id` (`verify_status`, `id`);
@@ -0,0 +1,15 @@
+-- add 2021-03-21
+
+-- ----------------------------
+-- Promotion windows of pms_product: promotion_type is switched to
+-- window_promotion_type between promotion_start_time and promotion_end_time,
+-- and back to previous_promotion_type afterwards
+-- ----------------------------
+ALTER TABLE `pms_product`
+  ADD COLUMN `window_promotion_type` int(1) DEFAULT NULL COMMENT '促销时间段内使用的促销类型',
+  ADD COLUMN `previous_promotion_type` int(1) DEFAULT NULL COMMENT '促销生效前的促销类型，促销结束后恢复',
+  ADD KEY `idx_promotion_end_time` (`promotion_end_time`);
+
+-- windowed promotions used to be set directly on promotion_type
+UPDATE `pms_product` SET `window_promotion_type` = `promotion_type`, `promotion_type` = 0
+WHERE `promotion_start_time` IS NOT NULL AND `promotion_end_time` IS NOT NULL AND `promotion_type` IN (1, 5);
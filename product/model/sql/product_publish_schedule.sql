@@ -0,0 +1,20 @@
+-- add 2021-03-20
+
+-- ----------------------------
+-- Table structure for pms_product_publish_schedule
+-- ----------------------------
+DROP TABLE IF EXISTS `pms_product_publish_schedule`;
+CREATE TABLE `pms_product_publish_schedule` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `product_id` bigint(20) NOT NULL,
+  `action` varchar(16) NOT NULL COMMENT '操作：publish->上架；unpublish->下架',
+  `schedule_time` datetime NOT NULL COMMENT '计划执行时间',
+  `status` int(1) NOT NULL DEFAULT '0' COMMENT '状态：0->待执行；1->已执行；2->执行失败；3->已取消',
+  `detail` varchar(255) NOT NULL DEFAULT '' COMMENT '执行失败原因',
+  `operator` varchar(64) NOT NULL DEFAULT '' COMMENT '操作人',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_status_schedule_time` (`status`, `schedule_time`),
+  KEY `idx_product_id` (`product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='商品定时上下架计划';
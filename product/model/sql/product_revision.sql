@@ -0,0 +1,16 @@
+-- add 2021-03-23
+
+-- ----------------------------
+-- Table structure for pms_product_revision
+-- ----------------------------
+DROP TABLE IF EXISTS `pms_product_revision`;
+CREATE TABLE `pms_product_revision` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `product_id` bigint(20) NOT NULL,
+  `revision` int(11) NOT NULL COMMENT '版本号，每个商品从1开始递增',
+  `snapshot` longtext NOT NULL COMMENT '商品及其sku、属性值、阶梯价格、满减价格的完整快照，json格式',
+  `actor` varchar(64) NOT NULL DEFAULT '' COMMENT '操作人',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_product_id_revision` (`product_id`, `revision`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='商品版本快照';
@@ -0,0 +1,13 @@
+-- add 2021-03-24
+
+-- ----------------------------
+-- product_sn and sku_code identify products and SKUs to ERP and warehouse
+-- systems, make them unique. Duplicates have to be renamed first, find them with
+--   SELECT `product_sn` FROM `pms_product` GROUP BY `product_sn` HAVING count(*) > 1;
+--   SELECT `sku_code` FROM `pms_sku_stock` GROUP BY `sku_code` HAVING count(*) > 1;
+-- ----------------------------
+ALTER TABLE `pms_product`
+  ADD UNIQUE KEY `uk_product_sn` (`product_sn`);
+
+ALTER TABLE `pms_sku_stock`
+  ADD UNIQUE KEY `uk_sku_code` (`sku_code`);
@@ -0,0 +1,9 @@
+-- add 2021-03-18
+
+-- ----------------------------
+-- Add update_time to pms_product, for incremental catalog exports
+-- ----------------------------
+ALTER TABLE `pms_product`
+  ADD COLUMN `update_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  ADD KEY `idx_publish_status_id` (`publish_status`, `id`),
+  ADD KEY `idx_update_time` (`update_time`);
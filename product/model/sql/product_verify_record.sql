@@ -0,0 +1,18 @@
+-- add 2021-03-19
+
+-- ----------------------------
+-- Table structure for pms_product_verify_record
+-- ----------------------------
+DROP TABLE IF EXISTS `pms_product_verify_record`;
+CREATE TABLE `pms_product_verify_record` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `product_id` bigint(20) NOT NULL,
+  `action` varchar(16) NOT NULL COMMENT '操作：submit->提交审核；approve->审核通过；reject->审核驳回；publish->上架；unpublish->下架；preview->预告',
+  `from_state` varchar(16) NOT NULL COMMENT '操作前状态',
+  `to_state` varchar(16) NOT NULL COMMENT '操作后状态',
+  `detail` varchar(255) NOT NULL DEFAULT '' COMMENT '反馈详情，驳回时为驳回原因',
+  `operator` varchar(64) NOT NULL DEFAULT '' COMMENT '操作人',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_product_id_id` (`product_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='商品审核及上下架记录';
@@ -0,0 +1,18 @@
+-- add 2021-03-17
+
+-- ----------------------------
+-- Table structure for pms_sku_stock_ledger
+-- ----------------------------
+DROP TABLE IF EXISTS `pms_sku_stock_ledger`;
+CREATE TABLE `pms_sku_stock_ledger` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `sku_id` bigint(20) NOT NULL,
+  `reason` varchar(16) NOT NULL COMMENT '变动原因：restock->入库；lock->锁定；unlock->解锁；deduct->扣减；adjust->手工调整；remove->删除 sku',
+  `stock_delta` int(11) NOT NULL DEFAULT '0' COMMENT '库存变化量',
+  `lock_stock_delta` int(11) NOT NULL DEFAULT '0' COMMENT '锁定库存变化量',
+  `operator` varchar(64) NOT NULL DEFAULT '' COMMENT '操作人',
+  `ref_id` varchar(64) NOT NULL DEFAULT '' COMMENT '关联单号',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_sku_id_id` (`sku_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='sku库存流水表，只追加';
@@ -0,0 +1,19 @@
+-- add 2021-03-16
+
+-- ----------------------------
+-- Table structure for pms_stock_reservation
+-- ----------------------------
+DROP TABLE IF EXISTS `pms_stock_reservation`;
+CREATE TABLE `pms_stock_reservation` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `order_sn` varchar(64) NOT NULL COMMENT '订单编号',
+  `sku_id` bigint(20) NOT NULL,
+  `quantity` int(11) NOT NULL COMMENT '锁定数量',
+  `status` int(1) NOT NULL DEFAULT '0' COMMENT '预占状态：0->锁定中；1->已确认；2->已取消；3->已过期',
+  `expire_time` datetime NOT NULL COMMENT '过期时间',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `update_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_order_sn_sku_id` (`order_sn`, `sku_id`),
+  KEY `idx_status_expire_time` (`status`, `expire_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='库存预占表';
@@ -1,5 +1,49 @@
 package model
 
-import "github.com/tal-tech/go-zero/core/stores/sqlx"
+import (
+	"errors"
 
-var ErrNotFound = sqlx.ErrNotFound
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+var (
+	ErrNotFound              = sqlx.ErrNotFound
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrInsufficientLockStock = errors.New("insufficient locked stock")
+	ErrReservationStatus     = errors.New("reservation status changed")
+	ErrScheduleStatus        = errors.New("schedule status changed")
+)
+
+// pms_product.promotion_type
+const (
+	PromotionTypeNone          = 0 // 没有促销使用原价
+	PromotionTypePromotion     = 1 // 使用促销价
+	PromotionTypeMember        = 2 // 使用会员价
+	PromotionTypeLadder        = 3 // 使用阶梯价格
+	PromotionTypeFullReduction = 4 // 使用满减价格
+	PromotionTypeFlashSale     = 5 // 限时购
+)
+
+// pms_product.verify_status
+const (
+	VerifyStatusDraft    = 0 // 未审核
+	VerifyStatusApproved = 1 // 审核通过
+	VerifyStatusPending  = 2 // 审核中
+	VerifyStatusRejected = 3 // 审核驳回
+)
+
+// pms_stock_reservation.status
+const (
+	ReservationStatusLocked    = 0 // 锁定中
+	ReservationStatusConfirmed = 1 // 已确认
+	ReservationStatusCancelled = 2 // 已取消
+	ReservationStatusExpired   = 3 // 已过期
+)
+
+// pms_product_publish_schedule.status
+const (
+	ScheduleStatusPending   = 0 // 待执行
+	ScheduleStatusDone      = 1 // 已执行
+	ScheduleStatusFailed    = 2 // 执行失败
+	ScheduleStatusCancelled = 3 // 已取消
+)
@@ -0,0 +1,145 @@
+package pricing
+
+import (
+	"math/big"
+	"time"
+
+	"malltmp/product/model"
+)
+
+type (
+	// Line is one product bought as one or more items, one per SKU.
+	Line struct {
+		Product    *model.PmsProduct
+		Ladders    []*model.PmsProductLadder
+		Reductions []*model.PmsProductFullReduction
+		Items      []Item
+		// At is when the line is priced, now if zero
+		At time.Time
+	}
+
+	// Item is a quantity of a product, of one SKU when Sku is not nil.
+	Item struct {
+		Sku *model.PmsSkuStock
+		Qty int64
+	}
+
+	// Breakdown is the price of an Item, with the saving of each promotion.
+	Breakdown struct {
+		Qty             int64
+		UnitPrice       model.Money // price of one item before promotions
+		Subtotal        model.Money // UnitPrice × Qty
+		PromotionSaving model.Money // 促销价, 限时购
+		LadderSaving    model.Money // 阶梯价格
+		ReductionSaving model.Money // 满减价格
+		Total           model.Money
+		Ladder          *model.PmsProductLadder
+		Reduction       *ReductionResult
+	}
+
+	// Calculator prices lines according to their product's promotion type.
+	Calculator struct {
+		ReductionMode ReductionMode
+	}
+)
+
+// Saving returns the sum of all promotion savings.
+func (b *Breakdown) Saving() model.Money {
+	return b.PromotionSaving + b.LadderSaving + b.ReductionSaving
+}
+
+// Quote prices each item of the line. Only the promotion selected by the
+// product's promotion type at the time of the line applies; the others
+// never stack with it.
+// Ladders and full reductions are per product (只针对同商品), so the tier
+// is chosen by the quantity of all items and the reduction by their
+// combined subtotal, then shared across items by subtotal.
+func (c Calculator) Quote(line Line) ([]*Breakdown, error) {
+	var (
+		breakdowns = make([]*Breakdown, len(line.Items))
+		qty        int64
+		subtotal   model.Money
+	)
+	for i, item := range line.Items {
+		if item.Qty <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+
+		unit := line.Product.Price.Money
+		if item.Sku != nil {
+			unit = item.Sku.Price.Or(unit)
+		}
+		breakdowns[i] = &Breakdown{
+			Qty:       item.Qty,
+			UnitPrice: unit,
+			Subtotal:  unit.Mul(item.Qty),
+		}
+		qty += item.Qty
+		subtotal += breakdowns[i].Subtotal
+	}
+
+	at := line.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	switch PromotionTypeAt(line.Product, at) {
+	case model.PromotionTypePromotion, model.PromotionTypeFlashSale:
+		for i, item := range line.Items {
+			promotion := line.Product.PromotionPrice
+			if item.Sku != nil && item.Sku.PromotionPrice.Valid {
+				promotion = item.Sku.PromotionPrice
+			}
+
+			b := breakdowns[i]
+			if promotion.Valid && promotion.Money < b.UnitPrice {
+				b.PromotionSaving = b.UnitPrice.Sub(promotion.Money).Mul(b.Qty)
+			}
+		}
+	case model.PromotionTypeLadder:
+		ladder, err := ResolveLadder(line.Ladders, qty)
+		switch err {
+		case nil:
+			for _, b := range breakdowns {
+				b.Ladder = ladder
+				b.LadderSaving = b.Subtotal - LadderUnitPrice(b.UnitPrice, ladder).Mul(b.Qty)
+			}
+		case ErrNoLadder:
+		default:
+			return nil, err
+		}
+	case model.PromotionTypeFullReduction:
+		reduction, err := EvaluateReduction(c.ReductionMode, line.Reductions, subtotal)
+		switch err {
+		case nil:
+			shareReduction(breakdowns, reduction, subtotal)
+		case ErrNoReduction:
+		default:
+			return nil, err
+		}
+	}
+
+	for _, b := range breakdowns {
+		b.Total = b.Subtotal.Reduce(b.Saving())
+	}
+
+	return breakdowns, nil
+}
+
+// shareReduction shares the saving of reduction across breakdowns by their
+// subtotal, the last taking what rounding leaves. Saving × Subtotal is taken
+// in big.Int, being past int64 on large carts.
+func shareReduction(breakdowns []*Breakdown, reduction *ReductionResult, subtotal model.Money) {
+	saving, total := big.NewInt(int64(reduction.Saving)), big.NewInt(int64(subtotal))
+	left := reduction.Saving
+	for i, b := range breakdowns {
+		share := left
+		if i < len(breakdowns)-1 {
+			n := new(big.Int).Mul(saving, big.NewInt(int64(b.Subtotal)))
+			share = model.Money(n.Quo(n, total).Int64())
+		}
+
+		b.Reduction = reduction
+		b.ReductionSaving = share
+		left -= share
+	}
+}
@@ -0,0 +1,163 @@
+package pricing
+
+import (
+	"database/sql"
+	"testing"
+
+	"malltmp/product/model"
+)
+
+func pricedProduct(price, promotionPrice string, promotionType int64) *model.PmsProduct {
+	p := &model.PmsProduct{
+		Price:         model.NewNullMoney(model.MustParseMoney(price)),
+		PromotionType: sql.NullInt64{Int64: promotionType, Valid: true},
+	}
+	if len(promotionPrice) > 0 {
+		p.PromotionPrice = model.NewNullMoney(model.MustParseMoney(promotionPrice))
+	}
+	return p
+}
+
+func pricedSku(price, promotionPrice string) *model.PmsSkuStock {
+	s := &model.PmsSkuStock{}
+	if len(price) > 0 {
+		s.Price = model.NewNullMoney(model.MustParseMoney(price))
+	}
+	if len(promotionPrice) > 0 {
+		s.PromotionPrice = model.NewNullMoney(model.MustParseMoney(promotionPrice))
+	}
+	return s
+}
+
+func TestQuoteInvalidQuantity(t *testing.T) {
+	_, err := Calculator{}.Quote(Line{
+		Product: pricedProduct("10", "", model.PromotionTypeNone),
+		Items:   []Item{{Qty: 1}, {Qty: 0}},
+	})
+	if err != ErrInvalidQuantity {
+		t.Errorf("error = %v, want ErrInvalidQuantity", err)
+	}
+}
+
+func TestQuoteNoPromotion(t *testing.T) {
+	b, err := Calculator{}.Quote(Line{
+		Product: pricedProduct("10", "8", model.PromotionTypeNone),
+		Items:   []Item{{Qty: 3}, {Sku: pricedSku("12.50", ""), Qty: 2}, {Sku: pricedSku("", ""), Qty: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantTotals := []string{"30.00", "25.00", "10.00"}
+	for i, want := range wantTotals {
+		if b[i].Total.String() != want || !b[i].Saving().IsZero() {
+			t.Errorf("item %d: total %s saving %s, want %s and no saving", i, b[i].Total, b[i].Saving(), want)
+		}
+	}
+}
+
+func TestQuotePromotionPrice(t *testing.T) {
+	for _, promotionType := range []int64{model.PromotionTypePromotion, model.PromotionTypeFlashSale} {
+		b, err := Calculator{}.Quote(Line{
+			Product: pricedProduct("10", "8", promotionType),
+			Items: []Item{
+				{Qty: 2},
+				{Sku: pricedSku("12", "9"), Qty: 1},
+				// a promotion price above the unit price doesn't apply
+				{Sku: pricedSku("7", ""), Qty: 1},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, want := range []struct{ saving, total string }{{"4.00", "16.00"}, {"3.00", "9.00"}, {"0.00", "7.00"}} {
+			if b[i].PromotionSaving.String() != want.saving || b[i].Total.String() != want.total {
+				t.Errorf("type %d item %d: saving %s total %s, want %s and %s", promotionType, i,
+					b[i].PromotionSaving, b[i].Total, want.saving, want.total)
+			}
+		}
+	}
+}
+
+func TestQuoteLadderByLineQuantity(t *testing.T) {
+	line := Line{
+		Product: pricedProduct("10", "", model.PromotionTypeLadder),
+		Ladders: []*model.PmsProductLadder{ladder(1, 3, "0.90", "9.00")},
+		Items:   []Item{{Qty: 2}, {Sku: pricedSku("20", ""), Qty: 1}},
+	}
+	b, err := Calculator{}.Quote(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 items of the product reach the tier together
+	if b[0].LadderSaving.String() != "2.00" || b[1].LadderSaving.String() != "2.00" {
+		t.Errorf("ladder savings %s and %s, want 2.00 each", b[0].LadderSaving, b[1].LadderSaving)
+	}
+
+	line.Items = line.Items[:1]
+	b, err = Calculator{}.Quote(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b[0].Ladder != nil || !b[0].Saving().IsZero() {
+		t.Errorf("below every tier: ladder %v saving %s", b[0].Ladder, b[0].Saving())
+	}
+}
+
+func TestQuoteFullReductionShared(t *testing.T) {
+	b, err := Calculator{ReductionMode: ReductionBest}.Quote(Line{
+		Product:    pricedProduct("33.33", "", model.PromotionTypeFullReduction),
+		Reductions: []*model.PmsProductFullReduction{reduction(1, "90", "10")},
+		Items:      []Item{{Qty: 1}, {Qty: 1}, {Sku: pricedSku("33.34", ""), Qty: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var saving, total model.Money
+	for _, item := range b {
+		if item.Reduction == nil || item.Reduction.ReductionId != 1 {
+			t.Fatalf("reduction not applied: %+v", item)
+		}
+		saving += item.ReductionSaving
+		total += item.Total
+	}
+	// shares are rounded down, the last item takes what is left
+	if saving.String() != "10.00" || total.String() != "90.00" {
+		t.Errorf("saving %s total %s, want 10.00 and 90.00", saving, total)
+	}
+	if b[0].ReductionSaving.String() != "3.33" || b[2].ReductionSaving.String() != "3.34" {
+		t.Errorf("shares %s, %s, %s", b[0].ReductionSaving, b[1].ReductionSaving, b[2].ReductionSaving)
+	}
+}
+
+func TestShareReductionLargeCart(t *testing.T) {
+	// saving × subtotal is past int64
+	half := model.NewMoney(30000000, 0)
+	breakdowns := []*Breakdown{{Subtotal: half}, {Subtotal: half}}
+	reduction := &ReductionResult{Saving: model.NewMoney(40000000, 0)}
+	shareReduction(breakdowns, reduction, half*2)
+
+	for i, b := range breakdowns {
+		if b.ReductionSaving.String() != "20000000.00" {
+			t.Errorf("share %d = %s, want 20000000.00", i, b.ReductionSaving)
+		}
+	}
+}
+
+func TestQuotePromotionsDontStack(t *testing.T) {
+	product := pricedProduct("100", "50", model.PromotionTypeFullReduction)
+	b, err := Calculator{}.Quote(Line{
+		Product:    product,
+		Ladders:    []*model.PmsProductLadder{ladder(1, 1, "0.50", "")},
+		Reductions: []*model.PmsProductFullReduction{reduction(1, "100", "10")},
+		Items:      []Item{{Qty: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b[0].PromotionSaving.IsZero() || !b[0].LadderSaving.IsZero() || b[0].ReductionSaving.String() != "10.00" {
+		t.Errorf("only the full reduction applies: %+v", b[0])
+	}
+}
@@ -0,0 +1,95 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"malltmp/product/model"
+)
+
+var (
+	ErrInvalidQuantity = errors.New("quantity must be positive")
+	ErrNoLadder        = errors.New("no ladder tier applies")
+
+	maxDiscount = model.NewMoney(1, 0)
+)
+
+// LadderError reports why a product's ladder set is inconsistent.
+type LadderError struct {
+	LadderId int64
+	Reason   string
+}
+
+func (e *LadderError) Error() string {
+	return fmt.Sprintf("ladder %d: %s", e.LadderId, e.Reason)
+}
+
+// SortLadders orders tiers by ascending count, NULL counts first.
+func SortLadders(ladders []*model.PmsProductLadder) {
+	sort.SliceStable(ladders, func(i, j int) bool {
+		return ladders[i].Count.Int64 < ladders[j].Count.Int64
+	})
+}
+
+// ValidateLadders checks a product's ladder set: counts are set, unique and
+// increasing, discounts are in (0,1], and each tier price equals the discount
+// applied to the base price.
+func ValidateLadders(base model.Money, ladders []*model.PmsProductLadder) error {
+	sorted := make([]*model.PmsProductLadder, len(ladders))
+	copy(sorted, ladders)
+	SortLadders(sorted)
+
+	var prev *model.PmsProductLadder
+	for _, ladder := range sorted {
+		if !ladder.Count.Valid || ladder.Count.Int64 <= 0 {
+			return &LadderError{LadderId: ladder.Id, Reason: "count must be positive"}
+		}
+		if prev != nil && prev.Count.Int64 == ladder.Count.Int64 {
+			return &LadderError{LadderId: ladder.Id, Reason: fmt.Sprintf("count %d duplicates ladder %d", ladder.Count.Int64, prev.Id)}
+		}
+		if !ladder.Discount.Valid || ladder.Discount.Money <= 0 || ladder.Discount.Money > maxDiscount {
+			return &LadderError{LadderId: ladder.Id, Reason: "discount must be in (0,1]"}
+		}
+		if prev != nil && ladder.Discount.Money > prev.Discount.Money {
+			return &LadderError{LadderId: ladder.Id, Reason: "discount must not increase with count"}
+		}
+		if want := base.Discount(ladder.Discount.Money); ladder.Price.Valid && ladder.Price.Money != want {
+			return &LadderError{LadderId: ladder.Id, Reason: fmt.Sprintf("price %s, want %s", ladder.Price.Money, want)}
+		}
+
+		prev = ladder
+	}
+
+	return nil
+}
+
+// ResolveLadder returns the tier with the largest count not exceeding qty.
+func ResolveLadder(ladders []*model.PmsProductLadder, qty int64) (*model.PmsProductLadder, error) {
+	if qty <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	var best *model.PmsProductLadder
+	for _, ladder := range ladders {
+		if !ladder.Count.Valid || ladder.Count.Int64 > qty {
+			continue
+		}
+		if best == nil || ladder.Count.Int64 > best.Count.Int64 {
+			best = ladder
+		}
+	}
+	if best == nil {
+		return nil, ErrNoLadder
+	}
+
+	return best, nil
+}
+
+// LadderUnitPrice returns the unit price of a tier for an item priced unit,
+// its discount applied. The stored price is the one of the product's base
+// price, shown to buyers and kept equal by ValidateLadders, but a SKU may be
+// priced otherwise, so every quote goes by the discount.
+func LadderUnitPrice(unit model.Money, ladder *model.PmsProductLadder) model.Money {
+	return unit.Discount(ladder.Discount.Or(maxDiscount))
+}
@@ -0,0 +1,151 @@
+package pricing
+
+import (
+	"database/sql"
+	"testing"
+
+	"malltmp/product/model"
+)
+
+func ladder(id, count int64, discount, price string) *model.PmsProductLadder {
+	l := &model.PmsProductLadder{
+		Id:    id,
+		Count: sql.NullInt64{Int64: count, Valid: count != 0},
+	}
+	if len(discount) > 0 {
+		l.Discount = model.NewNullMoney(model.MustParseMoney(discount))
+	}
+	if len(price) > 0 {
+		l.Price = model.NewNullMoney(model.MustParseMoney(price))
+	}
+	return l
+}
+
+func TestValidateLadders(t *testing.T) {
+	base := model.MustParseMoney("100.00")
+	tests := []struct {
+		name    string
+		ladders []*model.PmsProductLadder
+		wantId  int64
+	}{
+		{"empty", nil, 0},
+		{"valid", []*model.PmsProductLadder{
+			ladder(2, 5, "0.80", "80.00"),
+			ladder(1, 2, "0.90", ""),
+		}, 0},
+		{"no count", []*model.PmsProductLadder{ladder(1, 0, "0.90", "")}, 1},
+		{"duplicate count", []*model.PmsProductLadder{
+			ladder(1, 2, "0.90", ""),
+			ladder(2, 2, "0.80", ""),
+		}, 2},
+		{"zero discount", []*model.PmsProductLadder{ladder(1, 2, "0.00", "")}, 1},
+		{"discount over one", []*model.PmsProductLadder{ladder(1, 2, "1.01", "")}, 1},
+		{"discount rising", []*model.PmsProductLadder{
+			ladder(1, 2, "0.80", ""),
+			ladder(2, 5, "0.90", ""),
+		}, 2},
+		{"price off the discount", []*model.PmsProductLadder{ladder(1, 2, "0.90", "89.00")}, 1},
+	}
+	for _, tt := range tests {
+		err := ValidateLadders(base, tt.ladders)
+		if tt.wantId == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		lerr, ok := err.(*LadderError)
+		if !ok {
+			t.Errorf("%s: error = %v, want a LadderError", tt.name, err)
+			continue
+		}
+		if lerr.LadderId != tt.wantId {
+			t.Errorf("%s: ladder %d reported, want %d", tt.name, lerr.LadderId, tt.wantId)
+		}
+	}
+}
+
+func TestResolveLadder(t *testing.T) {
+	ladders := []*model.PmsProductLadder{
+		ladder(3, 10, "0.70", ""),
+		ladder(1, 2, "0.90", ""),
+		ladder(2, 5, "0.80", ""),
+		ladder(4, 0, "0.50", ""),
+	}
+	tests := []struct {
+		qty    int64
+		wantId int64
+		err    error
+	}{
+		{0, 0, ErrInvalidQuantity},
+		{-1, 0, ErrInvalidQuantity},
+		{1, 0, ErrNoLadder},
+		{2, 1, nil},
+		{4, 1, nil},
+		{5, 2, nil},
+		{9, 2, nil},
+		{10, 3, nil},
+		{1000, 3, nil},
+	}
+	for _, tt := range tests {
+		got, err := ResolveLadder(ladders, tt.qty)
+		if err != tt.err {
+			t.Errorf("qty %d: error = %v, want %v", tt.qty, err, tt.err)
+			continue
+		}
+		if err == nil && got.Id != tt.wantId {
+			t.Errorf("qty %d: ladder %d, want %d", tt.qty, got.Id, tt.wantId)
+		}
+	}
+}
+
+func TestLadderUnitPrice(t *testing.T) {
+	l := ladder(1, 2, "0.85", "85.00")
+	tests := []struct {
+		unit, want string
+	}{
+		{"100.00", "85.00"},
+		// a SKU priced otherwise goes by the discount, not the stored price
+		{"1.99", "1.69"},
+		{"0.10", "0.09"},
+	}
+	for _, tt := range tests {
+		got := LadderUnitPrice(model.MustParseMoney(tt.unit), l)
+		if got.String() != tt.want {
+			t.Errorf("LadderUnitPrice(%s) = %s, want %s", tt.unit, got, tt.want)
+		}
+	}
+
+	if got := LadderUnitPrice(model.MustParseMoney("3.00"), ladder(1, 2, "", "")); got.String() != "3.00" {
+		t.Errorf("LadderUnitPrice without discount = %s, want 3.00", got)
+	}
+}
+
+// The ladder quote and the price preview both go by Quote, which must agree
+// with LadderUnitPrice for every SKU price.
+func TestQuoteLadderMatchesUnitPrice(t *testing.T) {
+	product := &model.PmsProduct{
+		Price:         model.NewNullMoney(model.MustParseMoney("100.00")),
+		PromotionType: sql.NullInt64{Int64: model.PromotionTypeLadder, Valid: true},
+	}
+	l := ladder(1, 3, "0.85", "85.00")
+	sku := &model.PmsSkuStock{Price: model.NewNullMoney(model.MustParseMoney("1.99"))}
+
+	breakdowns, err := Calculator{}.Quote(Line{
+		Product: product,
+		Ladders: []*model.PmsProductLadder{l},
+		Items:   []Item{{Qty: 2}, {Sku: sku, Qty: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range breakdowns {
+		if b.Ladder != l {
+			t.Fatalf("item %d: ladder not applied", i)
+		}
+		want := LadderUnitPrice(b.UnitPrice, l).Mul(b.Qty)
+		if b.Total != want {
+			t.Errorf("item %d: total %s, want %s", i, b.Total, want)
+		}
+	}
+}
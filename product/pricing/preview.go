@@ -0,0 +1,215 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+
+	"malltmp/product/model"
+)
+
+const (
+	MaxPreviewItems = 200
+
+	// errors of a PreviewLine
+	SkuNotFound      = "sku not found"
+	ProductNotOnSale = "product is not on sale"
+)
+
+var (
+	ErrEmptyPreview   = errors.New("items must not be empty")
+	ErrTooManyPreview = fmt.Errorf("at most %d items can be previewed at once", MaxPreviewItems)
+)
+
+type (
+	// PreviewItem is a quantity of a SKU to price.
+	PreviewItem struct {
+		SkuId    int64
+		Quantity int64
+	}
+
+	// PreviewLine is the price of a PreviewItem. Breakdown is nil when the
+	// item can't be priced, Error telling why.
+	PreviewLine struct {
+		SkuId       int64
+		ProductId   int64
+		SkuCode     string
+		ProductName string
+		Quantity    int64
+		Available   int64
+		InStock     bool
+		GiftPoint   int64
+		GiftGrowth  int64
+		Breakdown   *Breakdown
+		Error       string
+	}
+
+	// Preview is the price of a cart, a line per SKU.
+	Preview struct {
+		Lines      []PreviewLine
+		Subtotal   model.Money
+		Saving     model.Money
+		Total      model.Money
+		GiftPoint  int64
+		GiftGrowth int64
+	}
+
+	// Previewer prices carts of SKUs, each product of the cart as a Line.
+	Previewer struct {
+		calculator     Calculator
+		productModel   model.PmsProductModel
+		skuModel       model.PmsSkuStockModel
+		ladderModel    model.PmsProductLadderModel
+		reductionModel model.PmsProductFullReductionModel
+	}
+)
+
+func NewPreviewer(calculator Calculator, productModel model.PmsProductModel, skuModel model.PmsSkuStockModel,
+	ladderModel model.PmsProductLadderModel, reductionModel model.PmsProductFullReductionModel) *Previewer {
+	return &Previewer{
+		calculator:     calculator,
+		productModel:   productModel,
+		skuModel:       skuModel,
+		ladderModel:    ladderModel,
+		reductionModel: reductionModel,
+	}
+}
+
+// Preview prices items, repeated SKUs merged into the line of the first.
+// SKUs not found or of products not on sale get a line with an error.
+func (p *Previewer) Preview(items []PreviewItem) (*Preview, error) {
+	items, err := mergePreviewItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	skuIds := make([]int64, len(items))
+	for i, item := range items {
+		skuIds[i] = item.SkuId
+	}
+	skus, _, err := p.skuModel.FindMany(skuIds)
+	if err != nil {
+		return nil, err
+	}
+	skuById := make(map[int64]*model.PmsSkuStock, len(skus))
+	var productIds []int64
+	for _, sku := range skus {
+		skuById[sku.Id] = sku
+		productIds = append(productIds, sku.ProductId.Int64)
+	}
+
+	products, _, err := p.productModel.FindMany(productIds)
+	if err != nil {
+		return nil, err
+	}
+	ladders, err := p.ladderModel.FindByProductIds(productIds)
+	if err != nil {
+		return nil, err
+	}
+	reductions, err := p.reductionModel.FindByProductIds(productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make(map[int64]*Line, len(products))
+	for _, product := range products {
+		if product.DeleteStatus.Int64 == 1 || product.PublishStatus.Int64 != 1 {
+			continue
+		}
+		lines[product.Id] = &Line{Product: product}
+	}
+	for _, ladder := range ladders {
+		if line, ok := lines[ladder.ProductId.Int64]; ok {
+			line.Ladders = append(line.Ladders, ladder)
+		}
+	}
+	for _, reduction := range reductions {
+		if line, ok := lines[reduction.ProductId.Int64]; ok {
+			line.Reductions = append(line.Reductions, reduction)
+		}
+	}
+
+	preview := &Preview{
+		Lines: make([]PreviewLine, len(items)),
+	}
+	// positions of each line's items in preview.Lines
+	positions := make(map[int64][]int, len(lines))
+	for i, item := range items {
+		out := &preview.Lines[i]
+		out.SkuId = item.SkuId
+		out.Quantity = item.Quantity
+
+		sku, ok := skuById[item.SkuId]
+		if !ok {
+			out.Error = SkuNotFound
+			continue
+		}
+		out.ProductId = sku.ProductId.Int64
+		out.SkuCode = sku.SkuCode
+		out.Available = sku.Stock - sku.LockStock
+		if out.Available < 0 {
+			out.Available = 0
+		}
+
+		line, ok := lines[sku.ProductId.Int64]
+		if !ok {
+			out.Error = ProductNotOnSale
+			continue
+		}
+		out.ProductName = line.Product.Name
+		out.InStock = out.Available >= item.Quantity
+		line.Items = append(line.Items, Item{Sku: sku, Qty: item.Quantity})
+		positions[line.Product.Id] = append(positions[line.Product.Id], i)
+	}
+
+	for productId, line := range lines {
+		if len(line.Items) == 0 {
+			continue
+		}
+
+		breakdowns, err := p.calculator.Quote(*line)
+		if err != nil {
+			return nil, err
+		}
+		for j, b := range breakdowns {
+			out := &preview.Lines[positions[productId][j]]
+			out.Breakdown = b
+			out.GiftPoint = line.Product.GiftPoint * b.Qty
+			out.GiftGrowth = line.Product.GiftGrowth * b.Qty
+
+			preview.Subtotal += b.Subtotal
+			preview.Saving += b.Saving()
+			preview.Total += b.Total
+			preview.GiftPoint += out.GiftPoint
+			preview.GiftGrowth += out.GiftGrowth
+		}
+	}
+
+	return preview, nil
+}
+
+// mergePreviewItems sums the quantities of repeated SKUs, keeping the first position.
+func mergePreviewItems(items []PreviewItem) ([]PreviewItem, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyPreview
+	}
+	if len(items) > MaxPreviewItems {
+		return nil, ErrTooManyPreview
+	}
+
+	merged := make([]PreviewItem, 0, len(items))
+	index := make(map[int64]int, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+
+		if i, ok := index[item.SkuId]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.SkuId] = len(merged)
+		merged = append(merged, item)
+	}
+
+	return merged, nil
+}
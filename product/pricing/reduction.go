@@ -0,0 +1,127 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+
+	"malltmp/product/model"
+)
+
+// ReductionMode decides how full-reduction (满减) thresholds are applied.
+type ReductionMode int
+
+const (
+	// ReductionBest applies the single best threshold reached, 满X减Y.
+	ReductionBest ReductionMode = iota
+	// ReductionRepeat applies a threshold once for every X reached, 每满X减Y.
+	ReductionRepeat
+)
+
+var ErrNoReduction = errors.New("no full reduction applies")
+
+// ReductionError reports why a product's full-reduction set is inconsistent.
+type ReductionError struct {
+	ReductionId int64
+	Reason      string
+}
+
+func (e *ReductionError) Error() string {
+	return fmt.Sprintf("full reduction %d: %s", e.ReductionId, e.Reason)
+}
+
+// ReductionResult is the full-reduction applied to a subtotal.
+type ReductionResult struct {
+	ReductionId int64
+	FullPrice   model.Money
+	ReducePrice model.Money
+	Times       int64
+	Saving      model.Money
+}
+
+// ParseReductionMode parses the config value of a ReductionMode, "best" or "repeat".
+func ParseReductionMode(s string) (ReductionMode, error) {
+	switch s {
+	case "", "best":
+		return ReductionBest, nil
+	case "repeat":
+		return ReductionRepeat, nil
+	default:
+		return 0, fmt.Errorf("unknown full reduction mode %q", s)
+	}
+}
+
+func (m ReductionMode) String() string {
+	switch m {
+	case ReductionBest:
+		return "best"
+	case ReductionRepeat:
+		return "repeat"
+	default:
+		return fmt.Sprintf("ReductionMode(%d)", int(m))
+	}
+}
+
+// ValidateReductions checks that every threshold is positive and unique, and
+// that a reduction never reaches its threshold.
+func ValidateReductions(reductions []*model.PmsProductFullReduction) error {
+	seen := make(map[model.Money]int64, len(reductions))
+	for _, r := range reductions {
+		if !r.FullPrice.Valid || r.FullPrice.Money <= 0 {
+			return &ReductionError{ReductionId: r.Id, Reason: "full price must be positive"}
+		}
+		if !r.ReducePrice.Valid || r.ReducePrice.Money <= 0 {
+			return &ReductionError{ReductionId: r.Id, Reason: "reduce price must be positive"}
+		}
+		if r.ReducePrice.Money >= r.FullPrice.Money {
+			return &ReductionError{ReductionId: r.Id, Reason: fmt.Sprintf("reduce price %s exceeds full price %s",
+				r.ReducePrice.Money, r.FullPrice.Money)}
+		}
+		if id, ok := seen[r.FullPrice.Money]; ok {
+			return &ReductionError{ReductionId: r.Id, Reason: fmt.Sprintf("full price %s duplicates full reduction %d",
+				r.FullPrice.Money, id)}
+		}
+
+		seen[r.FullPrice.Money] = r.Id
+	}
+
+	return nil
+}
+
+// EvaluateReduction picks the reduction giving the largest saving on subtotal.
+// Invalid rows are skipped, so run ValidateReductions to report them.
+func EvaluateReduction(mode ReductionMode, reductions []*model.PmsProductFullReduction,
+	subtotal model.Money) (*ReductionResult, error) {
+	var best *ReductionResult
+	for _, r := range reductions {
+		if !r.FullPrice.Valid || !r.ReducePrice.Valid || r.FullPrice.Money <= 0 ||
+			r.ReducePrice.Money <= 0 || r.ReducePrice.Money >= r.FullPrice.Money {
+			continue
+		}
+		if subtotal < r.FullPrice.Money {
+			continue
+		}
+
+		times := int64(1)
+		if mode == ReductionRepeat {
+			times = int64(subtotal / r.FullPrice.Money)
+		}
+		saving := r.ReducePrice.Money.Mul(times)
+		if saving > subtotal {
+			saving = subtotal
+		}
+		if best == nil || saving > best.Saving {
+			best = &ReductionResult{
+				ReductionId: r.Id,
+				FullPrice:   r.FullPrice.Money,
+				ReducePrice: r.ReducePrice.Money,
+				Times:       times,
+				Saving:      saving,
+			}
+		}
+	}
+	if best == nil {
+		return nil, ErrNoReduction
+	}
+
+	return best, nil
+}
@@ -0,0 +1,97 @@
+package pricing
+
+import (
+	"testing"
+
+	"malltmp/product/model"
+)
+
+func reduction(id int64, full, reduce string) *model.PmsProductFullReduction {
+	return &model.PmsProductFullReduction{
+		Id:          id,
+		FullPrice:   model.NewNullMoney(model.MustParseMoney(full)),
+		ReducePrice: model.NewNullMoney(model.MustParseMoney(reduce)),
+	}
+}
+
+func TestParseReductionMode(t *testing.T) {
+	for in, want := range map[string]ReductionMode{"": ReductionBest, "best": ReductionBest, "repeat": ReductionRepeat} {
+		got, err := ParseReductionMode(in)
+		if err != nil || got != want {
+			t.Errorf("ParseReductionMode(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseReductionMode("stack"); err == nil {
+		t.Error("ParseReductionMode(stack) should fail")
+	}
+}
+
+func TestValidateReductions(t *testing.T) {
+	tests := []struct {
+		name       string
+		reductions []*model.PmsProductFullReduction
+		wantId     int64
+	}{
+		{"valid", []*model.PmsProductFullReduction{reduction(1, "100", "10"), reduction(2, "200", "30")}, 0},
+		{"zero full price", []*model.PmsProductFullReduction{reduction(1, "0", "10")}, 1},
+		{"zero reduce price", []*model.PmsProductFullReduction{reduction(1, "100", "0")}, 1},
+		{"reduce reaches full", []*model.PmsProductFullReduction{reduction(1, "100", "100")}, 1},
+		{"duplicate full price", []*model.PmsProductFullReduction{reduction(1, "100", "10"),
+			reduction(2, "100.00", "20")}, 2},
+	}
+	for _, tt := range tests {
+		err := ValidateReductions(tt.reductions)
+		if tt.wantId == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		rerr, ok := err.(*ReductionError)
+		if !ok || rerr.ReductionId != tt.wantId {
+			t.Errorf("%s: error = %v, want full reduction %d", tt.name, err, tt.wantId)
+		}
+	}
+}
+
+func TestEvaluateReduction(t *testing.T) {
+	reductions := []*model.PmsProductFullReduction{
+		reduction(1, "100", "10"),
+		reduction(2, "200", "30"),
+		reduction(3, "50", "60"), // invalid, skipped
+	}
+	tests := []struct {
+		mode       ReductionMode
+		subtotal   string
+		wantId     int64
+		wantTimes  int64
+		wantSaving string
+	}{
+		{ReductionBest, "99.99", 0, 0, ""},
+		{ReductionBest, "100", 1, 1, "10.00"},
+		{ReductionBest, "250", 2, 1, "30.00"},
+		{ReductionBest, "450", 2, 1, "30.00"},
+		{ReductionRepeat, "250", 2, 1, "30.00"},
+		{ReductionRepeat, "399.99", 1, 3, "30.00"},
+		{ReductionRepeat, "400", 2, 2, "60.00"},
+		{ReductionRepeat, "500", 2, 2, "60.00"},
+		{ReductionRepeat, "300", 1, 3, "30.00"}, // a tie keeps the first
+	}
+	for _, tt := range tests {
+		got, err := EvaluateReduction(tt.mode, reductions, model.MustParseMoney(tt.subtotal))
+		if tt.wantId == 0 {
+			if err != ErrNoReduction {
+				t.Errorf("%v %s: error = %v, want ErrNoReduction", tt.mode, tt.subtotal, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v %s: unexpected error %v", tt.mode, tt.subtotal, err)
+			continue
+		}
+		if got.ReductionId != tt.wantId || got.Times != tt.wantTimes || got.Saving.String() != tt.wantSaving {
+			t.Errorf("%v %s: got %+v, want reduction %d × %d saving %s", tt.mode, tt.subtotal, got,
+				tt.wantId, tt.wantTimes, tt.wantSaving)
+		}
+	}
+}
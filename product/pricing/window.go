@@ -0,0 +1,126 @@
+package pricing
+
+import (
+	"errors"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/syncx"
+)
+
+const windowBatch = 500
+
+var (
+	ErrInvalidWindow        = errors.New("promotion window must end after it starts")
+	ErrInvalidPromotionType = errors.New("invalid promotion type")
+)
+
+// PromotionWindowWorker switches products to the promotion type of their
+// window when it starts, and back to the previous one when it ends.
+type PromotionWindowWorker struct {
+	productModel model.PmsProductModel
+	interval     time.Duration
+	done         *syncx.DoneChan
+}
+
+// PromotionTypeAt returns the promotion type of the product at t. Inside its
+// window that is the window's, outside the one before the window started, so
+// a late worker doesn't leave a promotion on, or off, past its boundary.
+func PromotionTypeAt(product *model.PmsProduct, t time.Time) int64 {
+	if !product.WindowPromotionType.Valid || !product.PromotionStartTime.Valid || !product.PromotionEndTime.Valid {
+		return product.PromotionType.Int64
+	}
+
+	if !t.Before(product.PromotionStartTime.Time) && t.Before(product.PromotionEndTime.Time) {
+		return product.WindowPromotionType.Int64
+	}
+	if product.PreviousPromotionType.Valid {
+		// the worker started the window and hasn't ended it yet
+		return product.PreviousPromotionType.Int64
+	}
+
+	return product.PromotionType.Int64
+}
+
+// ValidateWindow checks a promotion window before it is set.
+func ValidateWindow(promotionType int64, start, end time.Time) error {
+	if promotionType < model.PromotionTypeNone || promotionType > model.PromotionTypeFlashSale {
+		return ErrInvalidPromotionType
+	}
+	if !end.After(start) {
+		return ErrInvalidWindow
+	}
+
+	return nil
+}
+
+func NewPromotionWindowWorker(productModel model.PmsProductModel, interval time.Duration) *PromotionWindowWorker {
+	return &PromotionWindowWorker{
+		productModel: productModel,
+		interval:     interval,
+		done:         syncx.NewDoneChan(),
+	}
+}
+
+// Start applies the due windows right away, then every interval until Stop is called.
+func (w *PromotionWindowWorker) Start() {
+	w.run()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.run()
+		case <-w.done.Done():
+			return
+		}
+	}
+}
+
+func (w *PromotionWindowWorker) Stop() {
+	w.done.Close()
+}
+
+// Apply starts and ends the promotion windows due at now, returning how many.
+func (w *PromotionWindowWorker) Apply(now time.Time) (started, ended int, err error) {
+	var lastId int64
+	for {
+		products, err := w.productModel.FindPromotionWindowDue(now, lastId, windowBatch)
+		if err != nil {
+			return started, ended, err
+		}
+
+		for _, product := range products {
+			if product.PreviousPromotionType.Valid {
+				if err := w.productModel.EndPromotionWindow(nil, product.Id); err != nil {
+					return started, ended, err
+				}
+				ended++
+			} else {
+				if err := w.productModel.StartPromotionWindow(nil, product.Id); err != nil {
+					return started, ended, err
+				}
+				started++
+			}
+		}
+
+		if len(products) < windowBatch {
+			return started, ended, nil
+		}
+		lastId = products[len(products)-1].Id
+	}
+}
+
+func (w *PromotionWindowWorker) run() {
+	started, ended, err := w.Apply(time.Now())
+	if err != nil {
+		logx.Errorf("apply promotion windows failed: %v", err)
+	}
+	if started > 0 || ended > 0 {
+		logx.Infof("promotion windows started %d, ended %d", started, ended)
+	}
+}
@@ -0,0 +1,144 @@
+package pricing
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+type fakeWindowProductModel struct {
+	model.PmsProductModel
+	rows    map[int64]model.PmsProduct
+	started []int64
+	ended   []int64
+}
+
+func (m *fakeWindowProductModel) FindPromotionWindowDue(now time.Time, lastId int64, limit int) ([]*model.PmsProduct, error) {
+	var resp []*model.PmsProduct
+	for id := lastId + 1; id <= int64(len(m.rows)) && len(resp) < limit; id++ {
+		p := m.rows[id]
+		started := p.PreviousPromotionType.Valid
+		if !started && !now.Before(p.PromotionStartTime.Time) && now.Before(p.PromotionEndTime.Time) ||
+			started && !now.Before(p.PromotionEndTime.Time) {
+			resp = append(resp, &p)
+		}
+	}
+	return resp, nil
+}
+
+func (m *fakeWindowProductModel) StartPromotionWindow(_ sqlx.Session, id int64) error {
+	m.started = append(m.started, id)
+	return nil
+}
+
+func (m *fakeWindowProductModel) EndPromotionWindow(_ sqlx.Session, id int64) error {
+	m.ended = append(m.ended, id)
+	return nil
+}
+
+func windowProduct(promotionType int64, start, end time.Time, previous *int64) *model.PmsProduct {
+	p := &model.PmsProduct{
+		PromotionType:       sql.NullInt64{Int64: promotionType, Valid: true},
+		WindowPromotionType: sql.NullInt64{Int64: model.PromotionTypeFlashSale, Valid: true},
+		PromotionStartTime:  sql.NullTime{Time: start, Valid: true},
+		PromotionEndTime:    sql.NullTime{Time: end, Valid: true},
+	}
+	if previous != nil {
+		p.PreviousPromotionType = sql.NullInt64{Int64: *previous, Valid: true}
+	}
+	return p
+}
+
+func TestPromotionTypeAt(t *testing.T) {
+	start := time.Date(2021, 3, 20, 10, 0, 0, 0, time.Local)
+	end := start.Add(2 * time.Hour)
+	ladder := int64(model.PromotionTypeLadder)
+
+	tests := []struct {
+		name    string
+		product *model.PmsProduct
+		at      time.Time
+		want    int64
+	}{
+		{"no window", &model.PmsProduct{
+			PromotionType: sql.NullInt64{Int64: model.PromotionTypeLadder, Valid: true},
+		}, start, model.PromotionTypeLadder},
+		{"window without end", &model.PmsProduct{
+			PromotionType:       sql.NullInt64{Int64: model.PromotionTypeLadder, Valid: true},
+			WindowPromotionType: sql.NullInt64{Int64: model.PromotionTypeFlashSale, Valid: true},
+			PromotionStartTime:  sql.NullTime{Time: start, Valid: true},
+		}, start, model.PromotionTypeLadder},
+		{"before the window", windowProduct(ladder, start, end, nil), start.Add(-time.Second),
+			model.PromotionTypeLadder},
+		// the worker is late to start the window
+		{"window start", windowProduct(ladder, start, end, nil), start, model.PromotionTypeFlashSale},
+		{"window started", windowProduct(model.PromotionTypeFlashSale, start, end, &ladder), start.Add(time.Hour),
+			model.PromotionTypeFlashSale},
+		// the worker is late to end the window
+		{"window end", windowProduct(model.PromotionTypeFlashSale, start, end, &ladder), end,
+			model.PromotionTypeLadder},
+		{"window ended", windowProduct(ladder, start, end, nil), end.Add(time.Hour), model.PromotionTypeLadder},
+	}
+	for _, tt := range tests {
+		if got := PromotionTypeAt(tt.product, tt.at); got != tt.want {
+			t.Errorf("%s: PromotionTypeAt = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateWindow(t *testing.T) {
+	start := time.Date(2021, 3, 20, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		name          string
+		promotionType int64
+		end           time.Time
+		want          error
+	}{
+		{"valid", model.PromotionTypeFlashSale, start.Add(time.Hour), nil},
+		{"no promotion", model.PromotionTypeNone, start.Add(time.Hour), nil},
+		{"negative type", -1, start.Add(time.Hour), ErrInvalidPromotionType},
+		{"unknown type", model.PromotionTypeFlashSale + 1, start.Add(time.Hour), ErrInvalidPromotionType},
+		{"empty window", model.PromotionTypeFlashSale, start, ErrInvalidWindow},
+		{"reversed window", model.PromotionTypeFlashSale, start.Add(-time.Hour), ErrInvalidWindow},
+	}
+	for _, tt := range tests {
+		if err := ValidateWindow(tt.promotionType, start, tt.end); err != tt.want {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestPromotionWindowWorkerApply(t *testing.T) {
+	now := time.Date(2021, 3, 20, 10, 0, 0, 0, time.Local)
+	ladder := int64(model.PromotionTypeLadder)
+	products := &fakeWindowProductModel{rows: make(map[int64]model.PmsProduct)}
+	for id, p := range []*model.PmsProduct{
+		windowProduct(ladder, now.Add(-time.Hour), now.Add(time.Hour), nil),                           // to start
+		windowProduct(ladder, now.Add(time.Hour), now.Add(2*time.Hour), nil),                          // not yet
+		windowProduct(model.PromotionTypeFlashSale, now.Add(-2*time.Hour), now, &ladder),              // to end
+		windowProduct(model.PromotionTypeFlashSale, now.Add(-time.Hour), now.Add(time.Hour), &ladder), // running
+		windowProduct(ladder, now, now.Add(time.Hour), nil),                                           // to start
+	} {
+		p.Id = int64(id + 1)
+		products.rows[p.Id] = *p
+	}
+
+	w := NewPromotionWindowWorker(products, time.Minute)
+	started, ended, err := w.Apply(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started != 2 || ended != 1 {
+		t.Errorf("started %d, ended %d, want 2 and 1", started, ended)
+	}
+	if len(products.started) != 2 || products.started[0] != 1 || products.started[1] != 5 {
+		t.Errorf("started %v, want [1 5]", products.started)
+	}
+	if len(products.ended) != 1 || products.ended[0] != 3 {
+		t.Errorf("ended %v, want [3]", products.ended)
+	}
+}
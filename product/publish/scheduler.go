@@ -0,0 +1,210 @@
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+	"github.com/tal-tech/go-zero/core/syncx"
+)
+
+const (
+	scheduleBatch     = 100
+	operatorScheduler = "scheduler"
+)
+
+var (
+	ErrScheduleAction   = errors.New("only publish and unpublish can be scheduled")
+	ErrSchedulePassed   = errors.New("schedule time has passed")
+	ErrScheduleFinished = errors.New("schedule is already applied or failed")
+)
+
+// Scheduler applies publish and unpublish at a given time. Schedules are kept
+// in pms_product_publish_schedule, so they survive restarts, and each is
+// applied at most once, even with several instances running.
+type Scheduler struct {
+	conn          sqlx.SqlConn
+	workflow      *Workflow
+	productModel  model.PmsProductModel
+	scheduleModel model.PmsProductPublishScheduleModel
+	interval      time.Duration
+	done          *syncx.DoneChan
+}
+
+func NewScheduler(conn sqlx.SqlConn, workflow *Workflow, productModel model.PmsProductModel,
+	scheduleModel model.PmsProductPublishScheduleModel, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		conn:          conn,
+		workflow:      workflow,
+		productModel:  productModel,
+		scheduleModel: scheduleModel,
+		interval:      interval,
+		done:          syncx.NewDoneChan(),
+	}
+}
+
+// Schedule makes action, ActionPublish or ActionUnpublish, happen to the product at.
+func (s *Scheduler) Schedule(productId int64, action string, at time.Time,
+	operator string) (*model.PmsProductPublishSchedule, error) {
+	if action != ActionPublish && action != ActionUnpublish {
+		return nil, ErrScheduleAction
+	}
+	if !at.After(time.Now()) {
+		return nil, ErrSchedulePassed
+	}
+	if _, err := s.productModel.FindOne(productId); err != nil {
+		return nil, err
+	}
+
+	schedule := model.PmsProductPublishSchedule{
+		ProductId:    productId,
+		Action:       action,
+		ScheduleTime: at,
+		Status:       model.ScheduleStatusPending,
+		Operator:     operator,
+	}
+	ret, err := s.scheduleModel.Insert(schedule)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Id, err = ret.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// Cancel keeps a pending schedule from being applied. Cancelling twice is a no-op.
+func (s *Scheduler) Cancel(id int64) error {
+	return s.conn.Transact(func(session sqlx.Session) error {
+		schedule, err := s.scheduleModel.TxFindOneForUpdate(session, id)
+		if err != nil {
+			return err
+		}
+
+		switch schedule.Status {
+		case model.ScheduleStatusCancelled:
+			return nil
+		case model.ScheduleStatusPending:
+			return s.scheduleModel.TxUpdateStatus(session, id, model.ScheduleStatusPending,
+				model.ScheduleStatusCancelled, "")
+		default:
+			return ErrScheduleFinished
+		}
+	})
+}
+
+// Upcoming pages through the pending schedules of a product, or of all
+// products when productId is 0, earliest first.
+func (s *Scheduler) Upcoming(productId, lastId int64, limit int) ([]*model.PmsProductPublishSchedule, error) {
+	return s.scheduleModel.FindPending(productId, lastId, limit)
+}
+
+// ApplyDue applies the due schedules, a batch at a time, returning how many.
+// A schedule failing to apply is logged and passed, not to hold back the ones
+// after it, and tried again by the next call if still pending.
+func (s *Scheduler) ApplyDue() (int, error) {
+	var (
+		lastId  int64
+		applied int
+	)
+	now := time.Now()
+	for {
+		schedules, err := s.scheduleModel.FindDue(now, lastId, scheduleBatch)
+		if err != nil {
+			return applied, err
+		}
+
+		for _, schedule := range schedules {
+			lastId = schedule.Id
+			if err := s.apply(schedule.Id); err != nil {
+				logx.Errorf("apply publish schedule %d failed: %v", schedule.Id, err)
+				continue
+			}
+			applied++
+		}
+
+		if len(schedules) < scheduleBatch {
+			return applied, nil
+		}
+	}
+}
+
+// Start applies due schedules right away, catching up on those missed while
+// stopped, then every interval until Stop is called.
+func (s *Scheduler) Start() {
+	s.applyAll()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.applyAll()
+		case <-s.done.Done():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) Stop() {
+	s.done.Close()
+}
+
+// apply runs the transition and marks the schedule done in one transaction.
+// A schedule whose transition doesn't apply to the product any more,
+// or whose product is gone, is marked failed. Other errors leave it pending
+// to be retried.
+func (s *Scheduler) apply(id int64) error {
+	err := s.conn.Transact(func(session sqlx.Session) error {
+		schedule, err := s.scheduleModel.TxFindOneForUpdate(session, id)
+		if err != nil {
+			return err
+		}
+		if schedule.Status != model.ScheduleStatusPending {
+			// cancelled meanwhile, or applied by another instance
+			return nil
+		}
+
+		operator := schedule.Operator
+		if len(operator) == 0 {
+			operator = operatorScheduler
+		}
+		_, err = s.workflow.TxTransition(session, schedule.ProductId, schedule.Action, operator,
+			fmt.Sprintf("schedule %d", schedule.Id))
+		if err != nil {
+			return err
+		}
+
+		return s.scheduleModel.TxUpdateStatus(session, id, model.ScheduleStatusPending,
+			model.ScheduleStatusDone, "")
+	})
+
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(*TransitionError); ok || err == model.ErrNotFound {
+		if e := s.scheduleModel.TxUpdateStatus(s.conn, id, model.ScheduleStatusPending,
+			model.ScheduleStatusFailed, err.Error()); e != nil {
+			return e
+		}
+	}
+	return err
+}
+
+func (s *Scheduler) applyAll() {
+	n, err := s.ApplyDue()
+	if err != nil {
+		logx.Errorf("apply publish schedules failed: %v", err)
+	}
+	if n > 0 {
+		logx.Infof("applied %d publish schedules", n)
+	}
+}
@@ -0,0 +1,238 @@
+package publish
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+// states of a product, derived from its verify, publish and preview status
+const (
+	StateDraft     = "draft"
+	StatePending   = "pending"
+	StateRejected  = "rejected"
+	StateApproved  = "approved" // approved but not on sale, never published or unpublished
+	StatePublished = "published"
+	StatePreview   = "preview"
+)
+
+// actions that move a product between states
+const (
+	ActionSubmit    = "submit"
+	ActionApprove   = "approve"
+	ActionReject    = "reject"
+	ActionPublish   = "publish"
+	ActionUnpublish = "unpublish"
+	ActionPreview   = "preview"
+)
+
+var (
+	ErrUnknownAction  = errors.New("unknown action")
+	ErrReasonRequired = errors.New("reason is required to reject")
+
+	// transitions maps an action to the states it applies to and the state it leads to.
+	transitions = map[string]transition{
+		ActionSubmit:    {from: []string{StateDraft, StateRejected}, to: StatePending},
+		ActionApprove:   {from: []string{StatePending}, to: StateApproved},
+		ActionReject:    {from: []string{StatePending}, to: StateRejected},
+		ActionPublish:   {from: []string{StateApproved, StatePreview}, to: StatePublished},
+		ActionUnpublish: {from: []string{StatePublished, StatePreview}, to: StateApproved},
+		ActionPreview:   {from: []string{StateApproved}, to: StatePreview},
+	}
+
+	// statuses are the verify, publish and preview status of each state.
+	statuses = map[string]status{
+		StateDraft:     {verify: model.VerifyStatusDraft},
+		StatePending:   {verify: model.VerifyStatusPending},
+		StateRejected:  {verify: model.VerifyStatusRejected},
+		StateApproved:  {verify: model.VerifyStatusApproved},
+		StatePublished: {verify: model.VerifyStatusApproved, publish: 1},
+		StatePreview:   {verify: model.VerifyStatusApproved, preview: 1},
+	}
+)
+
+type (
+	// TransitionError tells that an action doesn't apply to the product's state.
+	TransitionError struct {
+		Action string
+		State  string
+	}
+
+	// Workflow moves products through review and publishing, recording every
+	// transition. Only approved products can be published or previewed.
+	Workflow struct {
+		conn         sqlx.SqlConn
+		productModel model.PmsProductModel
+		recordModel  model.PmsProductVerifyRecordModel
+	}
+
+	transition struct {
+		from []string
+		to   string
+	}
+
+	status struct {
+		verify  int64
+		publish int64
+		preview int64
+	}
+
+	// guardedProductModel keeps blind writes from changing the workflow state.
+	guardedProductModel struct {
+		model.PmsProductModel
+		conn sqlx.SqlConn
+	}
+)
+
+func NewWorkflow(conn sqlx.SqlConn, productModel model.PmsProductModel,
+	recordModel model.PmsProductVerifyRecordModel) *Workflow {
+	return &Workflow{
+		conn:         conn,
+		productModel: productModel,
+		recordModel:  recordModel,
+	}
+}
+
+// StateOf returns the state of a product.
+func StateOf(product *model.PmsProduct) string {
+	switch product.VerifyStatus.Int64 {
+	case model.VerifyStatusPending:
+		return StatePending
+	case model.VerifyStatusRejected:
+		return StateRejected
+	case model.VerifyStatusApproved:
+		switch {
+		case product.PublishStatus.Int64 == 1:
+			return StatePublished
+		case product.PreviewStatus.Int64 == 1:
+			return StatePreview
+		default:
+			return StateApproved
+		}
+	default:
+		return StateDraft
+	}
+}
+
+// Transition applies action to the product and returns its new state.
+// The reason is required to reject, and recorded as the detail of the others.
+func (wf *Workflow) Transition(id int64, action, operator, reason string) (string, error) {
+	var state string
+	err := wf.conn.Transact(func(session sqlx.Session) error {
+		var err error
+		state, err = wf.TxTransition(session, id, action, operator, reason)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return state, nil
+}
+
+// TxTransition is Transition within the transaction of session.
+func (wf *Workflow) TxTransition(session sqlx.Session, id int64, action, operator, reason string) (string, error) {
+	t, ok := transitions[action]
+	if !ok {
+		return "", ErrUnknownAction
+	}
+	if action == ActionReject && len(reason) == 0 {
+		return "", ErrReasonRequired
+	}
+
+	product, err := wf.productModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return "", err
+	}
+
+	from := StateOf(product)
+	if !contains(t.from, from) {
+		return "", &TransitionError{Action: action, State: from}
+	}
+
+	s := statuses[t.to]
+	if err := wf.productModel.TxUpdateStatus(session, id, s.verify, s.publish, s.preview); err != nil {
+		return "", err
+	}
+	_, err = wf.recordModel.TxInsert(session, model.PmsProductVerifyRecord{
+		ProductId: id,
+		Action:    action,
+		FromState: from,
+		ToState:   t.to,
+		Detail:    reason,
+		Operator:  operator,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return t.to, nil
+}
+
+// History pages through the transitions of a product, oldest first.
+func (wf *Workflow) History(productId, lastId int64, limit int) ([]*model.PmsProductVerifyRecord, error) {
+	return wf.recordModel.FindByProductId(productId, lastId, limit)
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot %s a product in %s state", e.Action, e.State)
+}
+
+// NewGuardedProductModel wraps productModel so that inserts create drafts, and
+// updates keep the stored verify, publish and preview status, read locked in
+// the transaction of the update. Those only change through Workflow.
+func NewGuardedProductModel(conn sqlx.SqlConn, productModel model.PmsProductModel) model.PmsProductModel {
+	return &guardedProductModel{
+		PmsProductModel: productModel,
+		conn:            conn,
+	}
+}
+
+func (m *guardedProductModel) Insert(data model.PmsProduct) (sql.Result, error) {
+	return m.PmsProductModel.Insert(draft(data))
+}
+
+func (m *guardedProductModel) TxInsert(session sqlx.Session, data model.PmsProduct) (sql.Result, error) {
+	return m.PmsProductModel.TxInsert(session, draft(data))
+}
+
+func (m *guardedProductModel) Update(data model.PmsProduct) error {
+	return m.conn.Transact(func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *guardedProductModel) TxUpdate(session sqlx.Session, data model.PmsProduct) error {
+	// locked, a transition can't land between the read and the update
+	old, err := m.PmsProductModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+
+	data.VerifyStatus = old.VerifyStatus
+	data.PublishStatus = old.PublishStatus
+	data.PreviewStatus = old.PreviewStatus
+	return m.PmsProductModel.TxUpdate(session, data)
+}
+
+func draft(data model.PmsProduct) model.PmsProduct {
+	s := statuses[StateDraft]
+	data.VerifyStatus = sql.NullInt64{Int64: s.verify, Valid: true}
+	data.PublishStatus = sql.NullInt64{Int64: s.publish, Valid: true}
+	data.PreviewStatus = sql.NullInt64{Int64: s.preview, Valid: true}
+	return data
+}
+
+func contains(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,404 @@
+package publish
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+var errBroken = errors.New("broken")
+
+type (
+	fakeConn struct {
+		sqlx.SqlConn
+	}
+
+	fakeSession struct {
+		sqlx.Session
+	}
+
+	fakeResult int64
+
+	fakeProductModel struct {
+		model.PmsProductModel
+		rows     map[int64]model.PmsProduct
+		inserted []model.PmsProduct
+		// products failing to load, as if the database were down
+		broken map[int64]bool
+		// the sessions products were locked and updated in
+		locked  []sqlx.Session
+		updated []sqlx.Session
+	}
+
+	fakeRecordModel struct {
+		model.PmsProductVerifyRecordModel
+		records []model.PmsProductVerifyRecord
+	}
+
+	fakeScheduleModel struct {
+		model.PmsProductPublishScheduleModel
+		rows map[int64]model.PmsProductPublishSchedule
+	}
+)
+
+func (c fakeConn) Transact(fn func(session sqlx.Session) error) error {
+	return fn(&fakeSession{})
+}
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	return int64(r), nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+func (m *fakeProductModel) FindOne(id int64) (*model.PmsProduct, error) {
+	return m.TxFindOneForUpdate(nil, id)
+}
+
+func (m *fakeProductModel) TxFindOneForUpdate(session sqlx.Session, id int64) (*model.PmsProduct, error) {
+	m.locked = append(m.locked, session)
+	if m.broken[id] {
+		return nil, errBroken
+	}
+	row, ok := m.rows[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &row, nil
+}
+
+func (m *fakeProductModel) Insert(data model.PmsProduct) (sql.Result, error) {
+	return m.TxInsert(nil, data)
+}
+
+func (m *fakeProductModel) TxInsert(_ sqlx.Session, data model.PmsProduct) (sql.Result, error) {
+	m.inserted = append(m.inserted, data)
+	return fakeResult(len(m.inserted)), nil
+}
+
+func (m *fakeProductModel) TxUpdate(session sqlx.Session, data model.PmsProduct) error {
+	m.updated = append(m.updated, session)
+	m.rows[data.Id] = data
+	return nil
+}
+
+func (m *fakeProductModel) TxUpdateStatus(_ sqlx.Session, id, verify, publish, preview int64) error {
+	row := m.rows[id]
+	row.VerifyStatus = sql.NullInt64{Int64: verify, Valid: true}
+	row.PublishStatus = sql.NullInt64{Int64: publish, Valid: true}
+	row.PreviewStatus = sql.NullInt64{Int64: preview, Valid: true}
+	m.rows[id] = row
+	return nil
+}
+
+func (m *fakeRecordModel) TxInsert(_ sqlx.Session, data model.PmsProductVerifyRecord) (sql.Result, error) {
+	m.records = append(m.records, data)
+	return fakeResult(len(m.records)), nil
+}
+
+func (m *fakeScheduleModel) Insert(data model.PmsProductPublishSchedule) (sql.Result, error) {
+	data.Id = int64(len(m.rows) + 1)
+	m.rows[data.Id] = data
+	return fakeResult(data.Id), nil
+}
+
+func (m *fakeScheduleModel) FindDue(now time.Time, lastId int64, limit int) ([]*model.PmsProductPublishSchedule, error) {
+	var due []*model.PmsProductPublishSchedule
+	for id := range m.rows {
+		row := m.rows[id]
+		if row.Status == model.ScheduleStatusPending && !row.ScheduleTime.After(now) {
+			due = append(due, &row)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].ScheduleTime.Before(due[j].ScheduleTime) ||
+			due[i].ScheduleTime.Equal(due[j].ScheduleTime) && due[i].Id < due[j].Id
+	})
+
+	var resp []*model.PmsProductPublishSchedule
+	last, passed := m.rows[lastId], lastId == 0
+	for _, row := range due {
+		if !passed {
+			passed = row.ScheduleTime.After(last.ScheduleTime) ||
+				row.ScheduleTime.Equal(last.ScheduleTime) && row.Id > lastId
+		}
+		if passed && len(resp) < limit {
+			resp = append(resp, row)
+		}
+	}
+	return resp, nil
+}
+
+func (m *fakeScheduleModel) TxFindOneForUpdate(_ sqlx.Session, id int64) (*model.PmsProductPublishSchedule, error) {
+	row, ok := m.rows[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &row, nil
+}
+
+func (m *fakeScheduleModel) TxUpdateStatus(_ sqlx.Session, id, from, to int64, detail string) error {
+	row := m.rows[id]
+	if row.Status != from {
+		return model.ErrScheduleStatus
+	}
+	row.Status, row.Detail = to, detail
+	m.rows[id] = row
+	return nil
+}
+
+func productIn(state string) model.PmsProduct {
+	s := statuses[state]
+	return model.PmsProduct{
+		Id:            1,
+		VerifyStatus:  sql.NullInt64{Int64: s.verify, Valid: true},
+		PublishStatus: sql.NullInt64{Int64: s.publish, Valid: true},
+		PreviewStatus: sql.NullInt64{Int64: s.preview, Valid: true},
+	}
+}
+
+func TestStateOf(t *testing.T) {
+	for state := range statuses {
+		p := productIn(state)
+		if got := StateOf(&p); got != state {
+			t.Errorf("StateOf(%s) = %s", state, got)
+		}
+	}
+
+	if got := StateOf(&model.PmsProduct{}); got != StateDraft {
+		t.Errorf("StateOf of a product without status = %s, want draft", got)
+	}
+}
+
+func TestTransition(t *testing.T) {
+	states := []string{StateDraft, StatePending, StateRejected, StateApproved, StatePublished, StatePreview}
+	tests := []struct {
+		action string
+		to     string
+		from   []string
+	}{
+		{ActionSubmit, StatePending, []string{StateDraft, StateRejected}},
+		{ActionApprove, StateApproved, []string{StatePending}},
+		{ActionReject, StateRejected, []string{StatePending}},
+		{ActionPublish, StatePublished, []string{StateApproved, StatePreview}},
+		{ActionUnpublish, StateApproved, []string{StatePublished, StatePreview}},
+		{ActionPreview, StatePreview, []string{StateApproved}},
+	}
+
+	for _, tt := range tests {
+		for _, from := range states {
+			products := &fakeProductModel{rows: map[int64]model.PmsProduct{1: productIn(from)}}
+			records := &fakeRecordModel{}
+			wf := NewWorkflow(fakeConn{}, products, records)
+
+			state, err := wf.Transition(1, tt.action, "alice", "looks good")
+			if !contains(tt.from, from) {
+				if te, ok := err.(*TransitionError); !ok || te.Action != tt.action || te.State != from {
+					t.Errorf("%s from %s: error = %v, want a TransitionError", tt.action, from, err)
+				}
+				product := products.rows[1]
+				if len(records.records) != 0 || StateOf(&product) != from {
+					t.Errorf("%s from %s: product moved or recorded", tt.action, from)
+				}
+				continue
+			}
+
+			if err != nil {
+				t.Errorf("%s from %s: %v", tt.action, from, err)
+				continue
+			}
+			product := products.rows[1]
+			if state != tt.to || StateOf(&product) != tt.to {
+				t.Errorf("%s from %s: state %s, stored %s, want %s", tt.action, from, state, StateOf(&product), tt.to)
+			}
+			want := model.PmsProductVerifyRecord{ProductId: 1, Action: tt.action, FromState: from, ToState: tt.to,
+				Detail: "looks good", Operator: "alice"}
+			if len(records.records) != 1 || records.records[0] != want {
+				t.Errorf("%s from %s: records = %+v, want %+v", tt.action, from, records.records, want)
+			}
+		}
+	}
+}
+
+func TestTransitionErrors(t *testing.T) {
+	products := &fakeProductModel{rows: map[int64]model.PmsProduct{1: productIn(StatePending)}}
+	wf := NewWorkflow(fakeConn{}, products, &fakeRecordModel{})
+
+	if _, err := wf.Transition(1, "delete", "alice", ""); err != ErrUnknownAction {
+		t.Errorf("unknown action: error = %v", err)
+	}
+	if _, err := wf.Transition(1, ActionReject, "alice", ""); err != ErrReasonRequired {
+		t.Errorf("reject without reason: error = %v", err)
+	}
+	if _, err := wf.Transition(2, ActionApprove, "alice", ""); err != model.ErrNotFound {
+		t.Errorf("missing product: error = %v", err)
+	}
+	product := products.rows[1]
+	if state := StateOf(&product); state != StatePending {
+		t.Errorf("state = %s, want pending", state)
+	}
+}
+
+func TestGuardedProductModel(t *testing.T) {
+	products := &fakeProductModel{rows: map[int64]model.PmsProduct{1: productIn(StatePublished)}}
+	m := NewGuardedProductModel(fakeConn{}, products)
+
+	// inserts are drafts, in or out of a transaction
+	published := productIn(StatePublished)
+	if _, err := m.Insert(published); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.TxInsert(nil, published); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range products.inserted {
+		if state := StateOf(&p); state != StateDraft {
+			t.Errorf("insert %d: state %s, want draft", i, state)
+		}
+	}
+
+	// updates keep the state stored, read locked in their transaction
+	session := &fakeSession{}
+	for _, update := range []struct {
+		name  string
+		write func(data model.PmsProduct) error
+	}{
+		{"update", m.Update},
+		{"tx update", func(data model.PmsProduct) error {
+			return m.TxUpdate(session, data)
+		}},
+	} {
+		products.locked, products.updated = nil, nil
+		draft := productIn(StateDraft)
+		draft.Name = update.name
+		if err := update.write(draft); err != nil {
+			t.Fatal(err)
+		}
+
+		product := products.rows[1]
+		if product.Name != update.name || StateOf(&product) != StatePublished {
+			t.Errorf("%s: product = %+v, want published", update.name, product)
+		}
+		if len(products.locked) != 1 || len(products.updated) != 1 || products.locked[0] == nil ||
+			products.locked[0] != products.updated[0] {
+			t.Errorf("%s: locked in %v, updated in %v, want one transaction", update.name,
+				products.locked, products.updated)
+		}
+	}
+	if products.updated[0] != session {
+		t.Errorf("tx update ran in %v, not its session", products.updated[0])
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	products := &fakeProductModel{rows: map[int64]model.PmsProduct{1: productIn(StateApproved)}}
+	records := &fakeRecordModel{}
+	schedules := &fakeScheduleModel{rows: make(map[int64]model.PmsProductPublishSchedule)}
+	s := NewScheduler(fakeConn{}, NewWorkflow(fakeConn{}, products, records), products, schedules, time.Minute)
+
+	if _, err := s.Schedule(1, ActionApprove, time.Now().Add(time.Hour), "alice"); err != ErrScheduleAction {
+		t.Errorf("schedule approve: error = %v", err)
+	}
+	if _, err := s.Schedule(1, ActionPublish, time.Now().Add(-time.Second), "alice"); err != ErrSchedulePassed {
+		t.Errorf("schedule in the past: error = %v", err)
+	}
+	if _, err := s.Schedule(2, ActionPublish, time.Now().Add(time.Hour), "alice"); err != model.ErrNotFound {
+		t.Errorf("schedule of a missing product: error = %v", err)
+	}
+
+	publish, err := s.Schedule(1, ActionPublish, time.Now().Add(time.Hour), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// published twice, the second fails
+	again, err := s.Schedule(1, ActionPublish, time.Now().Add(time.Hour), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancelled, err := s.Schedule(1, ActionUnpublish, time.Now().Add(time.Hour), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(cancelled.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(cancelled.Id); err != nil {
+		t.Errorf("cancel twice: error = %v", err)
+	}
+
+	if n, err := s.ApplyDue(); n != 0 || err != nil {
+		t.Errorf("applied %d, error %v before due", n, err)
+	}
+	for id, row := range schedules.rows {
+		row.ScheduleTime = time.Now().Add(-time.Second)
+		schedules.rows[id] = row
+	}
+	n, err := s.ApplyDue()
+	if n != 1 || err != nil {
+		t.Fatalf("applied %d, error %v, want 1", n, err)
+	}
+
+	product := products.rows[1]
+	if StateOf(&product) != StatePublished {
+		t.Errorf("state = %s, want published", StateOf(&product))
+	}
+	want := map[int64]int64{
+		publish.Id:   model.ScheduleStatusDone,
+		again.Id:     model.ScheduleStatusFailed,
+		cancelled.Id: model.ScheduleStatusCancelled,
+	}
+	for id, status := range want {
+		if schedules.rows[id].Status != status {
+			t.Errorf("schedule %d: status %d, want %d", id, schedules.rows[id].Status, status)
+		}
+	}
+	if len(records.records) != 1 || records.records[0].Operator != "alice" ||
+		records.records[0].Detail != "schedule 1" {
+		t.Errorf("records = %+v", records.records)
+	}
+	if err := s.Cancel(publish.Id); err != ErrScheduleFinished {
+		t.Errorf("cancel of an applied schedule: error = %v", err)
+	}
+}
+
+func TestSchedulerPastFailures(t *testing.T) {
+	products := &fakeProductModel{
+		rows:   map[int64]model.PmsProduct{1: productIn(StateApproved)},
+		broken: map[int64]bool{2: true},
+	}
+	schedules := &fakeScheduleModel{rows: make(map[int64]model.PmsProductPublishSchedule)}
+	s := NewScheduler(fakeConn{}, NewWorkflow(fakeConn{}, products, &fakeRecordModel{}), products, schedules,
+		time.Minute)
+
+	// a batch and more of schedules failing ahead of a good one, all due at once
+	due := time.Now().Add(-time.Second)
+	for i := 0; i <= scheduleBatch; i++ {
+		schedules.Insert(model.PmsProductPublishSchedule{ProductId: 2, Action: ActionPublish,
+			ScheduleTime: due, Status: model.ScheduleStatusPending})
+	}
+	good, _ := schedules.Insert(model.PmsProductPublishSchedule{ProductId: 1, Action: ActionPublish,
+		ScheduleTime: due, Status: model.ScheduleStatusPending})
+	goodId, _ := good.LastInsertId()
+
+	n, err := s.ApplyDue()
+	if n != 1 || err != nil {
+		t.Fatalf("applied %d, error %v, want 1", n, err)
+	}
+	if status := schedules.rows[goodId].Status; status != model.ScheduleStatusDone {
+		t.Errorf("good schedule: status %d, want %d", status, model.ScheduleStatusDone)
+	}
+	// the failing ones stay pending, to be tried again
+	for id, row := range schedules.rows {
+		if id != goodId && row.Status != model.ScheduleStatusPending {
+			t.Errorf("schedule %d: status %d, want pending", id, row.Status)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"time"
+
+	"github.com/tal-tech/go-zero/zrpc"
+)
+
+type Config struct {
+	zrpc.RpcServerConf
+	Mysql struct {
+		DataSource string
+	}
+	// Loader batches the product and SKU lookups by ID arriving within Wait
+	Loader struct {
+		Wait     time.Duration `json:",default=2ms"`
+		MaxBatch int           `json:",default=100"`
+	}
+	Pricing struct {
+		ReductionMode string `json:",default=best,options=best|repeat"`
+	}
+	// Reservation holds the stock locked by LockStock for Ttl, unless deducted
+	// or unlocked before
+	Reservation struct {
+		Ttl            time.Duration `json:",default=30m"`
+		ExpireInterval time.Duration `json:",default=10s"`
+	}
+}
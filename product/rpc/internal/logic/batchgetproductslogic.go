@@ -0,0 +1,51 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/rpc/internal/svc"
+	"malltmp/product/rpc/product"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxBatchGet = 200
+
+var errTooManyIds = status.Errorf(codes.InvalidArgument, "at most %d ids can be got at once", maxBatchGet)
+
+type BatchGetProductsLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewBatchGetProductsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BatchGetProductsLogic {
+	return &BatchGetProductsLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *BatchGetProductsLogic) BatchGetProducts(in *product.BatchGetProductsReq) (*product.BatchGetProductsResp, error) {
+	if len(in.Ids) > maxBatchGet {
+		return nil, errTooManyIds
+	}
+
+	products, missing, err := l.svcCtx.ProductModel.FindMany(in.Ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &product.BatchGetProductsResp{
+		Products:   make([]*product.Product, 0, len(products)),
+		MissingIds: missing,
+	}
+	for _, p := range products {
+		resp.Products = append(resp.Products, toProduct(p))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/rpc/internal/svc"
+	"malltmp/product/rpc/product"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type BatchGetSkusLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewBatchGetSkusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BatchGetSkusLogic {
+	return &BatchGetSkusLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *BatchGetSkusLogic) BatchGetSkus(in *product.BatchGetSkusReq) (*product.BatchGetSkusResp, error) {
+	if len(in.Ids) > maxBatchGet {
+		return nil, errTooManyIds
+	}
+
+	skus, missing, err := l.svcCtx.SkuStockModel.FindMany(in.Ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &product.BatchGetSkusResp{
+		Skus:       make([]*product.Sku, 0, len(skus)),
+		MissingIds: missing,
+	}
+	for _, sku := range skus {
+		resp.Skus = append(resp.Skus, toSku(sku))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"errors"
+	"time"
+
+	"malltmp/product/model"
+	"malltmp/product/pricing"
+	"malltmp/product/rpc/product"
+	"malltmp/product/stock"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errEmptyRefId = errors.New("ref_id is required")
+
+// rpcError gives the errors callers act upon a status code, so they can tell
+// a missing product or short stock from a failure worth retrying.
+func rpcError(err error) error {
+	switch err {
+	case model.ErrNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case model.ErrInsufficientStock, model.ErrInsufficientLockStock, stock.ErrReservationCancelled,
+		stock.ErrReservationExpired, stock.ErrReservationConfirmed:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case stock.ErrInvalidQuantity, stock.ErrReservationQuantity, errEmptyRefId, pricing.ErrInvalidQuantity,
+		pricing.ErrEmptyPreview, pricing.ErrTooManyPreview:
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
+
+func toProduct(p *model.PmsProduct) *product.Product {
+	return &product.Product{
+		Id:                p.Id,
+		Name:              p.Name,
+		SubTitle:          p.SubTitle.String,
+		Pic:               p.Pic.String,
+		ProductSn:         p.ProductSn,
+		BrandId:           p.BrandId.Int64,
+		BrandName:         p.BrandName.String,
+		ProductCategoryId: p.ProductCategoryId.Int64,
+		Price:             moneyString(p.Price),
+		PromotionPrice:    moneyString(p.PromotionPrice),
+		OriginalPrice:     moneyString(p.OriginalPrice),
+		PromotionType:     pricing.PromotionTypeAt(p, time.Now()),
+		Stock:             p.Stock.Int64,
+		LowStock:          p.LowStock.Int64,
+		Unit:              p.Unit.String,
+		Weight:            p.Weight.Float64,
+		GiftPoint:         p.GiftPoint,
+		GiftGrowth:        p.GiftGrowth,
+		UsePointLimit:     p.UsePointLimit.Int64,
+		PromotionPerLimit: p.PromotionPerLimit.Int64,
+		OnSale:            p.PublishStatus.Int64 == 1 && p.DeleteStatus.Int64 != 1,
+	}
+}
+
+func toSku(s *model.PmsSkuStock) *product.Sku {
+	available := s.Stock - s.LockStock
+	if available < 0 {
+		available = 0
+	}
+
+	return &product.Sku{
+		Id:             s.Id,
+		ProductId:      s.ProductId.Int64,
+		SkuCode:        s.SkuCode,
+		Price:          moneyString(s.Price),
+		PromotionPrice: moneyString(s.PromotionPrice),
+		Stock:          s.Stock,
+		LockStock:      s.LockStock,
+		Available:      available,
+		SpData:         s.SpData.String,
+		Pic:            s.Pic.String,
+	}
+}
+
+func moneyString(m model.NullMoney) string {
+	if !m.Valid {
+		return ""
+	}
+	return m.Money.String()
+}
@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/rpc/internal/svc"
+	"malltmp/product/rpc/product"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type DeductStockLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewDeductStockLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeductStockLogic {
+	return &DeductStockLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// DeductStock ships the stock locked for the order ref_id, when it is paid.
+// Deducting twice is a no-op.
+func (l *DeductStockLogic) DeductStock(in *product.StockReq) (*product.StockResp, error) {
+	if len(in.RefId) == 0 {
+		return nil, rpcError(errEmptyRefId)
+	}
+
+	if err := l.svcCtx.Reserver.ConfirmOrder(in.RefId, in.SkuId, in.Quantity); err != nil {
+		return nil, rpcError(err)
+	}
+
+	return &product.StockResp{}, nil
+}
@@ -0,0 +1,33 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/rpc/internal/svc"
+	"malltmp/product/rpc/product"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type GetProductLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetProductLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetProductLogic {
+	return &GetProductLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *GetProductLogic) GetProduct(in *product.GetProductReq) (*product.Product, error) {
+	p, err := l.svcCtx.ProductModel.FindOne(in.Id)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	return toProduct(p), nil
+}
@@ -0,0 +1,33 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/rpc/internal/svc"
+	"malltmp/product/rpc/product"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type GetSkuLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewGetSkuLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSkuLogic {
+	return &GetSkuLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *GetSkuLogic) GetSku(in *product.GetSkuReq) (*product.Sku, error) {
+	sku, err := l.svcCtx.SkuStockModel.FindOne(in.Id)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	return toSku(sku), nil
+}
@@ -0,0 +1,40 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/rpc/internal/svc"
+	"malltmp/product/rpc/product"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type LockStockLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewLockStockLogic(ctx context.Context, svcCtx *svc.ServiceContext) *LockStockLogic {
+	return &LockStockLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// LockStock moves available stock into lock_stock, when an order is placed.
+// The stock is reserved for the order ref_id, once however many times called,
+// and unlocked when the reservation expires.
+func (l *LockStockLogic) LockStock(in *product.StockReq) (*product.StockResp, error) {
+	if len(in.RefId) == 0 {
+		return nil, rpcError(errEmptyRefId)
+	}
+
+	_, err := l.svcCtx.Reserver.Reserve(in.RefId, in.SkuId, in.Quantity, l.svcCtx.Config.Reservation.Ttl)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	return &product.StockResp{}, nil
+}
@@ -0,0 +1,71 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/pricing"
+	"malltmp/product/rpc/internal/svc"
+	"malltmp/product/rpc/product"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type PriceQuoteLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewPriceQuoteLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PriceQuoteLogic {
+	return &PriceQuoteLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+func (l *PriceQuoteLogic) PriceQuote(in *product.PriceQuoteReq) (*product.PriceQuoteResp, error) {
+	items := make([]pricing.PreviewItem, len(in.Items))
+	for i, item := range in.Items {
+		items[i] = pricing.PreviewItem{SkuId: item.SkuId, Quantity: item.Quantity}
+	}
+
+	preview, err := l.svcCtx.Previewer.Preview(items)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	resp := &product.PriceQuoteResp{
+		Lines:      make([]*product.QuoteLine, len(preview.Lines)),
+		Subtotal:   preview.Subtotal.String(),
+		Saving:     preview.Saving.String(),
+		Total:      preview.Total.String(),
+		GiftPoint:  preview.GiftPoint,
+		GiftGrowth: preview.GiftGrowth,
+	}
+	for i, line := range preview.Lines {
+		out := &product.QuoteLine{
+			SkuId:       line.SkuId,
+			ProductId:   line.ProductId,
+			SkuCode:     line.SkuCode,
+			ProductName: line.ProductName,
+			Quantity:    line.Quantity,
+			GiftPoint:   line.GiftPoint,
+			GiftGrowth:  line.GiftGrowth,
+			Available:   line.Available,
+			InStock:     line.InStock,
+			Error:       line.Error,
+		}
+		if b := line.Breakdown; b != nil {
+			out.UnitPrice = b.UnitPrice.String()
+			out.Subtotal = b.Subtotal.String()
+			out.PromotionSaving = b.PromotionSaving.String()
+			out.LadderSaving = b.LadderSaving.String()
+			out.ReductionSaving = b.ReductionSaving.String()
+			out.Total = b.Total.String()
+		}
+		resp.Lines[i] = out
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"context"
+
+	"malltmp/product/rpc/internal/svc"
+	"malltmp/product/rpc/product"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+type UnlockStockLogic struct {
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	logx.Logger
+}
+
+func NewUnlockStockLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UnlockStockLogic {
+	return &UnlockStockLogic{
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		Logger: logx.WithContext(ctx),
+	}
+}
+
+// UnlockStock releases the stock locked for the order ref_id, when it is
+// cancelled. Unlocking twice is a no-op.
+func (l *UnlockStockLogic) UnlockStock(in *product.StockReq) (*product.StockResp, error) {
+	if len(in.RefId) == 0 {
+		return nil, rpcError(errEmptyRefId)
+	}
+
+	if err := l.svcCtx.Reserver.CancelOrder(in.RefId, in.SkuId, in.Quantity); err != nil {
+		return nil, rpcError(err)
+	}
+
+	return &product.StockResp{}, nil
+}
@@ -0,0 +1,65 @@
+// Code generated by goctl. DO NOT EDIT!
+// Source: product.proto
+
+package server
+
+import (
+	"context"
+
+	"malltmp/product/rpc/internal/logic"
+	"malltmp/product/rpc/internal/svc"
+	"malltmp/product/rpc/product"
+)
+
+type ProductServiceServer struct {
+	svcCtx *svc.ServiceContext
+}
+
+func NewProductServiceServer(svcCtx *svc.ServiceContext) *ProductServiceServer {
+	return &ProductServiceServer{
+		svcCtx: svcCtx,
+	}
+}
+
+func (s *ProductServiceServer) GetProduct(ctx context.Context, in *product.GetProductReq) (*product.Product, error) {
+	l := logic.NewGetProductLogic(ctx, s.svcCtx)
+	return l.GetProduct(in)
+}
+
+func (s *ProductServiceServer) BatchGetProducts(ctx context.Context, in *product.BatchGetProductsReq) (*product.BatchGetProductsResp, error) {
+	l := logic.NewBatchGetProductsLogic(ctx, s.svcCtx)
+	return l.BatchGetProducts(in)
+}
+
+func (s *ProductServiceServer) GetSku(ctx context.Context, in *product.GetSkuReq) (*product.Sku, error) {
+	l := logic.NewGetSkuLogic(ctx, s.svcCtx)
+	return l.GetSku(in)
+}
+
+func (s *ProductServiceServer) BatchGetSkus(ctx context.Context, in *product.BatchGetSkusReq) (*product.BatchGetSkusResp, error) {
+	l := logic.NewBatchGetSkusLogic(ctx, s.svcCtx)
+	return l.BatchGetSkus(in)
+}
+
+// LockStock moves available stock into lock_stock, when an order is placed.
+func (s *ProductServiceServer) LockStock(ctx context.Context, in *product.StockReq) (*product.StockResp, error) {
+	l := logic.NewLockStockLogic(ctx, s.svcCtx)
+	return l.LockStock(in)
+}
+
+// UnlockStock releases locked stock, when an order is cancelled.
+func (s *ProductServiceServer) UnlockStock(ctx context.Context, in *product.StockReq) (*product.StockResp, error) {
+	l := logic.NewUnlockStockLogic(ctx, s.svcCtx)
+	return l.UnlockStock(in)
+}
+
+// DeductStock ships locked stock, when an order is paid.
+func (s *ProductServiceServer) DeductStock(ctx context.Context, in *product.StockReq) (*product.StockResp, error) {
+	l := logic.NewDeductStockLogic(ctx, s.svcCtx)
+	return l.DeductStock(in)
+}
+
+func (s *ProductServiceServer) PriceQuote(ctx context.Context, in *product.PriceQuoteReq) (*product.PriceQuoteResp, error) {
+	l := logic.NewPriceQuoteLogic(ctx, s.svcCtx)
+	return l.PriceQuote(in)
+}
@@ -0,0 +1,53 @@
+package svc
+
+import (
+	"malltmp/product/audit"
+	"malltmp/product/loader"
+	"malltmp/product/model"
+	"malltmp/product/pricing"
+	"malltmp/product/rpc/internal/config"
+	"malltmp/product/stock"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+type ServiceContext struct {
+	Config             config.Config
+	ProductModel       model.PmsProductModel
+	SkuStockModel      model.PmsSkuStockModel
+	Reserver           *stock.Reserver
+	ReservationExpirer *stock.ReservationExpirer
+	Previewer          *pricing.Previewer
+}
+
+func NewServiceContext(c config.Config) *ServiceContext {
+	mode, err := pricing.ParseReductionMode(c.Pricing.ReductionMode)
+	logx.Must(err)
+
+	conn := sqlx.NewMysql(c.Mysql.DataSource)
+	recorder := audit.NewRecorder(conn, model.NewPmsAuditLogModel(conn))
+	ledgerModel := model.NewPmsSkuStockLedgerModel(conn)
+	productModel := model.NewPmsProductModel(conn)
+	skuStockModel := stock.NewRollupSkuStockModel(conn, stock.NewLedgerSkuStockModel(conn,
+		audit.NewSkuStockModel(model.NewPmsSkuStockModel(conn), recorder, audit.ActorSystem), ledgerModel),
+		productModel)
+
+	// reads of hot products and SKUs are coalesced, stock moves read their own
+	loadedProductModel := loader.NewProductModel(productModel, c.Loader.Wait, c.Loader.MaxBatch)
+	loadedSkuStockModel := loader.NewSkuStockModel(skuStockModel, c.Loader.Wait, c.Loader.MaxBatch)
+
+	// stock moves go through reservations, idempotent by order and expiring
+	reserver := stock.NewReserver(conn, stock.NewInventory(conn, skuStockModel, ledgerModel),
+		model.NewPmsStockReservationModel(conn))
+
+	return &ServiceContext{
+		Config:             c,
+		ProductModel:       loadedProductModel,
+		SkuStockModel:      loadedSkuStockModel,
+		Reserver:           reserver,
+		ReservationExpirer: stock.NewReservationExpirer(reserver, c.Reservation.ExpireInterval),
+		Previewer: pricing.NewPreviewer(pricing.Calculator{ReductionMode: mode}, loadedProductModel,
+			loadedSkuStockModel, model.NewPmsProductLadderModel(conn), model.NewPmsProductFullReductionModel(conn)),
+	}
+}
@@ -0,0 +1,98 @@
+// Code generated by goctl. DO NOT EDIT!
+// Source: product.proto
+
+//go:generate mockgen -destination ./productservice_mock.go -package productservice -source $GOFILE
+
+package productservice
+
+import (
+	"context"
+
+	"malltmp/product/rpc/product"
+
+	"github.com/tal-tech/go-zero/zrpc"
+)
+
+type (
+	PriceQuoteReq        = product.PriceQuoteReq
+	Product              = product.Product
+	Sku                  = product.Sku
+	GetProductReq        = product.GetProductReq
+	BatchGetProductsResp = product.BatchGetProductsResp
+	QuoteLine            = product.QuoteLine
+	PriceQuoteResp       = product.PriceQuoteResp
+	BatchGetProductsReq  = product.BatchGetProductsReq
+	GetSkuReq            = product.GetSkuReq
+	BatchGetSkusReq      = product.BatchGetSkusReq
+	BatchGetSkusResp     = product.BatchGetSkusResp
+	StockReq             = product.StockReq
+	StockResp            = product.StockResp
+	QuoteItem            = product.QuoteItem
+
+	ProductService interface {
+		GetProduct(ctx context.Context, in *GetProductReq) (*Product, error)
+		BatchGetProducts(ctx context.Context, in *BatchGetProductsReq) (*BatchGetProductsResp, error)
+		GetSku(ctx context.Context, in *GetSkuReq) (*Sku, error)
+		BatchGetSkus(ctx context.Context, in *BatchGetSkusReq) (*BatchGetSkusResp, error)
+		//  LockStock moves available stock into lock_stock, when an order is placed.
+		LockStock(ctx context.Context, in *StockReq) (*StockResp, error)
+		//  UnlockStock releases locked stock, when an order is cancelled.
+		UnlockStock(ctx context.Context, in *StockReq) (*StockResp, error)
+		//  DeductStock ships locked stock, when an order is paid.
+		DeductStock(ctx context.Context, in *StockReq) (*StockResp, error)
+		PriceQuote(ctx context.Context, in *PriceQuoteReq) (*PriceQuoteResp, error)
+	}
+
+	defaultProductService struct {
+		cli zrpc.Client
+	}
+)
+
+func NewProductService(cli zrpc.Client) ProductService {
+	return &defaultProductService{
+		cli: cli,
+	}
+}
+
+func (m *defaultProductService) GetProduct(ctx context.Context, in *GetProductReq) (*Product, error) {
+	client := product.NewProductServiceClient(m.cli.Conn())
+	return client.GetProduct(ctx, in)
+}
+
+func (m *defaultProductService) BatchGetProducts(ctx context.Context, in *BatchGetProductsReq) (*BatchGetProductsResp, error) {
+	client := product.NewProductServiceClient(m.cli.Conn())
+	return client.BatchGetProducts(ctx, in)
+}
+
+func (m *defaultProductService) GetSku(ctx context.Context, in *GetSkuReq) (*Sku, error) {
+	client := product.NewProductServiceClient(m.cli.Conn())
+	return client.GetSku(ctx, in)
+}
+
+func (m *defaultProductService) BatchGetSkus(ctx context.Context, in *BatchGetSkusReq) (*BatchGetSkusResp, error) {
+	client := product.NewProductServiceClient(m.cli.Conn())
+	return client.BatchGetSkus(ctx, in)
+}
+
+// LockStock moves available stock into lock_stock, when an order is placed.
+func (m *defaultProductService) LockStock(ctx context.Context, in *StockReq) (*StockResp, error) {
+	client := product.NewProductServiceClient(m.cli.Conn())
+	return client.LockStock(ctx, in)
+}
+
+// UnlockStock releases locked stock, when an order is cancelled.
+func (m *defaultProductService) UnlockStock(ctx context.Context, in *StockReq) (*StockResp, error) {
+	client := product.NewProductServiceClient(m.cli.Conn())
+	return client.UnlockStock(ctx, in)
+}
+
+// DeductStock ships locked stock, when an order is paid.
+func (m *defaultProductService) DeductStock(ctx context.Context, in *StockReq) (*StockResp, error) {
+	client := product.NewProductServiceClient(m.cli.Conn())
+	return client.DeductStock(ctx, in)
+}
+
+func (m *defaultProductService) PriceQuote(ctx context.Context, in *PriceQuoteReq) (*PriceQuoteResp, error) {
+	client := product.NewProductServiceClient(m.cli.Conn())
+	return client.PriceQuote(ctx, in)
+}
@@ -0,0 +1,275 @@
+package stock
+
+import (
+	"database/sql"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+// reasons of pms_sku_stock_ledger entries
+const (
+	ReasonRestock = "restock"
+	ReasonLock    = "lock"
+	ReasonUnlock  = "unlock"
+	ReasonDeduct  = "deduct"
+	ReasonAdjust  = "adjust"
+	ReasonRemove  = "remove"
+
+	// OperatorSystem is recorded for changes made without a known operator.
+	OperatorSystem = "system"
+)
+
+type (
+	// Change is a quantity of stock moved for a reason by an operator,
+	// referring to e.g. an order or a purchase.
+	Change struct {
+		SkuId    int64
+		Quantity int64
+		Operator string
+		RefId    string
+	}
+
+	// Inventory changes SKU stock and records every delta in the ledger,
+	// in the same transaction.
+	Inventory struct {
+		conn        sqlx.SqlConn
+		skuModel    model.PmsSkuStockModel
+		ledgerModel model.PmsSkuStockLedgerModel
+	}
+
+	// Verification compares the stock a SKU's ledger replays to with the stored one.
+	Verification struct {
+		SkuId           int64
+		Entries         int64
+		LedgerStock     int64
+		LedgerLockStock int64
+		Stock           int64
+		LockStock       int64
+	}
+
+	// ledgerSkuStockModel records the deltas of blind inserts, updates and
+	// deletes, which don't go through Inventory.
+	ledgerSkuStockModel struct {
+		model.PmsSkuStockModel
+		conn        sqlx.SqlConn
+		ledgerModel model.PmsSkuStockLedgerModel
+	}
+)
+
+func NewInventory(conn sqlx.SqlConn, skuModel model.PmsSkuStockModel,
+	ledgerModel model.PmsSkuStockLedgerModel) *Inventory {
+	return &Inventory{
+		conn:        conn,
+		skuModel:    skuModel,
+		ledgerModel: ledgerModel,
+	}
+}
+
+// Lock moves available stock into lock_stock. A nil session runs in a new
+// transaction, so do the others below.
+func (inv *Inventory) Lock(session sqlx.Session, change Change) error {
+	return inv.apply(session, change, ReasonLock, 0, change.Quantity, inv.skuModel.LockStock)
+}
+
+// Unlock releases lock_stock back to available stock.
+func (inv *Inventory) Unlock(session sqlx.Session, change Change) error {
+	return inv.apply(session, change, ReasonUnlock, 0, -change.Quantity, inv.skuModel.UnlockStock)
+}
+
+// Deduct ships locked stock.
+func (inv *Inventory) Deduct(session sqlx.Session, change Change) error {
+	return inv.apply(session, change, ReasonDeduct, -change.Quantity, -change.Quantity, inv.skuModel.DeductStock)
+}
+
+// Restock adds stock.
+func (inv *Inventory) Restock(session sqlx.Session, change Change) error {
+	return inv.apply(session, change, ReasonRestock, change.Quantity, 0, inv.skuModel.AddStock)
+}
+
+// Adjust overwrites stock and lock_stock, recording the difference.
+func (inv *Inventory) Adjust(skuId, stock, lockStock int64, operator, refId string) error {
+	return inv.conn.Transact(func(session sqlx.Session) error {
+		sku, err := inv.skuModel.TxFindOneForUpdate(session, skuId)
+		if err != nil {
+			return err
+		}
+		if sku.Stock == stock && sku.LockStock == lockStock {
+			return nil
+		}
+
+		if err := inv.skuModel.SetStock(session, skuId, stock, lockStock); err != nil {
+			return err
+		}
+		_, err = inv.ledgerModel.TxInsert(session, model.PmsSkuStockLedger{
+			SkuId:          skuId,
+			Reason:         ReasonAdjust,
+			StockDelta:     stock - sku.Stock,
+			LockStockDelta: lockStock - sku.LockStock,
+			Operator:       operatorOrSystem(operator),
+			RefId:          refId,
+		})
+		return err
+	})
+}
+
+// History pages through the ledger of a SKU, oldest first.
+func (inv *Inventory) History(skuId, lastId int64, limit int) ([]*model.PmsSkuStockLedger, error) {
+	return inv.ledgerModel.FindBySkuId(skuId, lastId, limit)
+}
+
+// Verify replays the ledger of a SKU and compares it with the stored stock.
+func (inv *Inventory) Verify(skuId int64) (*Verification, error) {
+	sku, err := inv.skuModel.FindOne(skuId)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := inv.ledgerModel.SumBySkuId(skuId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Verification{
+		SkuId:           skuId,
+		Entries:         sum.Entries,
+		LedgerStock:     sum.Stock,
+		LedgerLockStock: sum.LockStock,
+		Stock:           sku.Stock,
+		LockStock:       sku.LockStock,
+	}, nil
+}
+
+func (inv *Inventory) apply(session sqlx.Session, change Change, reason string, stockDelta, lockStockDelta int64,
+	fn func(session sqlx.Session, id, quantity int64) error) error {
+	if change.Quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+
+	return inv.transact(session, func(session sqlx.Session) error {
+		if err := fn(session, change.SkuId, change.Quantity); err != nil {
+			return err
+		}
+
+		_, err := inv.ledgerModel.TxInsert(session, model.PmsSkuStockLedger{
+			SkuId:          change.SkuId,
+			Reason:         reason,
+			StockDelta:     stockDelta,
+			LockStockDelta: lockStockDelta,
+			Operator:       operatorOrSystem(change.Operator),
+			RefId:          change.RefId,
+		})
+		return err
+	})
+}
+
+func (inv *Inventory) transact(session sqlx.Session, fn func(session sqlx.Session) error) error {
+	if session != nil {
+		return fn(session)
+	}
+
+	return inv.conn.Transact(fn)
+}
+
+// Consistent tells whether the ledger replays to the stored stock.
+func (v *Verification) Consistent() bool {
+	return v.LedgerStock == v.Stock && v.LedgerLockStock == v.LockStock
+}
+
+// NewLedgerSkuStockModel wraps skuModel to record the stock of inserted SKUs
+// as restock, the stock changed by Update as adjust and the stock of deleted
+// SKUs as remove, each in the transaction of the write.
+func NewLedgerSkuStockModel(conn sqlx.SqlConn, skuModel model.PmsSkuStockModel,
+	ledgerModel model.PmsSkuStockLedgerModel) model.PmsSkuStockModel {
+	return &ledgerSkuStockModel{
+		PmsSkuStockModel: skuModel,
+		conn:             conn,
+		ledgerModel:      ledgerModel,
+	}
+}
+
+func (m *ledgerSkuStockModel) Insert(data model.PmsSkuStock) (ret sql.Result, err error) {
+	err = m.conn.Transact(func(session sqlx.Session) error {
+		ret, err = m.TxInsert(session, data)
+		return err
+	})
+	return
+}
+
+func (m *ledgerSkuStockModel) TxInsert(session sqlx.Session, data model.PmsSkuStock) (sql.Result, error) {
+	ret, err := m.PmsSkuStockModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+	if data.Stock == 0 && data.LockStock == 0 {
+		return ret, nil
+	}
+
+	id, err := ret.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return ret, m.record(session, id, ReasonRestock, data.Stock, data.LockStock)
+}
+
+func (m *ledgerSkuStockModel) Update(data model.PmsSkuStock) error {
+	return m.conn.Transact(func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *ledgerSkuStockModel) TxUpdate(session sqlx.Session, data model.PmsSkuStock) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.TxUpdate(session, data); err != nil {
+		return err
+	}
+	if old.Stock == data.Stock && old.LockStock == data.LockStock {
+		return nil
+	}
+
+	return m.record(session, data.Id, ReasonAdjust, data.Stock-old.Stock, data.LockStock-old.LockStock)
+}
+
+func (m *ledgerSkuStockModel) Delete(id int64) error {
+	return m.conn.Transact(func(session sqlx.Session) error {
+		return m.TxDelete(session, id)
+	})
+}
+
+func (m *ledgerSkuStockModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.TxDelete(session, id); err != nil {
+		return err
+	}
+	if old.Stock == 0 && old.LockStock == 0 {
+		return nil
+	}
+
+	// the ledger of a removed SKU replays to nothing
+	return m.record(session, id, ReasonRemove, -old.Stock, -old.LockStock)
+}
+
+func (m *ledgerSkuStockModel) record(session sqlx.Session, skuId int64, reason string,
+	stockDelta, lockStockDelta int64) error {
+	_, err := m.ledgerModel.TxInsert(session, model.PmsSkuStockLedger{
+		SkuId:          skuId,
+		Reason:         reason,
+		StockDelta:     stockDelta,
+		LockStockDelta: lockStockDelta,
+		Operator:       OperatorSystem,
+	})
+	return err
+}
+
+func operatorOrSystem(operator string) string {
+	if len(operator) == 0 {
+		return OperatorSystem
+	}
+	return operator
+}
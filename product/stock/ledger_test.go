@@ -0,0 +1,84 @@
+package stock
+
+import (
+	"database/sql"
+	"testing"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+func (m *fakeSkuModel) TxFindOneForUpdate(_ sqlx.Session, id int64) (*model.PmsSkuStock, error) {
+	stock, ok := m.stock[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &model.PmsSkuStock{Id: id, Stock: stock, LockStock: m.lockStock[id]}, nil
+}
+
+func (m *fakeSkuModel) TxInsert(_ sqlx.Session, data model.PmsSkuStock) (sql.Result, error) {
+	id := int64(len(m.stock) + 1)
+	m.stock[id], m.lockStock[id] = data.Stock, data.LockStock
+	return fakeResult(id), nil
+}
+
+func (m *fakeSkuModel) TxUpdate(_ sqlx.Session, data model.PmsSkuStock) error {
+	m.stock[data.Id], m.lockStock[data.Id] = data.Stock, data.LockStock
+	return nil
+}
+
+func (m *fakeSkuModel) TxDelete(_ sqlx.Session, id int64) error {
+	delete(m.stock, id)
+	delete(m.lockStock, id)
+	return nil
+}
+
+func TestLedgerSkuStockModel(t *testing.T) {
+	skus := &fakeSkuModel{stock: make(map[int64]int64), lockStock: make(map[int64]int64)}
+	ledger := &fakeLedgerModel{}
+	m := NewLedgerSkuStockModel(fakeConn{}, skus, ledger)
+
+	if _, err := m.Insert(model.PmsSkuStock{}); err != nil {
+		t.Fatal(err)
+	}
+	ret, err := m.Insert(model.PmsSkuStock{Stock: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := ret.LastInsertId()
+	if err := m.Update(model.PmsSkuStock{Id: id, Stock: 8, LockStock: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Update(model.PmsSkuStock{Id: id, Stock: 8, LockStock: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		reason           string
+		stock, lockStock int64
+	}{
+		{ReasonRestock, 5, 0},
+		{ReasonAdjust, 3, 1},
+		{ReasonRemove, -8, -1},
+	}
+	if len(ledger.entries) != len(want) {
+		t.Fatalf("ledger = %+v, want %d entries", ledger.entries, len(want))
+	}
+	for i, w := range want {
+		e := ledger.entries[i]
+		if e.SkuId != id || e.Reason != w.reason || e.StockDelta != w.stock || e.LockStockDelta != w.lockStock {
+			t.Errorf("entry %d = %+v, want %s %d %d", i, e, w.reason, w.stock, w.lockStock)
+		}
+	}
+
+	if err := m.Delete(id); err != model.ErrNotFound {
+		t.Errorf("delete of a missing sku: error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,191 @@
+package stock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/syncx"
+	"github.com/tal-tech/go-zero/core/timex"
+)
+
+const lowStockBatch = 500
+
+// LowStockScanner periodically finds products and SKUs whose available stock
+// reached their low_stock and notifies about them. An alert is sent once and
+// not repeated until the stock recovers above the threshold.
+type LowStockScanner struct {
+	productModel model.PmsProductModel
+	skuModel     model.PmsSkuStockModel
+	notifier     Notifier
+	interval     time.Duration
+	lock         sync.Mutex
+	alerted      map[string]struct{}
+	done         *syncx.DoneChan
+}
+
+func NewLowStockScanner(productModel model.PmsProductModel, skuModel model.PmsSkuStockModel,
+	notifier Notifier, interval time.Duration) *LowStockScanner {
+	return &LowStockScanner{
+		productModel: productModel,
+		skuModel:     skuModel,
+		notifier:     notifier,
+		interval:     interval,
+		alerted:      make(map[string]struct{}),
+		done:         syncx.NewDoneChan(),
+	}
+}
+
+// Start scans every interval until Stop is called.
+func (s *LowStockScanner) Start() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Scan(context.Background()); err != nil {
+				logx.Errorf("low stock scan failed: %v", err)
+			}
+		case <-s.done.Done():
+			return
+		}
+	}
+}
+
+func (s *LowStockScanner) Stop() {
+	s.done.Close()
+}
+
+// Scan runs one pass over all products and SKUs.
+func (s *LowStockScanner) Scan(ctx context.Context) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	start := timex.Now()
+	low := make(map[string]struct{})
+	if err := s.scanProducts(ctx, low); err != nil {
+		return err
+	}
+	if err := s.scanSkus(ctx, low); err != nil {
+		return err
+	}
+
+	// whatever is no longer low has recovered, and may alert again
+	for key := range s.alerted {
+		if _, ok := low[key]; !ok {
+			delete(s.alerted, key)
+		}
+	}
+	logx.WithDuration(timex.Since(start)).Infof("low stock scan done, %d low", len(low))
+
+	return nil
+}
+
+func (s *LowStockScanner) scanProducts(ctx context.Context, low map[string]struct{}) error {
+	var lastId int64
+	for {
+		products, err := s.productModel.FindLowStock(lastId, lowStockBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, product := range products {
+			if product.DeleteStatus.Int64 == 1 {
+				continue
+			}
+
+			s.alert(ctx, low, Alert{
+				Kind:      AlertKindProduct,
+				ProductId: product.Id,
+				Name:      product.Name,
+				Available: product.Stock.Int64,
+				Threshold: product.LowStock.Int64,
+				Time:      time.Now(),
+			})
+		}
+
+		if len(products) < lowStockBatch {
+			return nil
+		}
+		lastId = products[len(products)-1].Id
+	}
+}
+
+func (s *LowStockScanner) scanSkus(ctx context.Context, low map[string]struct{}) error {
+	var lastId int64
+	for {
+		skus, err := s.skuModel.FindLowStock(lastId, lowStockBatch)
+		if err != nil {
+			return err
+		}
+
+		productIds := make([]int64, 0, len(skus))
+		for _, sku := range skus {
+			productIds = append(productIds, sku.ProductId.Int64)
+		}
+		products, _, err := s.productModel.FindMany(productIds)
+		if err != nil {
+			return err
+		}
+		productById := make(map[int64]*model.PmsProduct, len(products))
+		for _, product := range products {
+			productById[product.Id] = product
+		}
+
+		for _, sku := range skus {
+			product, ok := productById[sku.ProductId.Int64]
+			if !ok || product.DeleteStatus.Int64 == 1 {
+				continue
+			}
+
+			threshold := sku.LowStock.Int64
+			if !sku.LowStock.Valid {
+				threshold = product.LowStock.Int64
+			}
+			s.alert(ctx, low, Alert{
+				Kind:      AlertKindSku,
+				ProductId: product.Id,
+				SkuId:     sku.Id,
+				SkuCode:   sku.SkuCode,
+				Name:      product.Name,
+				Available: sku.Stock - sku.LockStock,
+				Threshold: threshold,
+				Time:      time.Now(),
+			})
+		}
+
+		if len(skus) < lowStockBatch {
+			return nil
+		}
+		lastId = skus[len(skus)-1].Id
+	}
+}
+
+func (s *LowStockScanner) alert(ctx context.Context, low map[string]struct{}, alert Alert) {
+	id := alert.ProductId
+	if alert.Kind == AlertKindSku {
+		id = alert.SkuId
+	}
+	key := alertKey(alert.Kind, id)
+	low[key] = struct{}{}
+
+	if _, ok := s.alerted[key]; ok {
+		return
+	}
+	if err := s.notifier.Notify(ctx, alert); err != nil {
+		// not marked as alerted, so the next scan retries
+		logx.WithContext(ctx).Errorf("notify low stock of %s failed: %v", key, err)
+		return
+	}
+
+	s.alerted[key] = struct{}{}
+}
+
+func alertKey(kind string, id int64) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
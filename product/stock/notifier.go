@@ -0,0 +1,117 @@
+package stock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tal-tech/go-zero/core/logx"
+)
+
+const (
+	AlertKindProduct = "product"
+	AlertKindSku     = "sku"
+
+	defaultWebhookTimeout = 3 * time.Second
+)
+
+type (
+	// Alert tells that the available stock of a product or SKU reached its low_stock.
+	Alert struct {
+		Kind      string    `json:"kind"`
+		ProductId int64     `json:"productId"`
+		SkuId     int64     `json:"skuId,omitempty"`
+		SkuCode   string    `json:"skuCode,omitempty"`
+		Name      string    `json:"name"`
+		Available int64     `json:"available"`
+		Threshold int64     `json:"threshold"`
+		Time      time.Time `json:"time"`
+	}
+
+	// Notifier delivers low-stock alerts.
+	Notifier interface {
+		Notify(ctx context.Context, alert Alert) error
+	}
+
+	logNotifier struct{}
+
+	webhookNotifier struct {
+		url    string
+		client *http.Client
+	}
+
+	multiNotifier []Notifier
+)
+
+// NewLogNotifier returns a Notifier that writes alerts to the log.
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(ctx context.Context, alert Alert) error {
+	if alert.Kind == AlertKindSku {
+		logx.WithContext(ctx).Errorf("low stock: sku %d (%s) of product %d %q, available %d, threshold %d",
+			alert.SkuId, alert.SkuCode, alert.ProductId, alert.Name, alert.Available, alert.Threshold)
+	} else {
+		logx.WithContext(ctx).Errorf("low stock: product %d %q, stock %d, threshold %d",
+			alert.ProductId, alert.Name, alert.Available, alert.Threshold)
+	}
+	return nil
+}
+
+// NewWebhookNotifier returns a Notifier that posts each alert as JSON to url.
+func NewWebhookNotifier(url string, timeout time.Duration) Notifier {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook %s responded %s", n.url, resp.Status)
+	}
+
+	return nil
+}
+
+// NewMultiNotifier returns a Notifier that delivers to all notifiers,
+// returning the first error after trying each of them.
+func NewMultiNotifier(notifiers ...Notifier) Notifier {
+	return multiNotifier(notifiers)
+}
+
+func (m multiNotifier) Notify(ctx context.Context, alert Alert) error {
+	var first error
+	for _, n := range m {
+		if err := n.Notify(ctx, alert); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,305 @@
+package stock
+
+import (
+	"errors"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+	"github.com/tal-tech/go-zero/core/syncx"
+)
+
+const (
+	expireBatch         = 100
+	operatorReservation = "reservation"
+)
+
+var (
+	ErrInvalidQuantity      = errors.New("quantity must be positive")
+	ErrReservationCancelled = errors.New("reservation is cancelled")
+	ErrReservationExpired   = errors.New("reservation is expired")
+	ErrReservationConfirmed = errors.New("reservation is already confirmed")
+	ErrReservationQuantity  = errors.New("quantity differs from the reservation's")
+)
+
+type (
+	// Reserver holds SKU stock for pending orders. Each reservation locks stock
+	// until it is confirmed (deducted), cancelled or expired (unlocked).
+	// Reserving is idempotent by order and SKU, confirming and cancelling by
+	// reservation ID.
+	Reserver struct {
+		conn             sqlx.SqlConn
+		inventory        *Inventory
+		reservationModel model.PmsStockReservationModel
+	}
+
+	// ReservationExpirer unlocks expired reservations in the background.
+	ReservationExpirer struct {
+		reserver *Reserver
+		interval time.Duration
+		done     *syncx.DoneChan
+	}
+)
+
+func NewReserver(conn sqlx.SqlConn, inventory *Inventory,
+	reservationModel model.PmsStockReservationModel) *Reserver {
+	return &Reserver{
+		conn:             conn,
+		inventory:        inventory,
+		reservationModel: reservationModel,
+	}
+}
+
+// Reserve locks quantity of the SKU for the order until ttl passes. Reserving
+// the same SKU for the same order again returns the existing reservation
+// while it holds stock, locked or confirmed; once cancelled or expired the
+// order has to be placed again, failing with ErrReservationCancelled or
+// ErrReservationExpired. Another quantity fails with ErrReservationQuantity.
+func (r *Reserver) Reserve(orderSn string, skuId, quantity int64, ttl time.Duration) (*model.PmsStockReservation, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	existing, err := r.reservationModel.FindOneByOrderSnSkuId(orderSn, skuId)
+	switch err {
+	case nil:
+		return reusable(existing, quantity)
+	case model.ErrNotFound:
+	default:
+		return nil, err
+	}
+
+	reservation := model.PmsStockReservation{
+		OrderSn:    orderSn,
+		SkuId:      skuId,
+		Quantity:   quantity,
+		Status:     model.ReservationStatusLocked,
+		ExpireTime: time.Now().Add(ttl),
+	}
+	err = r.conn.Transact(func(session sqlx.Session) error {
+		if err := r.inventory.Lock(session, reservationChange(&reservation)); err != nil {
+			return err
+		}
+
+		ret, err := r.reservationModel.TxInsert(session, reservation)
+		if err != nil {
+			return err
+		}
+		reservation.Id, err = ret.LastInsertId()
+		return err
+	})
+	if errors.Is(err, model.ErrDuplicate) {
+		// a concurrent call for the same order and SKU won the unique key
+		existing, err := r.reservationModel.FindOneByOrderSnSkuId(orderSn, skuId)
+		if err != nil {
+			return nil, err
+		}
+		return reusable(existing, quantity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// Confirm deducts the reserved stock. Confirming twice is a no-op.
+func (r *Reserver) Confirm(id int64) error {
+	var expired bool
+	err := r.conn.Transact(func(session sqlx.Session) error {
+		reservation, err := r.reservationModel.TxFindOneForUpdate(session, id)
+		if err != nil {
+			return err
+		}
+
+		switch reservation.Status {
+		case model.ReservationStatusConfirmed:
+			return nil
+		case model.ReservationStatusCancelled:
+			return ErrReservationCancelled
+		case model.ReservationStatusExpired:
+			return ErrReservationExpired
+		}
+
+		if !reservation.ExpireTime.After(time.Now()) {
+			expired = true
+			return r.unlock(session, reservation, model.ReservationStatusExpired)
+		}
+		if err := r.inventory.Deduct(session, reservationChange(reservation)); err != nil {
+			return err
+		}
+		return r.reservationModel.TxUpdateStatus(session, id, model.ReservationStatusLocked,
+			model.ReservationStatusConfirmed)
+	})
+	if err == nil && expired {
+		return ErrReservationExpired
+	}
+
+	return err
+}
+
+// Cancel unlocks the reserved stock. Cancelling twice, or after expiry, is a no-op.
+func (r *Reserver) Cancel(id int64) error {
+	return r.release(id, model.ReservationStatusCancelled)
+}
+
+// ConfirmOrder confirms the reservation of the SKU for the order, for callers
+// knowing the order and not the reservation. quantity has to be the one
+// reserved, failing with ErrReservationQuantity.
+func (r *Reserver) ConfirmOrder(orderSn string, skuId, quantity int64) error {
+	reservation, err := r.findOrder(orderSn, skuId, quantity)
+	if err != nil {
+		return err
+	}
+
+	return r.Confirm(reservation.Id)
+}
+
+// CancelOrder cancels the reservation of the SKU for the order, as ConfirmOrder confirms.
+func (r *Reserver) CancelOrder(orderSn string, skuId, quantity int64) error {
+	reservation, err := r.findOrder(orderSn, skuId, quantity)
+	if err != nil {
+		return err
+	}
+
+	return r.Cancel(reservation.Id)
+}
+
+// ExpireDue unlocks the reservations expired at now, a batch at a time,
+// returning how many. A reservation failing to expire is logged and passed,
+// not to hold back the ones after it, and tried again by the next call.
+func (r *Reserver) ExpireDue(now time.Time) (int, error) {
+	var (
+		lastId  int64
+		expired int
+	)
+	for {
+		reservations, err := r.reservationModel.FindExpired(now, lastId, expireBatch)
+		if err != nil {
+			return expired, err
+		}
+
+		for _, reservation := range reservations {
+			lastId = reservation.Id
+			if err := r.release(reservation.Id, model.ReservationStatusExpired); err != nil {
+				logx.Errorf("expire reservation %d failed: %v", reservation.Id, err)
+				continue
+			}
+			expired++
+		}
+
+		if len(reservations) < expireBatch {
+			return expired, nil
+		}
+	}
+}
+
+func (r *Reserver) release(id, status int64) error {
+	return r.conn.Transact(func(session sqlx.Session) error {
+		reservation, err := r.reservationModel.TxFindOneForUpdate(session, id)
+		if err != nil {
+			return err
+		}
+
+		switch reservation.Status {
+		case model.ReservationStatusConfirmed:
+			return ErrReservationConfirmed
+		case model.ReservationStatusCancelled, model.ReservationStatusExpired:
+			return nil
+		}
+
+		return r.unlock(session, reservation, status)
+	})
+}
+
+func (r *Reserver) unlock(session sqlx.Session, reservation *model.PmsStockReservation, status int64) error {
+	if err := r.inventory.Unlock(session, reservationChange(reservation)); err != nil {
+		return err
+	}
+
+	return r.reservationModel.TxUpdateStatus(session, reservation.Id, model.ReservationStatusLocked, status)
+}
+
+func (r *Reserver) findOrder(orderSn string, skuId, quantity int64) (*model.PmsStockReservation, error) {
+	reservation, err := r.reservationModel.FindOneByOrderSnSkuId(orderSn, skuId)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Quantity != quantity {
+		return nil, ErrReservationQuantity
+	}
+
+	return reservation, nil
+}
+
+// reusable returns the reservation of an order reserving quantity again, if it
+// still holds stock and is of quantity.
+func reusable(reservation *model.PmsStockReservation, quantity int64) (*model.PmsStockReservation, error) {
+	if reservation.Quantity != quantity {
+		return nil, ErrReservationQuantity
+	}
+
+	switch reservation.Status {
+	case model.ReservationStatusConfirmed:
+		return reservation, nil
+	case model.ReservationStatusCancelled:
+		return nil, ErrReservationCancelled
+	case model.ReservationStatusExpired:
+		return nil, ErrReservationExpired
+	}
+
+	// locked, but about to be unlocked by the expirer
+	if !reservation.ExpireTime.After(time.Now()) {
+		return nil, ErrReservationExpired
+	}
+	return reservation, nil
+}
+
+// reservationChange refers the stock a reservation moves to its order.
+func reservationChange(reservation *model.PmsStockReservation) Change {
+	return Change{
+		SkuId:    reservation.SkuId,
+		Quantity: reservation.Quantity,
+		Operator: operatorReservation,
+		RefId:    reservation.OrderSn,
+	}
+}
+
+func NewReservationExpirer(reserver *Reserver, interval time.Duration) *ReservationExpirer {
+	return &ReservationExpirer{
+		reserver: reserver,
+		interval: interval,
+		done:     syncx.NewDoneChan(),
+	}
+}
+
+// Start expires due reservations every interval until Stop is called.
+func (e *ReservationExpirer) Start() {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.expire()
+		case <-e.done.Done():
+			return
+		}
+	}
+}
+
+func (e *ReservationExpirer) Stop() {
+	e.done.Close()
+}
+
+func (e *ReservationExpirer) expire() {
+	n, err := e.reserver.ExpireDue(time.Now())
+	if err != nil {
+		logx.Errorf("expire reservations failed: %v", err)
+	}
+	if n > 0 {
+		logx.Infof("expired %d stock reservations", n)
+	}
+}
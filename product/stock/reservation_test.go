@@ -0,0 +1,366 @@
+package stock
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+var errUnlock = errors.New("unlock failed")
+
+type (
+	// fakeConn runs transactions on itself, without rolling back.
+	fakeConn struct {
+		sqlx.SqlConn
+	}
+
+	fakeResult int64
+
+	fakeReservationModel struct {
+		model.PmsStockReservationModel
+		rows     map[int64]*model.PmsStockReservation
+		lastId   int64
+		onInsert func()
+	}
+
+	fakeSkuModel struct {
+		model.PmsSkuStockModel
+		stock     map[int64]int64
+		lockStock map[int64]int64
+		// SKUs failing to unlock
+		broken map[int64]bool
+	}
+
+	fakeLedgerModel struct {
+		model.PmsSkuStockLedgerModel
+		entries []model.PmsSkuStockLedger
+	}
+)
+
+func (c fakeConn) Transact(fn func(session sqlx.Session) error) error {
+	return fn(c)
+}
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	return int64(r), nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+func (m *fakeReservationModel) FindOneByOrderSnSkuId(orderSn string, skuId int64) (*model.PmsStockReservation, error) {
+	for _, row := range m.rows {
+		if row.OrderSn == orderSn && row.SkuId == skuId {
+			r := *row
+			return &r, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *fakeReservationModel) FindExpired(now time.Time, lastId int64, limit int) ([]*model.PmsStockReservation, error) {
+	var resp []*model.PmsStockReservation
+	for _, row := range m.rows {
+		if row.Status == model.ReservationStatusLocked && !row.ExpireTime.After(now) && row.Id > lastId {
+			r := *row
+			resp = append(resp, &r)
+		}
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Id < resp[j].Id
+	})
+	if len(resp) > limit {
+		resp = resp[:limit]
+	}
+	return resp, nil
+}
+
+func (m *fakeReservationModel) TxInsert(_ sqlx.Session, data model.PmsStockReservation) (sql.Result, error) {
+	if m.onInsert != nil {
+		m.onInsert()
+	}
+	if _, err := m.FindOneByOrderSnSkuId(data.OrderSn, data.SkuId); err == nil {
+		return nil, &model.DuplicateError{Key: "uk_order_sn_sku_id"}
+	}
+
+	m.lastId++
+	data.Id = m.lastId
+	m.rows[data.Id] = &data
+	return fakeResult(data.Id), nil
+}
+
+func (m *fakeReservationModel) TxFindOneForUpdate(_ sqlx.Session, id int64) (*model.PmsStockReservation, error) {
+	row, ok := m.rows[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	r := *row
+	return &r, nil
+}
+
+func (m *fakeReservationModel) TxUpdateStatus(_ sqlx.Session, id, from, to int64) error {
+	row, ok := m.rows[id]
+	if !ok || row.Status != from {
+		return model.ErrReservationStatus
+	}
+	row.Status = to
+	return nil
+}
+
+func (m *fakeSkuModel) LockStock(_ sqlx.Session, id, quantity int64) error {
+	if m.stock[id]-m.lockStock[id] < quantity {
+		return model.ErrInsufficientStock
+	}
+	m.lockStock[id] += quantity
+	return nil
+}
+
+func (m *fakeSkuModel) UnlockStock(_ sqlx.Session, id, quantity int64) error {
+	if m.broken[id] {
+		return errUnlock
+	}
+	m.lockStock[id] -= quantity
+	return nil
+}
+
+func (m *fakeSkuModel) DeductStock(_ sqlx.Session, id, quantity int64) error {
+	m.stock[id] -= quantity
+	m.lockStock[id] -= quantity
+	return nil
+}
+
+func (m *fakeLedgerModel) TxInsert(_ sqlx.Session, data model.PmsSkuStockLedger) (sql.Result, error) {
+	m.entries = append(m.entries, data)
+	return fakeResult(len(m.entries)), nil
+}
+
+func newFakeReserver(stock map[int64]int64) (*Reserver, *fakeReservationModel, *fakeSkuModel, *fakeLedgerModel) {
+	reservations := &fakeReservationModel{rows: make(map[int64]*model.PmsStockReservation)}
+	skus := &fakeSkuModel{stock: stock, lockStock: make(map[int64]int64), broken: make(map[int64]bool)}
+	ledger := &fakeLedgerModel{}
+	conn := fakeConn{}
+	return NewReserver(conn, NewInventory(conn, skus, ledger), reservations), reservations, skus, ledger
+}
+
+func TestReserve(t *testing.T) {
+	reserver, _, skus, ledger := newFakeReserver(map[int64]int64{1: 10})
+
+	if _, err := reserver.Reserve("A", 1, 0, time.Minute); err != ErrInvalidQuantity {
+		t.Errorf("quantity 0: error = %v, want ErrInvalidQuantity", err)
+	}
+	if _, err := reserver.Reserve("A", 1, 11, time.Minute); err != model.ErrInsufficientStock {
+		t.Errorf("quantity 11: error = %v, want ErrInsufficientStock", err)
+	}
+
+	first, err := reserver.Reserve("A", 1, 3, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// reserving again locks nothing more
+	again, err := reserver.Reserve("A", 1, 3, time.Minute)
+	if err != nil || again.Id != first.Id {
+		t.Fatalf("reserve again = %+v, %v, want reservation %d", again, err, first.Id)
+	}
+	if skus.lockStock[1] != 3 || len(ledger.entries) != 1 || ledger.entries[0].Reason != ReasonLock ||
+		ledger.entries[0].RefId != "A" {
+		t.Errorf("lock stock %d, ledger %+v", skus.lockStock[1], ledger.entries)
+	}
+}
+
+func TestReserveReusesOnlyHeldReservations(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int64
+		expire time.Duration
+		err    error
+	}{
+		{"locked", model.ReservationStatusLocked, time.Minute, nil},
+		{"confirmed", model.ReservationStatusConfirmed, -time.Minute, nil},
+		{"cancelled", model.ReservationStatusCancelled, time.Minute, ErrReservationCancelled},
+		{"expired", model.ReservationStatusExpired, -time.Minute, ErrReservationExpired},
+		{"locked past expiry", model.ReservationStatusLocked, -time.Minute, ErrReservationExpired},
+	}
+	for _, tt := range tests {
+		reserver, reservations, skus, _ := newFakeReserver(map[int64]int64{1: 10})
+		reservations.lastId = 1
+		reservations.rows[1] = &model.PmsStockReservation{Id: 1, OrderSn: "A", SkuId: 1, Quantity: 2,
+			Status: tt.status, ExpireTime: time.Now().Add(tt.expire)}
+
+		got, err := reserver.Reserve("A", 1, 2, time.Minute)
+		if err != tt.err {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && got.Id != 1 {
+			t.Errorf("%s: reservation %d, want 1", tt.name, got.Id)
+		}
+		if skus.lockStock[1] != 0 {
+			t.Errorf("%s: locked %d again", tt.name, skus.lockStock[1])
+		}
+	}
+}
+
+func TestReserveConcurrentDuplicate(t *testing.T) {
+	reserver, reservations, _, _ := newFakeReserver(map[int64]int64{1: 10})
+	// another call wins the unique key, and is cancelled right away
+	reservations.onInsert = func() {
+		reservations.onInsert = nil
+		reservations.lastId++
+		reservations.rows[reservations.lastId] = &model.PmsStockReservation{Id: reservations.lastId,
+			OrderSn: "A", SkuId: 1, Quantity: 1, Status: model.ReservationStatusCancelled}
+	}
+
+	if _, err := reserver.Reserve("A", 1, 1, time.Minute); err != ErrReservationCancelled {
+		t.Errorf("error = %v, want ErrReservationCancelled", err)
+	}
+}
+
+func TestReserveOtherQuantity(t *testing.T) {
+	reserver, reservations, skus, _ := newFakeReserver(map[int64]int64{1: 10})
+	if _, err := reserver.Reserve("A", 1, 3, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reserver.Reserve("A", 1, 4, time.Minute); err != ErrReservationQuantity {
+		t.Errorf("reserve again: error = %v, want ErrReservationQuantity", err)
+	}
+	if skus.lockStock[1] != 3 {
+		t.Errorf("lock stock %d, want 3", skus.lockStock[1])
+	}
+
+	// another call of the order wins the unique key with its own quantity
+	reservations.onInsert = func() {
+		reservations.onInsert = nil
+		reservations.lastId++
+		reservations.rows[reservations.lastId] = &model.PmsStockReservation{Id: reservations.lastId,
+			OrderSn: "B", SkuId: 1, Quantity: 1, Status: model.ReservationStatusLocked,
+			ExpireTime: time.Now().Add(time.Minute)}
+	}
+	if _, err := reserver.Reserve("B", 1, 2, time.Minute); err != ErrReservationQuantity {
+		t.Errorf("concurrent reserve: error = %v, want ErrReservationQuantity", err)
+	}
+}
+
+func TestConfirmAndCancel(t *testing.T) {
+	reserver, reservations, skus, _ := newFakeReserver(map[int64]int64{1: 10})
+	confirmed, err := reserver.Reserve("A", 1, 2, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancelled, err := reserver.Reserve("B", 1, 3, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := reserver.Confirm(confirmed.Id); err != nil {
+			t.Fatalf("confirm %d: %v", i, err)
+		}
+		if err := reserver.Cancel(cancelled.Id); err != nil {
+			t.Fatalf("cancel %d: %v", i, err)
+		}
+	}
+	if skus.stock[1] != 8 || skus.lockStock[1] != 0 {
+		t.Errorf("stock %d lock stock %d, want 8 and 0", skus.stock[1], skus.lockStock[1])
+	}
+
+	if err := reserver.Cancel(confirmed.Id); err != ErrReservationConfirmed {
+		t.Errorf("cancel confirmed: error = %v, want ErrReservationConfirmed", err)
+	}
+	if err := reserver.Confirm(cancelled.Id); err != ErrReservationCancelled {
+		t.Errorf("confirm cancelled: error = %v, want ErrReservationCancelled", err)
+	}
+
+	late, err := reserver.Reserve("C", 1, 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations.rows[late.Id].ExpireTime = time.Now().Add(-time.Second)
+	if err := reserver.Confirm(late.Id); err != ErrReservationExpired {
+		t.Errorf("confirm past expiry: error = %v, want ErrReservationExpired", err)
+	}
+	if reservations.rows[late.Id].Status != model.ReservationStatusExpired || skus.lockStock[1] != 0 {
+		t.Errorf("past expiry: status %d lock stock %d", reservations.rows[late.Id].Status, skus.lockStock[1])
+	}
+}
+
+func TestExpireDuePassesFailures(t *testing.T) {
+	reserver, reservations, skus, _ := newFakeReserver(map[int64]int64{1: 1000, 2: 1000})
+	past := time.Now().Add(-time.Minute)
+	// a whole batch failing to unlock comes first
+	for i := int64(1); i <= expireBatch+20; i++ {
+		skuId := int64(2)
+		if i <= expireBatch {
+			skuId = 1
+		}
+		reservations.rows[i] = &model.PmsStockReservation{Id: i, SkuId: skuId, Quantity: 1,
+			Status: model.ReservationStatusLocked, ExpireTime: past}
+		skus.lockStock[skuId]++
+	}
+	reservations.lastId = expireBatch + 20
+	skus.broken[1] = true
+
+	n, err := reserver.ExpireDue(time.Now())
+	if err != nil || n != 20 {
+		t.Fatalf("ExpireDue = %d, %v, want 20", n, err)
+	}
+	if skus.lockStock[2] != 0 || reservations.rows[expireBatch+1].Status != model.ReservationStatusExpired {
+		t.Errorf("reservations after the failing batch not expired")
+	}
+
+	// the failures are tried again
+	skus.broken[1] = false
+	if n, err := reserver.ExpireDue(time.Now()); err != nil || n != expireBatch {
+		t.Errorf("ExpireDue again = %d, %v, want %d", n, err, expireBatch)
+	}
+	if n, err := reserver.ExpireDue(time.Now()); err != nil || n != 0 {
+		t.Errorf("ExpireDue with none due = %d, %v", n, err)
+	}
+}
+
+func TestConfirmAndCancelOrder(t *testing.T) {
+	reserver, reservations, skus, ledger := newFakeReserver(map[int64]int64{1: 10, 2: 10})
+	if _, err := reserver.Reserve("A", 1, 3, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reserver.Reserve("A", 2, 4, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reserver.ConfirmOrder("B", 1, 3); err != model.ErrNotFound {
+		t.Errorf("confirm of another order: error = %v, want ErrNotFound", err)
+	}
+	if err := reserver.ConfirmOrder("A", 1, 2); err != ErrReservationQuantity {
+		t.Errorf("confirm of another quantity: error = %v, want ErrReservationQuantity", err)
+	}
+
+	// confirming and cancelling twice move the stock once
+	for i := 0; i < 2; i++ {
+		if err := reserver.ConfirmOrder("A", 1, 3); err != nil {
+			t.Fatal(err)
+		}
+		if err := reserver.CancelOrder("A", 2, 4); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if skus.stock[1] != 7 || skus.lockStock[1] != 0 || skus.stock[2] != 10 || skus.lockStock[2] != 0 {
+		t.Errorf("stock %v, lock stock %v", skus.stock, skus.lockStock)
+	}
+	if len(ledger.entries) != 4 {
+		t.Errorf("%d ledger entries, want lock, lock, deduct and unlock", len(ledger.entries))
+	}
+	if reservations.rows[1].Status != model.ReservationStatusConfirmed ||
+		reservations.rows[2].Status != model.ReservationStatusCancelled {
+		t.Errorf("statuses %d and %d", reservations.rows[1].Status, reservations.rows[2].Status)
+	}
+
+	if err := reserver.CancelOrder("A", 1, 3); err != ErrReservationConfirmed {
+		t.Errorf("cancel of a confirmed order: error = %v, want ErrReservationConfirmed", err)
+	}
+}
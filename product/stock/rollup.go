@@ -0,0 +1,208 @@
+package stock
+
+import (
+	"database/sql"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/logx"
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+const rollupBatch = 500
+
+type (
+	// rollupSkuStockModel keeps pms_product.stock and sale equal to the
+	// totals of the product's SKUs on every SKU write, in its transaction.
+	rollupSkuStockModel struct {
+		model.PmsSkuStockModel
+		conn         sqlx.SqlConn
+		productModel model.PmsProductModel
+	}
+
+	// Reconciler recomputes the stock and sale of all products from their SKUs.
+	Reconciler struct {
+		productModel model.PmsProductModel
+	}
+)
+
+// NewRollupSkuStockModel wraps skuModel to roll up SKU stock and sale into
+// the product after each insert, update, delete and stock change, in the
+// transaction of the write. Writes outside of one run in a new one.
+func NewRollupSkuStockModel(conn sqlx.SqlConn, skuModel model.PmsSkuStockModel,
+	productModel model.PmsProductModel) model.PmsSkuStockModel {
+	return &rollupSkuStockModel{
+		PmsSkuStockModel: skuModel,
+		conn:             conn,
+		productModel:     productModel,
+	}
+}
+
+func (m *rollupSkuStockModel) Insert(data model.PmsSkuStock) (ret sql.Result, err error) {
+	err = m.conn.Transact(func(session sqlx.Session) error {
+		ret, err = m.TxInsert(session, data)
+		return err
+	})
+	return
+}
+
+func (m *rollupSkuStockModel) TxInsert(session sqlx.Session, data model.PmsSkuStock) (sql.Result, error) {
+	ret, err := m.PmsSkuStockModel.TxInsert(session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, m.rollup(session, data.ProductId)
+}
+
+func (m *rollupSkuStockModel) Update(data model.PmsSkuStock) error {
+	return m.conn.Transact(func(session sqlx.Session) error {
+		return m.TxUpdate(session, data)
+	})
+}
+
+func (m *rollupSkuStockModel) TxUpdate(session sqlx.Session, data model.PmsSkuStock) error {
+	return m.move(session, data, m.PmsSkuStockModel.TxUpdate)
+}
+
+func (m *rollupSkuStockModel) UpdateInfo(data model.PmsSkuStock) error {
+	return m.conn.Transact(func(session sqlx.Session) error {
+		return m.TxUpdateInfo(session, data)
+	})
+}
+
+// TxUpdateInfo leaves stock and sale as they are, but may move the SKU to
+// another product.
+func (m *rollupSkuStockModel) TxUpdateInfo(session sqlx.Session, data model.PmsSkuStock) error {
+	return m.move(session, data, m.PmsSkuStockModel.TxUpdateInfo)
+}
+
+func (m *rollupSkuStockModel) Delete(id int64) error {
+	return m.conn.Transact(func(session sqlx.Session) error {
+		return m.TxDelete(session, id)
+	})
+}
+
+func (m *rollupSkuStockModel) TxDelete(session sqlx.Session, id int64) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+	if err := m.PmsSkuStockModel.TxDelete(session, id); err != nil {
+		return err
+	}
+
+	return m.rollup(session, old.ProductId)
+}
+
+func (m *rollupSkuStockModel) DeductStock(session sqlx.Session, id, quantity int64) error {
+	return m.transact(session, func(session sqlx.Session) error {
+		if err := m.PmsSkuStockModel.DeductStock(session, id, quantity); err != nil {
+			return err
+		}
+
+		return m.rollupSku(session, id)
+	})
+}
+
+func (m *rollupSkuStockModel) AddStock(session sqlx.Session, id, quantity int64) error {
+	return m.transact(session, func(session sqlx.Session) error {
+		if err := m.PmsSkuStockModel.AddStock(session, id, quantity); err != nil {
+			return err
+		}
+
+		return m.rollupSku(session, id)
+	})
+}
+
+func (m *rollupSkuStockModel) SetStock(session sqlx.Session, id, stock, lockStock int64) error {
+	return m.transact(session, func(session sqlx.Session) error {
+		if err := m.PmsSkuStockModel.SetStock(session, id, stock, lockStock); err != nil {
+			return err
+		}
+
+		return m.rollupSku(session, id)
+	})
+}
+
+// move writes data by update, rolling up the product of the SKU before and
+// after the write.
+func (m *rollupSkuStockModel) move(session sqlx.Session, data model.PmsSkuStock,
+	update func(session sqlx.Session, data model.PmsSkuStock) error) error {
+	old, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := update(session, data); err != nil {
+		return err
+	}
+
+	if old.ProductId != data.ProductId {
+		if err := m.rollup(session, old.ProductId); err != nil {
+			return err
+		}
+	}
+	return m.rollup(session, data.ProductId)
+}
+
+func (m *rollupSkuStockModel) rollupSku(session sqlx.Session, id int64) error {
+	sku, err := m.PmsSkuStockModel.TxFindOneForUpdate(session, id)
+	if err != nil {
+		return err
+	}
+
+	return m.rollup(session, sku.ProductId)
+}
+
+func (m *rollupSkuStockModel) rollup(session sqlx.Session, productId sql.NullInt64) error {
+	if !productId.Valid {
+		return nil
+	}
+
+	return m.productModel.RollupSkus(session, productId.Int64)
+}
+
+func (m *rollupSkuStockModel) transact(session sqlx.Session, fn func(session sqlx.Session) error) error {
+	if session != nil {
+		return fn(session)
+	}
+
+	return m.conn.Transact(fn)
+}
+
+func NewReconciler(productModel model.PmsProductModel) *Reconciler {
+	return &Reconciler{
+		productModel: productModel,
+	}
+}
+
+// Reconcile finds the products whose stock or sale differ from their SKUs,
+// and fixes them unless dryRun is set. The differences are returned either way.
+func (r *Reconciler) Reconcile(dryRun bool) ([]*model.PmsProductRollup, error) {
+	var (
+		diffs  []*model.PmsProductRollup
+		lastId int64
+	)
+	for {
+		batch, err := r.productModel.FindRollupDiffs(lastId, rollupBatch)
+		if err != nil {
+			return diffs, err
+		}
+
+		for _, diff := range batch {
+			if !dryRun {
+				if err := r.productModel.RollupSkus(nil, diff.ProductId); err != nil {
+					return diffs, err
+				}
+				logx.Infof("rolled up product %d, stock %d -> %d, sale %d -> %d",
+					diff.ProductId, diff.Stock, diff.SkuStock, diff.Sale, diff.SkuSale)
+			}
+			diffs = append(diffs, diff)
+		}
+
+		if len(batch) < rollupBatch {
+			return diffs, nil
+		}
+		lastId = batch[len(batch)-1].ProductId
+	}
+}
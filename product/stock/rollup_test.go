@@ -0,0 +1,172 @@
+package stock
+
+import (
+	"database/sql"
+	"testing"
+
+	"malltmp/product/model"
+
+	"github.com/tal-tech/go-zero/core/stores/sqlx"
+)
+
+type (
+	// rollupConn starts transactions on sessions of their own, to tell
+	// which transaction a write and its rollup ran in.
+	rollupConn struct {
+		sqlx.SqlConn
+		sessions []sqlx.Session
+	}
+
+	rollupSession struct {
+		sqlx.Session
+		id int
+	}
+
+	rollupSkuModel struct {
+		model.PmsSkuStockModel
+		rows   map[int64]model.PmsSkuStock
+		writes []sqlx.Session
+	}
+
+	rollupProductModel struct {
+		model.PmsProductModel
+		rollups []rollup
+	}
+
+	rollup struct {
+		session   sqlx.Session
+		productId int64
+	}
+)
+
+func (c *rollupConn) Transact(fn func(session sqlx.Session) error) error {
+	session := &rollupSession{id: len(c.sessions) + 1}
+	c.sessions = append(c.sessions, session)
+	return fn(session)
+}
+
+func (m *rollupSkuModel) TxFindOneForUpdate(_ sqlx.Session, id int64) (*model.PmsSkuStock, error) {
+	row, ok := m.rows[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &row, nil
+}
+
+func (m *rollupSkuModel) TxInsert(session sqlx.Session, data model.PmsSkuStock) (sql.Result, error) {
+	m.writes = append(m.writes, session)
+	data.Id = int64(len(m.rows) + 1)
+	m.rows[data.Id] = data
+	return fakeResult(data.Id), nil
+}
+
+func (m *rollupSkuModel) TxUpdate(session sqlx.Session, data model.PmsSkuStock) error {
+	m.writes = append(m.writes, session)
+	m.rows[data.Id] = data
+	return nil
+}
+
+func (m *rollupSkuModel) TxUpdateInfo(session sqlx.Session, data model.PmsSkuStock) error {
+	return m.TxUpdate(session, data)
+}
+
+func (m *rollupSkuModel) TxDelete(session sqlx.Session, id int64) error {
+	m.writes = append(m.writes, session)
+	delete(m.rows, id)
+	return nil
+}
+
+func (m *rollupSkuModel) AddStock(session sqlx.Session, id, quantity int64) error {
+	m.writes = append(m.writes, session)
+	row := m.rows[id]
+	row.Stock += quantity
+	m.rows[id] = row
+	return nil
+}
+
+func (m *rollupProductModel) RollupSkus(session sqlx.Session, id int64) error {
+	m.rollups = append(m.rollups, rollup{session: session, productId: id})
+	return nil
+}
+
+func TestRollupSkuStockModel(t *testing.T) {
+	conn := &rollupConn{}
+	skus := &rollupSkuModel{rows: make(map[int64]model.PmsSkuStock)}
+	products := &rollupProductModel{}
+	m := NewRollupSkuStockModel(conn, skus, products)
+	product := func(id int64) sql.NullInt64 {
+		return sql.NullInt64{Int64: id, Valid: id > 0}
+	}
+	tx := &rollupSession{id: -1}
+
+	tests := []struct {
+		name  string
+		write func() error
+		// products rolled up, in the session of the write
+		want []int64
+	}{
+		{"insert", func() error {
+			_, err := m.Insert(model.PmsSkuStock{ProductId: product(1), Stock: 5})
+			return err
+		}, []int64{1}},
+		{"insert without product", func() error {
+			_, err := m.Insert(model.PmsSkuStock{Stock: 5})
+			return err
+		}, nil},
+		{"tx insert", func() error {
+			_, err := m.TxInsert(tx, model.PmsSkuStock{ProductId: product(1), Stock: 3})
+			return err
+		}, []int64{1}},
+		{"update", func() error {
+			return m.Update(model.PmsSkuStock{Id: 1, ProductId: product(1), Stock: 8})
+		}, []int64{1}},
+		{"update moving the sku", func() error {
+			return m.Update(model.PmsSkuStock{Id: 1, ProductId: product(2), Stock: 8})
+		}, []int64{1, 2}},
+		{"tx update info", func() error {
+			return m.TxUpdateInfo(tx, model.PmsSkuStock{Id: 1, ProductId: product(3)})
+		}, []int64{2, 3}},
+		{"update info", func() error {
+			return m.UpdateInfo(model.PmsSkuStock{Id: 3, ProductId: product(1)})
+		}, []int64{1}},
+		{"add stock", func() error {
+			return m.AddStock(nil, 3, 2)
+		}, []int64{1}},
+		{"tx add stock", func() error {
+			return m.AddStock(tx, 3, 2)
+		}, []int64{1}},
+		{"delete", func() error {
+			return m.Delete(3)
+		}, []int64{1}},
+		{"tx delete", func() error {
+			return m.TxDelete(tx, 1)
+		}, []int64{3}},
+	}
+	for _, tt := range tests {
+		writes, rollups, sessions := len(skus.writes), len(products.rollups), len(conn.sessions)
+		if err := tt.write(); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		session := skus.writes[len(skus.writes)-1]
+		if len(skus.writes) != writes+1 || session == nil {
+			t.Errorf("%s: written %d times, in session %v", tt.name, len(skus.writes)-writes, session)
+		}
+		// writes outside of a transaction start one, those in one stay in it
+		if started := len(conn.sessions) - sessions; started > 1 || started == 1 && session != conn.sessions[sessions] ||
+			started == 0 && session != tx {
+			t.Errorf("%s: started %d transactions, written in %v", tt.name, started, session)
+		}
+
+		got := products.rollups[rollups:]
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: rolled up %+v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i, r := range got {
+			if r.productId != tt.want[i] || r.session != session {
+				t.Errorf("%s: rollup %d = %+v, want product %d in session %v", tt.name, i, r, tt.want[i], session)
+			}
+		}
+	}
+}